@@ -43,6 +43,16 @@ func main() {
 		Handler: app.Router.SetupRoutes(),
 	}
 
+	// Analysis jobs are processed in the background by a pool of workers
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	go app.ContentAnalyserService.BackfillSignatures(workersCtx)
+	go app.ContentAnalyserService.WatchDeletions(workersCtx)
+	workersDone := make(chan struct{})
+	go func() {
+		app.AnalysisJobService.Run(workersCtx)
+		close(workersDone)
+	}()
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
@@ -63,5 +73,13 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// Interrupted jobs are put back into the queue and picked up after the restart
+	stopWorkers()
+	select {
+	case <-workersDone:
+	case <-ctx.Done():
+		log.Println("Analysis workers did not stop in time")
+	}
+
 	log.Println("Server exited gracefully")
 }
@@ -27,9 +27,61 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/analysis": {
+            "post": {
+                "description": "Queue an analysis of a file and return the job to poll. A file already queued with the same options returns its current job,\na job with other options runs after the active job of the file.\nThe plagiarism detection algorithm is full shingling by default or winnowing.\nQuotations, the trailing bibliography and the text of the assignment templates can be excluded from the uniqueness score.\nThe file is compared with the submissions to the same assignment, to the same course, to the same course in earlier years or with all files (default).\nWith code_language the file is analysed as source code: identifiers and literals are normalized and matches carry line numbers.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analysis"
+                ],
+                "summary": "Queue file analysis",
+                "parameters": [
+                    {
+                        "description": "File to analyse",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateJobRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Analysis queued",
+                        "schema": {
+                            "$ref": "#/definitions/handler.JobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "File has been deleted",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/analysis/{id}": {
             "get": {
-                "description": "Get analysis details for a specific file by its ID",
+                "description": "Get the latest analysis of a file by its ID or the given analysis version, analyses are requested with POST /analysis",
                 "consumes": [
                     "application/json"
                 ],
@@ -47,6 +99,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Analysis version, the latest by default",
+                        "name": "version",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -64,7 +122,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Not Found - Analysis not found",
+                        "description": "Not Found - File has not been analysed yet",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -76,13 +134,112 @@ const docTemplate = `{
                         }
                     }
                 }
+            },
+            "delete": {
+                "description": "Delete shingles, analyses and word cloud images of a file, repeating the request is safe",
+                "tags": [
+                    "analysis"
+                ],
+                "summary": "Delete file analysis data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Analysis data deleted"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/analysis/{id}/diff": {
+            "get": {
+                "description": "Show how the analysis of a file changed between two versions: the uniqueness change and the sources\nthat appeared, disappeared or changed their similarity. By default the latest version is compared with the previous one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analysis"
+                ],
+                "summary": "Compare analysis versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Older version, the version preceding to by default",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Newer version, the latest by default",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Difference of the versions",
+                        "schema": {
+                            "$ref": "#/definitions/analysis.VersionDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Version not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Version has no plagiarism report",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
             }
         },
         "/analysis/{id}/download": {
             "get": {
-                "description": "Download the actual analysis cloud image by its ID",
+                "description": "Download the actual analysis cloud image by its ID, as PNG or as SVG with format=svg",
                 "produces": [
-                    "application/octet-stream"
+                    "image/png",
+                    "image/svg+xml"
                 ],
                 "tags": [
                     "analysis"
@@ -95,6 +252,16 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "png",
+                            "svg"
+                        ],
+                        "type": "string",
+                        "description": "Image format",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -125,38 +292,1077 @@ const docTemplate = `{
                 }
             }
         },
-        "/info/health": {
+        "/analysis/{id}/history": {
             "get": {
-                "description": "Check if the service is up and running",
+                "description": "List every analysis version of a file, oldest first. Versions are added by POST /analysis/{id}/rerun.",
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "analysis"
+                ],
+                "summary": "Analysis history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Health check endpoint",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Analysis versions",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.AnalysisVersionResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "File has not been analysed yet",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer",
-                    "example": 404
-                },
-                "message": {
-                    "type": "string",
-                    "example": "File not found"
+        },
+        "/analysis/{id}/rerun": {
+            "post": {
+                "description": "Queue a new analysis of a file even if it has been analysed already, e.g. after new submissions or with another algorithm.\nThe result is stored as the next analysis version, the previous versions are kept in the history.\nThe body is optional and takes the same analysis options as POST /analysis. Only an active rerun with the same options is reused.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analysis"
+                ],
+                "summary": "Rerun file analysis",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Analysis options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.RerunRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Analysis queued",
+                        "schema": {
+                            "$ref": "#/definitions/handler.JobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "File has been deleted",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/assignments/{assignment_id}/templates": {
+            "get": {
+                "description": "List the files registered as templates of the assignment",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "List assignment templates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Assignment ID",
+                        "name": "assignment_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Assignment templates",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TemplatesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/assignments/{assignment_id}/templates/{file_id}": {
+            "put": {
+                "description": "Register a file uploaded to the file storing service as a template of the assignment (task text, instructor boilerplate).\nText matching a template is not scored in analyses queued with the same assignment_id. Registering the file again refreshes it.\nA template with code_language is starter code, it is only excluded from source code analyses in that language.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Register assignment template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Assignment ID",
+                        "name": "assignment_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "go",
+                            "python",
+                            "java",
+                            "c"
+                        ],
+                        "type": "string",
+                        "description": "Programming language of starter code",
+                        "name": "code_language",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Template registered"
+                    },
+                    "400": {
+                        "description": "Unknown programming language",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "File is too large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "File storing service is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Stop excluding the text of the file from analyses of the assignment, repeating the request is safe",
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Remove assignment template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Assignment ID",
+                        "name": "assignment_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File ID",
+                        "name": "file_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Template removed"
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/clusters": {
+            "post": {
+                "description": "Compute the pairwise similarity matrix of analysed files from their stored shingles and group files\nwhose similarity reaches the threshold (percent, CLUSTER_THRESHOLD by default) into clusters.\nWith format=dot the similarity graph is returned in the Graphviz DOT language.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/vnd.graphviz"
+                ],
+                "tags": [
+                    "analysis"
+                ],
+                "summary": "Similarity matrix and clusters",
+                "parameters": [
+                    {
+                        "description": "Files to cluster",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ClustersRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "dot"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Similarity matrix and clusters",
+                        "schema": {
+                            "$ref": "#/definitions/analysis.ClusterReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/compare": {
+            "get": {
+                "description": "Compare two files with each other without the rest of the corpus, nothing is stored.\nReturns similarity in both directions, Jaccard and containment scores and the matched passages with positions in both files.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analysis"
+                ],
+                "summary": "Compare two files",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First file ID",
+                        "name": "a",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Second file ID",
+                        "name": "b",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "shingles",
+                            "winnowing"
+                        ],
+                        "type": "string",
+                        "description": "Plagiarism detection algorithm",
+                        "name": "algorithm",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Comparison of the files",
+                        "schema": {
+                            "$ref": "#/definitions/analysis.Comparison"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "File is too large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "File storing service is unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "post": {
+                "description": "Receive an event of another service. Events are delivered at least once and deduplicated by ID, repeating the request is safe.\nA file.uploaded event queues the analysis of the new file, events of other types are acknowledged and skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Receive an event",
+                "parameters": [
+                    {
+                        "description": "Event",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.EventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event has already been processed"
+                    },
+                    "202": {
+                        "description": "Event accepted"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/info/health": {
+            "get": {
+                "description": "Check if the service is up and running",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check endpoint",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}": {
+            "get": {
+                "description": "Get the status of an analysis job: queued, running, done (with analysis_id), failed (with error) or cancelled (the file has been deleted)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analysis"
+                ],
+                "summary": "Get analysis job status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Analysis job",
+                        "schema": {
+                            "$ref": "#/definitions/handler.JobResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "analysis.AlgorithmInfo": {
+            "type": "object",
+            "properties": {
+                "code_language": {
+                    "description": "Язык программирования в режиме исходного кода",
+                    "type": "string"
+                },
+                "kgram_size": {
+                    "description": "Размер шингла в словах или k-граммы в символах",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "shingles или winnowing",
+                    "type": "string"
+                },
+                "window_size": {
+                    "description": "Размер окна winnowing",
+                    "type": "integer"
+                }
+            }
+        },
+        "analysis.Cluster": {
+            "type": "object",
+            "properties": {
+                "file_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_similarity": {
+                    "type": "number"
+                }
+            }
+        },
+        "analysis.ClusterReport": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "$ref": "#/definitions/analysis.AlgorithmInfo"
+                },
+                "clusters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/analysis.Cluster"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "edges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/analysis.SimilarityEdge"
+                    }
+                },
+                "file_ids": {
+                    "description": "Порядок строк и столбцов матрицы",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "matrix": {
+                    "description": "matrix[i][j] — доля шинглов file_ids[i], найденных в file_ids[j] (0-100)",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "number"
+                        }
+                    }
+                },
+                "missing_file_ids": {
+                    "description": "Файлы без шинглов выбранного алгоритма (еще не проанализированы)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "threshold": {
+                    "type": "number"
+                }
+            }
+        },
+        "analysis.Comparison": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "$ref": "#/definitions/analysis.AlgorithmInfo"
+                },
+                "common_shingles": {
+                    "description": "Количество различных общих шинглов",
+                    "type": "integer"
+                },
+                "compared_at": {
+                    "type": "string"
+                },
+                "containment_a_in_b": {
+                    "description": "Доля различных шинглов A, содержащихся в B (0-100)",
+                    "type": "number"
+                },
+                "containment_b_in_a": {
+                    "description": "Доля различных шинглов B, содержащихся в A (0-100)",
+                    "type": "number"
+                },
+                "file_a": {
+                    "type": "string"
+                },
+                "file_b": {
+                    "type": "string"
+                },
+                "jaccard": {
+                    "description": "Коэффициент Жаккара множеств шинглов (0-100)",
+                    "type": "number"
+                },
+                "passages": {
+                    "description": "document — фрагмент A, source — фрагмент B",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/analysis.MatchedPassage"
+                    }
+                },
+                "similarity_a_to_b": {
+                    "description": "Доля шинглов A, найденных в B (0-100)",
+                    "type": "number"
+                },
+                "similarity_b_to_a": {
+                    "description": "Доля шинглов B, найденных в A (0-100)",
+                    "type": "number"
+                },
+                "total_shingles_a": {
+                    "type": "integer"
+                },
+                "total_shingles_b": {
+                    "type": "integer"
+                }
+            }
+        },
+        "analysis.MatchedPassage": {
+            "type": "object",
+            "properties": {
+                "document": {
+                    "description": "Фрагмент анализируемого документа",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/analysis.TextSpan"
+                        }
+                    ]
+                },
+                "source": {
+                    "description": "Фрагмент документа-источника",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/analysis.TextSpan"
+                        }
+                    ]
+                },
+                "text": {
+                    "description": "Текст фрагмента анализируемого документа",
+                    "type": "string"
+                },
+                "word_count": {
+                    "description": "Длина фрагмента в словах",
+                    "type": "integer"
+                }
+            }
+        },
+        "analysis.SimilarityEdge": {
+            "type": "object",
+            "properties": {
+                "file_a": {
+                    "type": "string"
+                },
+                "file_b": {
+                    "type": "string"
+                },
+                "similarity": {
+                    "description": "Большее из двух направлений",
+                    "type": "number"
+                },
+                "similarity_a_to_b": {
+                    "description": "Доля шинглов A, найденных в B (0-100)",
+                    "type": "number"
+                },
+                "similarity_b_to_a": {
+                    "description": "Доля шинглов B, найденных в A (0-100)",
+                    "type": "number"
+                }
+            }
+        },
+        "analysis.SourceChange": {
+            "type": "object",
+            "properties": {
+                "delta": {
+                    "description": "Изменение схожести в процентных пунктах",
+                    "type": "number"
+                },
+                "file_id": {
+                    "type": "string"
+                },
+                "similarity_after": {
+                    "description": "0, если источник исчез в новой версии",
+                    "type": "number"
+                },
+                "similarity_before": {
+                    "description": "0, если источник появился в новой версии",
+                    "type": "number"
+                }
+            }
+        },
+        "analysis.TextSpan": {
+            "type": "object",
+            "properties": {
+                "end_line": {
+                    "description": "Последняя строка, только для исходного кода",
+                    "type": "integer"
+                },
+                "end_pos": {
+                    "description": "Конец в байтах",
+                    "type": "integer"
+                },
+                "end_rune": {
+                    "description": "Конец в символах",
+                    "type": "integer"
+                },
+                "start_line": {
+                    "description": "Первая строка, только для исходного кода",
+                    "type": "integer"
+                },
+                "start_pos": {
+                    "description": "Начало в байтах",
+                    "type": "integer"
+                },
+                "start_rune": {
+                    "description": "Начало в символах",
+                    "type": "integer"
+                }
+            }
+        },
+        "analysis.VersionDiff": {
+            "type": "object",
+            "properties": {
+                "algorithm_changed": {
+                    "description": "Версии получены разными алгоритмами, сравнивать их нужно с осторожностью",
+                    "type": "boolean"
+                },
+                "changed_sources": {
+                    "description": "Источники обеих версий с изменившейся схожестью",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/analysis.SourceChange"
+                    }
+                },
+                "file_id": {
+                    "type": "string"
+                },
+                "from_algorithm": {
+                    "$ref": "#/definitions/analysis.AlgorithmInfo"
+                },
+                "from_version": {
+                    "type": "integer"
+                },
+                "new_sources": {
+                    "description": "Источники, найденные только новой версией",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/analysis.SourceChange"
+                    }
+                },
+                "removed_sources": {
+                    "description": "Источники, которых нет в новой версии (например, удаленные файлы)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/analysis.SourceChange"
+                    }
+                },
+                "to_algorithm": {
+                    "$ref": "#/definitions/analysis.AlgorithmInfo"
+                },
+                "to_version": {
+                    "type": "integer"
+                },
+                "unchanged_sources": {
+                    "description": "Источники обеих версий с той же схожестью",
+                    "type": "integer"
+                },
+                "uniqueness_after": {
+                    "description": "Процент уникальности в новой версии",
+                    "type": "number"
+                },
+                "uniqueness_before": {
+                    "description": "Процент уникальности в старой версии",
+                    "type": "number"
+                },
+                "uniqueness_delta": {
+                    "description": "Изменение уникальности в процентных пунктах",
+                    "type": "number"
+                }
+            }
+        },
+        "handler.AnalysisVersionResponse": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string",
+                    "example": "winnowing"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T12:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "12345678-1234-1234-1234-123456789012"
+                },
+                "options": {
+                    "description": "Область поиска и исключения",
+                    "type": "string",
+                    "example": "scope=assignment,quotes=true,bibliography=false,assignment="
+                },
+                "sources": {
+                    "description": "Количество найденных источников",
+                    "type": "integer",
+                    "example": 3
+                },
+                "uniqueness_percentage": {
+                    "type": "number",
+                    "example": 87.5
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "handler.ClustersRequest": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string",
+                    "enum": [
+                        "shingles",
+                        "winnowing"
+                    ],
+                    "example": "shingles"
+                },
+                "file_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 50
+                }
+            }
+        },
+        "handler.CreateJobRequest": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string",
+                    "enum": [
+                        "shingles",
+                        "winnowing"
+                    ],
+                    "example": "winnowing"
+                },
+                "assignment_id": {
+                    "description": "Не учитывать текст шаблонов задания",
+                    "type": "string",
+                    "example": "algorithms-2024-hw1"
+                },
+                "code_language": {
+                    "description": "Анализировать файл как исходный код",
+                    "type": "string",
+                    "enum": [
+                        "go",
+                        "python",
+                        "java",
+                        "c"
+                    ],
+                    "example": "python"
+                },
+                "exclude_bibliography": {
+                    "description": "Не учитывать список литературы в уникальности",
+                    "type": "boolean",
+                    "example": true
+                },
+                "exclude_quotes": {
+                    "description": "Не учитывать цитаты в уникальности",
+                    "type": "boolean",
+                    "example": true
+                },
+                "file_id": {
+                    "type": "string",
+                    "example": "12345678-1234-1234-1234-123456789012"
+                },
+                "scope": {
+                    "description": "С какими работами сравнивать",
+                    "type": "string",
+                    "enum": [
+                        "assignment",
+                        "course",
+                        "all",
+                        "prior_years"
+                    ],
+                    "example": "assignment"
+                }
+            }
+        },
+        "handler.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "message": {
+                    "type": "string",
+                    "example": "File not found"
+                }
+            }
+        },
+        "handler.EventRequest": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T12:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "12345678-1234-1234-1234-123456789012"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "file.uploaded"
+                }
+            }
+        },
+        "handler.JobResponse": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string",
+                    "example": "winnowing"
+                },
+                "analysis_id": {
+                    "type": "string"
+                },
+                "assignment_id": {
+                    "type": "string",
+                    "example": "algorithms-2024-hw1"
+                },
+                "attempts": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "code_language": {
+                    "type": "string",
+                    "example": "python"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T12:00:00Z"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "failed to get file content"
+                },
+                "exclude_bibliography": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "exclude_quotes": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "file_id": {
+                    "type": "string",
+                    "example": "12345678-1234-1234-1234-123456789012"
+                },
+                "finished_at": {
+                    "type": "string",
+                    "example": "2023-01-01T12:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "12345678-1234-1234-1234-123456789012"
+                },
+                "max_attempts": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "next_run_at": {
+                    "type": "string",
+                    "example": "2023-01-01T12:00:00Z"
+                },
+                "rerun": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "scope": {
+                    "type": "string",
+                    "example": "assignment"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "queued"
+                }
+            }
+        },
+        "handler.RerunRequest": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string",
+                    "enum": [
+                        "shingles",
+                        "winnowing"
+                    ],
+                    "example": "winnowing"
+                },
+                "assignment_id": {
+                    "description": "Не учитывать текст шаблонов задания",
+                    "type": "string",
+                    "example": "algorithms-2024-hw1"
+                },
+                "code_language": {
+                    "description": "Анализировать файл как исходный код",
+                    "type": "string",
+                    "enum": [
+                        "go",
+                        "python",
+                        "java",
+                        "c"
+                    ],
+                    "example": "python"
+                },
+                "exclude_bibliography": {
+                    "description": "Не учитывать список литературы в уникальности",
+                    "type": "boolean",
+                    "example": true
+                },
+                "exclude_quotes": {
+                    "description": "Не учитывать цитаты в уникальности",
+                    "type": "boolean",
+                    "example": true
+                },
+                "scope": {
+                    "description": "С какими работами сравнивать",
+                    "type": "string",
+                    "enum": [
+                        "assignment",
+                        "course",
+                        "all",
+                        "prior_years"
+                    ],
+                    "example": "assignment"
+                }
+            }
+        },
+        "handler.TemplatesResponse": {
+            "type": "object",
+            "properties": {
+                "assignment_id": {
+                    "type": "string",
+                    "example": "algorithms-2024-hw1"
+                },
+                "file_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         }
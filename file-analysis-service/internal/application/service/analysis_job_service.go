@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"fileanalysisservice/internal/domain/job"
+	"fileanalysisservice/internal/domain/plagiarism"
+	"fileanalysisservice/internal/infrastructure/config"
+	"fileanalysisservice/internal/interfaces/repository"
+)
+
+// PollInterval is how often an idle worker looks for due jobs
+const PollInterval = time.Second
+
+// ErrJobNotFound is returned for unknown job IDs
+var ErrJobNotFound = errors.New("analysis job not found")
+
+// AnalysisJobService queues analyses and runs them with a bounded pool of workers.
+// Jobs are kept in the database, so queued and interrupted jobs survive restarts.
+type AnalysisJobService struct {
+	jobRepository          repository.JobRepository
+	contentAnalyserService *ContentAnalyserService
+	workers                int
+}
+
+// NewAnalysisJobService creates a new analysis job service
+func NewAnalysisJobService(jobRepository repository.JobRepository, contentAnalyserService *ContentAnalyserService, cfg *config.Config) *AnalysisJobService {
+	workers := cfg.AnalysisWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &AnalysisJobService{
+		jobRepository:          jobRepository,
+		contentAnalyserService: contentAnalyserService,
+		workers:                workers,
+	}
+}
+
+// Enqueue queues an analysis of the file with the given plagiarism detection algorithm (empty for the default one)
+// and the regions excluded from the uniqueness score, including the templates of the assignment.
+// The file is compared with the submissions of the scope only. A non-empty code language analyses the file
+// as source code in that language. If the file is already queued or being analysed with the same options,
+// that job is returned. A job with other options is queued and runs after the active job of the file.
+// A deleted file is not queued, ErrFileDeleted is returned.
+func (s *AnalysisJobService) Enqueue(ctx context.Context, fileID string, algorithm string, exclusions plagiarism.ExclusionOptions, scope job.Scope, codeLanguage string) (*job.Job, error) {
+	return s.enqueue(ctx, fileID, algorithm, exclusions, scope, codeLanguage, false)
+}
+
+// Rerun queues a new analysis of the file like Enqueue, but the cached analysis is not reused:
+// the job stores a new version of the analysis and the previous versions are kept.
+// Only an active rerun with the same options is reused.
+func (s *AnalysisJobService) Rerun(ctx context.Context, fileID string, algorithm string, exclusions plagiarism.ExclusionOptions, scope job.Scope, codeLanguage string) (*job.Job, error) {
+	return s.enqueue(ctx, fileID, algorithm, exclusions, scope, codeLanguage, true)
+}
+
+func (s *AnalysisJobService) enqueue(ctx context.Context, fileID string, algorithm string, exclusions plagiarism.ExclusionOptions, scope job.Scope, codeLanguage string, rerun bool) (*job.Job, error) {
+	err := s.contentAnalyserService.ValidateAlgorithm(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	if codeLanguage != "" && !plagiarism.IsCodeLanguage(codeLanguage) {
+		return nil, fmt.Errorf("%w: %s", plagiarism.ErrUnknownCodeLanguage, codeLanguage)
+	}
+
+	scope, err = job.ParseScope(string(scope))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.contentAnalyserService.checkNotDeleted(ctx, fileID); err != nil {
+		return nil, err
+	}
+
+	j, err := job.NewJob(fileID, algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	j.ID = uuid.New().String()
+	j.ExcludeQuotes = exclusions.Quotes
+	j.ExcludeBibliography = exclusions.Bibliography
+	j.AssignmentID = exclusions.Assignment
+	j.Scope = scope
+	j.CodeLanguage = codeLanguage
+	j.Rerun = rerun
+
+	stored, err := s.jobRepository.Store(ctx, j)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store analysis job: %w", err)
+	}
+
+	if stored {
+		log.Printf("Queued analysis job %s for file %s", j.ID, fileID)
+		return j, nil
+	}
+
+	active, err := s.jobRepository.FindActive(ctx, j)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis job: %w", err)
+	}
+
+	// The same job has finished in the meantime, queue a new one
+	if active == nil {
+		return s.enqueue(ctx, fileID, algorithm, exclusions, scope, codeLanguage, rerun)
+	}
+
+	return active, nil
+}
+
+// GetJob retrieves an analysis job
+func (s *AnalysisJobService) GetJob(ctx context.Context, id string) (*job.Job, error) {
+	j, err := s.jobRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis job: %w", err)
+	}
+
+	if j == nil {
+		return nil, ErrJobNotFound
+	}
+
+	return j, nil
+}
+
+// Run processes jobs until the context is done, then waits for the running jobs to be released
+func (s *AnalysisJobService) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx)
+		}()
+	}
+
+	log.Printf("Started %d analysis workers", s.workers)
+	wg.Wait()
+}
+
+// work claims and processes due jobs one by one, waiting PollInterval when the queue is empty
+func (s *AnalysisJobService) work(ctx context.Context) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		j, err := s.jobRepository.ClaimNext(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Failed to claim analysis job: %v", err)
+		}
+
+		if j == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(PollInterval):
+			}
+			continue
+		}
+
+		s.process(ctx, j)
+	}
+}
+
+// process runs the analysis of a claimed job and records the outcome
+func (s *AnalysisJobService) process(ctx context.Context, j *job.Job) {
+	// A job must not outlive its lease, otherwise another worker could claim it concurrently
+	jobCtx, cancel := context.WithTimeout(ctx, job.Lease)
+	defer cancel()
+
+	log.Printf("Running analysis job %s for file %s (attempt %d of %d)", j.ID, j.FileID, j.Attempts, j.MaxAttempts)
+
+	var err error
+	if j.Attempts > j.MaxAttempts {
+		// Claimed again after its lease expired too many times, e.g. the analysis keeps crashing the service
+		err = j.Fail(errors.New("analysis was interrupted too many times"), time.Now())
+	} else {
+		exclusions := plagiarism.ExclusionOptions{Quotes: j.ExcludeQuotes, Bibliography: j.ExcludeBibliography, Assignment: j.AssignmentID}
+		analysisModel, analyseErr := s.contentAnalyserService.Analyse(jobCtx, j.FileID, j.Algorithm, exclusions, j.Scope, j.CodeLanguage, j.Rerun)
+		switch {
+		case analyseErr == nil:
+			err = j.Succeed(analysisModel.ID, time.Now())
+		case errors.Is(analyseErr, ErrFileDeleted):
+			log.Printf("Analysis job %s cancelled: %v", j.ID, analyseErr)
+			err = j.Cancel(analyseErr, time.Now())
+		case ctx.Err() != nil:
+			// The service is shutting down, the attempt doesn't count
+			err = j.Release(time.Now())
+		default:
+			log.Printf("Analysis job %s failed: %v", j.ID, analyseErr)
+			err = j.Fail(analyseErr, time.Now())
+		}
+	}
+	if err != nil {
+		log.Printf("Failed to finish analysis job %s: %v", j.ID, err)
+		return
+	}
+
+	// The outcome is saved even when the service is shutting down
+	updateCtx, cancelUpdate := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancelUpdate()
+
+	if err := s.jobRepository.Update(updateCtx, j); err != nil {
+		log.Printf("Failed to update analysis job %s: %v", j.ID, err)
+	}
+}
@@ -1,16 +1,21 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fileanalysisservice/internal/infrastructure/config"
 	"fileanalysisservice/internal/infrastructure/filestoringservice"
-	"fileanalysisservice/internal/infrastructure/quickchart"
+	"fileanalysisservice/internal/interfaces/renderer"
 	"fmt"
 	"io"
 	"log"
-	"os"
 	"time"
 
+	"github.com/google/uuid"
+
 	"fileanalysisservice/internal/domain/analysis"
+	"fileanalysisservice/internal/domain/job"
 	"fileanalysisservice/internal/domain/plagiarism"
 	"fileanalysisservice/internal/infrastructure/storage/s3"
 	"fileanalysisservice/internal/interfaces/repository"
@@ -18,31 +23,130 @@ import (
 
 // ContentAnalyserService handles content-analysis-related business logic
 type ContentAnalyserService struct {
-	analysisRepository repository.AnalysisRepository
-	shingleRepository  repository.ShingleRepository
-	fileStoringService *filestoringservice.FileStoringService
-	quickChartService  *quickchart.QuickChart
-	fileStorage        *s3.FileStorage
-	plagiarismService  *plagiarism.Service
+	analysisRepository    repository.AnalysisRepository
+	shingleRepository     repository.ShingleRepository
+	signatureRepository   repository.SignatureRepository
+	deletedFileRepository repository.DeletedFileRepository
+	jobRepository         repository.JobRepository
+	fileStoringService    *filestoringservice.FileStoringService
+	wordCloudRenderer     renderer.WordCloudRenderer
+	fileStorage           *s3.FileStorage
+	plagiarismService     *plagiarism.Service
+	clusterThreshold      float64
 }
 
+// ErrVersionNotFound is returned when the file has no analysis with the requested version
+var ErrVersionNotFound = errors.New("analysis version not found")
+
+// ErrFileDeleted is returned when analysing a file that has been deleted in file-storing-service
+var ErrFileDeleted = errors.New("file has been deleted")
+
+// signatureBackfillBatchSize is the amount of documents signed per backfill iteration
+const signatureBackfillBatchSize = 100
+
+const (
+	// watchBaseBackoff is the delay before reconnecting a broken change stream, it doubles with every failure
+	watchBaseBackoff = time.Second
+	// watchMaxBackoff caps the delay between reconnects
+	watchMaxBackoff = time.Minute
+)
+
 // NewContentAnalyserService creates a new analysis service
-func NewContentAnalyserService(analysisRepository repository.AnalysisRepository, shingleRepository repository.ShingleRepository, fileStoringService *filestoringservice.FileStoringService, quickChartService *quickchart.QuickChart, storage *s3.FileStorage) *ContentAnalyserService {
+func NewContentAnalyserService(analysisRepository repository.AnalysisRepository, shingleRepository repository.ShingleRepository, signatureRepository repository.SignatureRepository, deletedFileRepository repository.DeletedFileRepository, jobRepository repository.JobRepository, fileStoringService *filestoringservice.FileStoringService, wordCloudRenderer renderer.WordCloudRenderer, storage *s3.FileStorage, cfg *config.Config) *ContentAnalyserService {
+	plagiarismService := plagiarism.NewPlagiarismService(analysisRepository, shingleRepository, signatureRepository)
+	plagiarismService.RegisterAlgorithm(plagiarism.NewWinnowing(cfg.WinnowingKGramSize, cfg.WinnowingWindowSize))
+
 	return &ContentAnalyserService{
-		analysisRepository: analysisRepository,
-		shingleRepository:  shingleRepository,
-		fileStoringService: fileStoringService,
-		quickChartService:  quickChartService,
-		fileStorage:        storage,
-		plagiarismService:  plagiarism.NewPlagiarismService(analysisRepository, shingleRepository),
+		analysisRepository:    analysisRepository,
+		shingleRepository:     shingleRepository,
+		signatureRepository:   signatureRepository,
+		deletedFileRepository: deletedFileRepository,
+		jobRepository:         jobRepository,
+		fileStoringService:    fileStoringService,
+		wordCloudRenderer:     wordCloudRenderer,
+		fileStorage:           storage,
+		plagiarismService:     plagiarismService,
+		clusterThreshold:      cfg.ClusterThreshold,
 	}
 }
 
-func (s *ContentAnalyserService) Analyse(ctx context.Context, id string) (*analysis.Analysis, error) {
-	existingAnalysis, err := s.analysisRepository.FindByID(ctx, id)
-	if err == nil && existingAnalysis != nil {
-		log.Printf("Found existing analysis with id %s", id)
-		return existingAnalysis, nil
+// algorithmKey identifies the algorithm with its parameters an analysis is made with, so that
+// an analysis is reused only for the same algorithm. Source code analyses include the language.
+func (s *ContentAnalyserService) algorithmKey(algorithm string, codeLanguage string) (string, error) {
+	if codeLanguage == "" {
+		textAlgorithm, err := s.plagiarismService.Algorithm(algorithm)
+		if err != nil {
+			return "", err
+		}
+		return textAlgorithm.Key(), nil
+	}
+
+	codeAlgorithm, err := s.plagiarismService.CodeAlgorithm(algorithm, codeLanguage)
+	if err != nil {
+		return "", err
+	}
+	return codeAlgorithm.Key() + ":" + codeLanguage, nil
+}
+
+// optionsKey identifies the search scope and exclusions an analysis is made with, so that an analysis
+// is reused only for the same request. Quotes and bibliography aren't excluded from source code.
+func optionsKey(exclusions plagiarism.ExclusionOptions, scope job.Scope, codeLanguage string) string {
+	if scope == "" {
+		scope = job.ScopeAll
+	}
+	if codeLanguage != "" {
+		exclusions.Quotes, exclusions.Bibliography = false, false
+	}
+
+	return fmt.Sprintf("scope=%s,quotes=%t,bibliography=%t,assignment=%s", scope, exclusions.Quotes, exclusions.Bibliography, exclusions.Assignment)
+}
+
+// checkNotDeleted returns ErrFileDeleted if the file has been deleted
+func (s *ContentAnalyserService) checkNotDeleted(ctx context.Context, fileID string) error {
+	deleted, err := s.deletedFileRepository.IsDeleted(ctx, fileID)
+	if err != nil {
+		return err
+	}
+	if deleted {
+		return fmt.Errorf("%w: %s", ErrFileDeleted, fileID)
+	}
+
+	return nil
+}
+
+// ValidateAlgorithm checks that the plagiarism detection algorithm is supported, empty means the default one
+func (s *ContentAnalyserService) ValidateAlgorithm(algorithm string) error {
+	_, err := s.plagiarismService.Algorithm(algorithm)
+	return err
+}
+
+// Analyse analyses the file comparing it with the submissions of the scope. The templates of the assignment
+// the file is submitted to are excluded unless the exclusion options name another assignment.
+// A non-empty code language analyses the original file as source code, quotes and bibliography aren't excluded then.
+// An existing analysis made with the same algorithm and options is returned unless rerun is set,
+// a rerun stores a new version of the analysis. A deleted file is not analysed, ErrFileDeleted is returned.
+func (s *ContentAnalyserService) Analyse(ctx context.Context, id string, algorithm string, exclusions plagiarism.ExclusionOptions, scope job.Scope, codeLanguage string, rerun bool) (*analysis.Analysis, error) {
+	algorithmKey, err := s.algorithmKey(algorithm, codeLanguage)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNotDeleted(ctx, id); err != nil {
+		return nil, err
+	}
+
+	// The key is made of the requested options, the assignment resolved from the scope isn't part of it
+	options := optionsKey(exclusions, scope, codeLanguage)
+
+	if !rerun {
+		existingAnalysis, err := s.analysisRepository.FindByFileID(ctx, id, algorithmKey, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get analysis: %w", err)
+		}
+		if existingAnalysis != nil {
+			log.Printf("Found existing analysis %s of file %s", existingAnalysis.ID, id)
+			return existingAnalysis, nil
+		}
 	}
 
 	analysisModel, err := analysis.NewAnalysis(id)
@@ -50,13 +154,28 @@ func (s *ContentAnalyserService) Analyse(ctx context.Context, id string) (*analy
 		return nil, err
 	}
 
-	content, err := s.fileStoringService.GetFileContent(id)
+	analysisModel.ID = uuid.New().String()
+	analysisModel.Algorithm = algorithmKey
+	analysisModel.Options = options
+	analysisModel.Rerun = rerun
+
+	content, err := s.fileStoringService.GetFileContent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceFileIDs, err := s.resolveScope(ctx, id, scope, &exclusions)
 	if err != nil {
 		return nil, err
 	}
 
 	log.Printf("Starting plagiarism analysis for file %s", id)
-	plagiarismReport, err := s.plagiarismService.AnalyzePlagiarism(ctx, content, id)
+	var plagiarismReport *analysis.PlagiarismReport
+	if codeLanguage != "" {
+		plagiarismReport, err = s.analyseCode(ctx, id, algorithm, codeLanguage, exclusions.Assignment, sourceFileIDs)
+	} else {
+		plagiarismReport, err = s.plagiarismService.AnalyzePlagiarism(ctx, content, id, algorithm, exclusions, sourceFileIDs)
+	}
 	if err != nil {
 		log.Printf("Failed to analyze plagiarism for file %s: %v", id, err)
 	} else {
@@ -73,51 +192,155 @@ func (s *ContentAnalyserService) Analyse(ctx context.Context, id string) (*analy
 		log.Printf("Failed to set text statistics for file %s: %v", id, err)
 	}
 
-	path, err := s.quickChartService.WordCloud(content)
-
-	if path != "" {
-		defer func(path string) {
-			err := os.Remove(path)
-			if err != nil {
-				log.Printf("failed to remove temp file: %v", err)
-			}
-		}(path)
+	wordCloud, err := s.wordCloudRenderer.WordCloud(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render word cloud: %w", err)
 	}
 
+	fileInfo, err := s.fileStorage.Upload(ctx, bytes.NewReader(wordCloud.PNG))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to upload file to storage: %w", err)
 	}
 
-	file, err := os.Open(path)
+	analysisModel.ImageKey = fileInfo.ID
+	analysisModel.ImageLocation = fileInfo.Location
+	analysisModel.UpdatedAt = time.Now()
+
+	if len(wordCloud.SVG) > 0 {
+		_, err = s.fileStorage.UploadWithKey(ctx, analysisModel.SVGImageKey(), bytes.NewReader(wordCloud.SVG))
+		if err != nil {
+			s.deleteImages(ctx, analysisModel)
+			return nil, fmt.Errorf("failed to upload SVG word cloud to storage: %w", err)
+		}
+	}
+
+	stored, err := s.analysisRepository.Store(ctx, analysisModel)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		s.deleteImages(ctx, analysisModel)
+		return nil, fmt.Errorf("failed to store analysis metadata: %w", err)
+	}
+
+	// The file may have been deleted while it was analysed, after its data had been removed
+	if err := s.checkNotDeleted(ctx, id); err != nil {
+		if errors.Is(err, ErrFileDeleted) {
+			if deleteErr := s.DeleteFileData(context.WithoutCancel(ctx), id); deleteErr != nil {
+				log.Printf("Failed to delete analysis data of deleted file %s: %v", id, deleteErr)
+			}
+		}
+		return nil, err
 	}
-	defer func(file *os.File) {
-		err := file.Close()
+
+	if !stored {
+		// Another job with the same options stored its analysis first, that one is returned
+		s.deleteImages(ctx, analysisModel)
+
+		existingAnalysis, err := s.analysisRepository.FindByFileID(ctx, id, algorithmKey, options)
 		if err != nil {
+			return nil, fmt.Errorf("failed to get analysis: %w", err)
+		}
+		if existingAnalysis == nil {
+			return nil, fmt.Errorf("analysis of file %s was stored concurrently and then removed", id)
+		}
+
+		log.Printf("Found existing analysis %s of file %s stored concurrently", existingAnalysis.ID, id)
+		return existingAnalysis, nil
+	}
+
+	return analysisModel, nil
+}
 
+// deleteImages removes the word cloud images of an analysis that couldn't be stored, failures are only logged.
+// The images are removed even if the analysis was cancelled, otherwise nothing would reference them.
+func (s *ContentAnalyserService) deleteImages(ctx context.Context, analysisModel *analysis.Analysis) {
+	ctx = context.WithoutCancel(ctx)
+
+	for _, key := range []string{analysisModel.ImageKey, analysisModel.SVGImageKey()} {
+		if err := s.fileStorage.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete word cloud image %s: %v", key, err)
 		}
-	}(file)
+	}
+}
 
-	fileInfo, err := s.fileStorage.Upload(ctx, file)
+// GetAnalysis retrieves the latest analysis of the file, nil if the file hasn't been analysed yet
+func (s *ContentAnalyserService) GetAnalysis(ctx context.Context, fileID string) (*analysis.Analysis, error) {
+	analysisModel, err := s.analysisRepository.FindByFileID(ctx, fileID, "", "")
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload file to storage: %w", err)
+		return nil, fmt.Errorf("failed to get analysis: %w", err)
 	}
 
-	analysisModel.ID = fileInfo.ID
-	analysisModel.ImageLocation = fileInfo.Location
-	analysisModel.UpdatedAt = time.Now()
+	return analysisModel, nil
+}
 
-	err = s.analysisRepository.Store(ctx, analysisModel)
+// History retrieves every analysis version of the file, oldest first
+func (s *ContentAnalyserService) History(ctx context.Context, fileID string) ([]*analysis.Analysis, error) {
+	analyses, err := s.analysisRepository.FindAllByFileID(ctx, fileID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to store analysis metadata: %w", err)
+		return nil, fmt.Errorf("failed to get analysis history: %w", err)
 	}
 
-	return analysisModel, nil
+	return analyses, nil
+}
+
+// GetAnalysisVersion retrieves the given analysis version of the file, nil if there is no such version
+func (s *ContentAnalyserService) GetAnalysisVersion(ctx context.Context, fileID string, version int) (*analysis.Analysis, error) {
+	analyses, err := s.History(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, analysisModel := range analyses {
+		if analysisModel.Version == version {
+			return analysisModel, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// DiffVersions compares two analysis versions of the file. Zero to means the latest version,
+// zero from means the version preceding to.
+func (s *ContentAnalyserService) DiffVersions(ctx context.Context, fileID string, from int, to int) (*analysis.VersionDiff, error) {
+	analyses, err := s.History(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(analyses) == 0 {
+		return nil, fmt.Errorf("%w: file %s has not been analysed", ErrVersionNotFound, fileID)
+	}
+
+	if to == 0 {
+		to = analyses[len(analyses)-1].Version
+	}
+	if from == 0 {
+		from = to - 1
+		if from < 1 {
+			return nil, fmt.Errorf("%w: version %d has no previous version", ErrVersionNotFound, to)
+		}
+	}
+
+	var fromAnalysis, toAnalysis *analysis.Analysis
+	for _, analysisModel := range analyses {
+		if analysisModel.Version == from {
+			fromAnalysis = analysisModel
+		}
+		if analysisModel.Version == to {
+			toAnalysis = analysisModel
+		}
+	}
+
+	if toAnalysis == nil {
+		return nil, fmt.Errorf("%w: %d", ErrVersionNotFound, to)
+	}
+	if fromAnalysis == nil {
+		return nil, fmt.Errorf("%w: %d", ErrVersionNotFound, from)
+	}
+
+	return analysis.Diff(fromAnalysis, toAnalysis)
 }
 
-// DownloadImage retrieves an analysis's image from storage
-func (s *ContentAnalyserService) DownloadImage(ctx context.Context, id string) (io.ReadCloser, *analysis.Analysis, error) {
+// DownloadImage retrieves an analysis's image from storage, as PNG or as SVG when svg is set
+func (s *ContentAnalyserService) DownloadImage(ctx context.Context, id string, svg bool) (io.ReadCloser, *analysis.Analysis, error) {
 	analysisModel, err := s.analysisRepository.FindByID(ctx, id)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get analysis metadata: %w", err)
@@ -127,7 +350,12 @@ func (s *ContentAnalyserService) DownloadImage(ctx context.Context, id string) (
 		return nil, nil, fmt.Errorf("file not found")
 	}
 
-	fileReader, err := s.fileStorage.Download(ctx, analysisModel.ID)
+	key := analysisModel.ImageKey
+	if svg {
+		key = analysisModel.SVGImageKey()
+	}
+
+	fileReader, err := s.fileStorage.Download(ctx, key)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to download file from storage: %w", err)
 	}
@@ -135,12 +363,229 @@ func (s *ContentAnalyserService) DownloadImage(ctx context.Context, id string) (
 	return fileReader, analysisModel, nil
 }
 
-// AnalyzePlagiarism performs plagiarism analysis on a specific file
-func (s *ContentAnalyserService) AnalyzePlagiarism(ctx context.Context, id string) (*analysis.PlagiarismReport, error) {
-	content, err := s.fileStoringService.GetFileContent(id)
+// DeleteFileData removes everything stored for a deleted file: its shingles, word cloud images and analyses.
+// The file is recorded as deleted and its jobs are cancelled first, so that nothing is analysed for it anymore.
+// Each step is idempotent, so a failed deletion can simply be repeated.
+func (s *ContentAnalyserService) DeleteFileData(ctx context.Context, fileID string) error {
+	err := s.deletedFileRepository.MarkDeleted(ctx, fileID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	err = s.jobRepository.CancelByFileID(ctx, fileID, ErrFileDeleted.Error(), time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Shingles go first so that the file stops matching other documents even if the rest fails
+	err = s.shingleRepository.DeleteShingles(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to delete shingles: %w", err)
+	}
+
+	err = s.signatureRepository.DeleteSignature(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to delete signature: %w", err)
+	}
+
+	analyses, err := s.analysisRepository.FindAllByFileID(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to get analyses: %w", err)
+	}
+
+	for _, analysisModel := range analyses {
+		for _, key := range []string{analysisModel.ImageKey, analysisModel.SVGImageKey()} {
+			err = s.fileStorage.Delete(ctx, key)
+			if err != nil {
+				return fmt.Errorf("failed to delete word cloud image: %w", err)
+			}
+		}
+	}
+
+	err = s.analysisRepository.DeleteByFileID(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to delete analyses: %w", err)
+	}
+
+	log.Printf("Deleted analysis data for file %s", fileID)
+	return nil
+}
+
+// WatchDeletions removes the data of files as soon as they are deleted in file-storing-service, until the context
+// is done. A broken stream is reconnected with backoff. Deletions missed meanwhile are still removed by
+// file-storing-service, which calls DeleteFileData until it succeeds.
+func (s *ContentAnalyserService) WatchDeletions(ctx context.Context) {
+	delay := watchBaseBackoff
+	for ctx.Err() == nil {
+		connected := time.Now()
+		err := s.fileStoringService.WatchChanges(ctx, s.handleFileChange)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A stream that worked for a while is reconnected quickly
+		if time.Since(connected) > watchMaxBackoff {
+			delay = watchBaseBackoff
+		}
+
+		log.Printf("File changes stream ended, reconnecting in %v: %v", delay, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, watchMaxBackoff)
+	}
+}
+
+// handleFileChange removes the data of a deleted file. A failure is only logged, so that the stream goes on.
+func (s *ContentAnalyserService) handleFileChange(ctx context.Context, change filestoringservice.FileChange) error {
+	if change.Kind != filestoringservice.ChangeDeleted {
+		return nil
+	}
+
+	if err := s.DeleteFileData(ctx, change.FileID); err != nil {
+		log.Printf("Failed to delete analysis data of deleted file %s: %v", change.FileID, err)
+	}
+
+	return nil
+}
+
+// BackfillSignatures computes MinHash signatures of files analysed before signatures were introduced.
+// It runs until every file is signed or the context is cancelled.
+func (s *ContentAnalyserService) BackfillSignatures(ctx context.Context) {
+	total := 0
+	for ctx.Err() == nil {
+		signed, err := s.plagiarismService.BackfillSignatures(ctx, signatureBackfillBatchSize)
+		total += signed
+		if err != nil {
+			log.Printf("Failed to backfill signatures: %v", err)
+			return
+		}
+
+		if signed < signatureBackfillBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("Computed signatures for %d previously analysed files", total)
+	}
+}
+
+// Compare compares two files directly with the given algorithm, nothing is stored
+func (s *ContentAnalyserService) Compare(ctx context.Context, fileA, fileB string, algorithm string) (*analysis.Comparison, error) {
+	err := s.ValidateAlgorithm(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	contentA, err := s.fileStoringService.GetFileContent(ctx, fileA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file content: %w", err)
+	}
+
+	contentB, err := s.fileStoringService.GetFileContent(ctx, fileB)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get file content: %w", err)
 	}
 
-	return s.plagiarismService.AnalyzePlagiarism(ctx, content, id)
+	return s.plagiarismService.Compare(fileA, contentA, fileB, contentB, algorithm)
+}
+
+// SimilarityClusters computes the similarity matrix of analysed files and groups them into clusters.
+// A nil threshold means the configured one.
+func (s *ContentAnalyserService) SimilarityClusters(ctx context.Context, fileIDs []string, algorithm string, threshold *float64) (*analysis.ClusterReport, error) {
+	clusterThreshold := s.clusterThreshold
+	if threshold != nil {
+		clusterThreshold = *threshold
+	}
+
+	return s.plagiarismService.SimilarityClusters(ctx, fileIDs, algorithm, clusterThreshold)
+}
+
+// AnalyzePlagiarism performs plagiarism analysis on a specific file, as source code if the code language is set
+func (s *ContentAnalyserService) AnalyzePlagiarism(ctx context.Context, id string, algorithm string, exclusions plagiarism.ExclusionOptions, scope job.Scope, codeLanguage string) (*analysis.PlagiarismReport, error) {
+	sourceFileIDs, err := s.resolveScope(ctx, id, scope, &exclusions)
+	if err != nil {
+		return nil, err
+	}
+
+	if codeLanguage != "" {
+		return s.analyseCode(ctx, id, algorithm, codeLanguage, exclusions.Assignment, sourceFileIDs)
+	}
+
+	content, err := s.fileStoringService.GetFileContent(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file content: %w", err)
+	}
+
+	return s.plagiarismService.AnalyzePlagiarism(ctx, content, id, algorithm, exclusions, sourceFileIDs)
+}
+
+// analyseCode analyses the original file as source code, the text rendition loses its indentation and line breaks
+func (s *ContentAnalyserService) analyseCode(ctx context.Context, id string, algorithm string, codeLanguage string, assignmentID string, sourceFileIDs []string) (*analysis.PlagiarismReport, error) {
+	source, err := s.fileStoringService.GetFileSource(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file source: %w", err)
+	}
+
+	return s.plagiarismService.AnalyzeCode(ctx, source, id, algorithm, codeLanguage, assignmentID, sourceFileIDs)
+}
+
+// resolveScope asks file-storing-service for the files of the scope, nil means all files.
+// The assignment the file is submitted to becomes the default source of templates.
+func (s *ContentAnalyserService) resolveScope(ctx context.Context, id string, scope job.Scope, exclusions *plagiarism.ExclusionOptions) ([]string, error) {
+	if scope == "" {
+		scope = job.ScopeAll
+	}
+
+	scopeFiles, err := s.fileStoringService.GetScope(ctx, id, string(scope))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve search scope: %w", err)
+	}
+
+	if exclusions.Assignment == "" {
+		exclusions.Assignment = scopeFiles.AssignmentID
+	}
+
+	return scopeFiles.FileIDs, nil
+}
+
+// RegisterTemplate registers a stored file as a template of the assignment: its text is not scored
+// in analyses of the assignment submissions. Registering the same file again refreshes its fingerprints.
+// A template with a code language is starter code excluded from source code analyses in that language.
+func (s *ContentAnalyserService) RegisterTemplate(ctx context.Context, assignmentID string, fileID string, codeLanguage string) error {
+	if codeLanguage != "" && !plagiarism.IsCodeLanguage(codeLanguage) {
+		return fmt.Errorf("%w: %s", plagiarism.ErrUnknownCodeLanguage, codeLanguage)
+	}
+
+	var content string
+	var err error
+	if codeLanguage != "" {
+		content, err = s.fileStoringService.GetFileSource(ctx, fileID)
+	} else {
+		content, err = s.fileStoringService.GetFileContent(ctx, fileID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get file content: %w", err)
+	}
+
+	err = s.plagiarismService.RegisterTemplate(ctx, assignmentID, fileID, content, codeLanguage)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Registered file %s as a template of assignment %s", fileID, assignmentID)
+	return nil
+}
+
+// Templates returns the IDs of the template files of the assignment
+func (s *ContentAnalyserService) Templates(ctx context.Context, assignmentID string) ([]string, error) {
+	return s.plagiarismService.Templates(ctx, assignmentID)
+}
+
+// RemoveTemplate stops excluding the text of a template file from analyses of the assignment submissions
+func (s *ContentAnalyserService) RemoveTemplate(ctx context.Context, assignmentID string, fileID string) error {
+	return s.plagiarismService.RemoveTemplate(ctx, assignmentID, fileID)
 }
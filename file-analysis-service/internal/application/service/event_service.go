@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"fileanalysisservice/internal/domain/event"
+	"fileanalysisservice/internal/domain/job"
+	"fileanalysisservice/internal/domain/plagiarism"
+	"fileanalysisservice/internal/interfaces/repository"
+)
+
+// EventService consumes the events of file-storing-service. Every uploaded file is analysed right away,
+// so its shingles are indexed before later submissions are compared with it.
+type EventService struct {
+	eventRepository    repository.EventRepository
+	analysisJobService *AnalysisJobService
+}
+
+// NewEventService creates a new event service
+func NewEventService(eventRepository repository.EventRepository, analysisJobService *AnalysisJobService) *EventService {
+	return &EventService{
+		eventRepository:    eventRepository,
+		analysisJobService: analysisJobService,
+	}
+}
+
+// Handle processes an event once, a redelivered event is ignored. Events of unknown types are acknowledged
+// and skipped, so that new events of file-storing-service don't block the delivery of the others.
+// Returns false if the event has been handled before.
+func (s *EventService) Handle(ctx context.Context, e *event.Event) (bool, error) {
+	if err := e.Validate(); err != nil {
+		return false, err
+	}
+
+	processed, err := s.eventRepository.IsProcessed(ctx, e.ID)
+	if err != nil {
+		return false, err
+	}
+	if processed {
+		return false, nil
+	}
+
+	switch e.Type {
+	case event.TypeFileUploaded:
+		err = s.handleFileUploaded(ctx, e)
+	default:
+		log.Printf("Skipping event %s of unknown type %s", e.ID, e.Type)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// An event handled concurrently is queued once, the active job of the file is reused
+	return true, s.eventRepository.MarkProcessed(ctx, e.ID, e.Type, time.Now())
+}
+
+// handleFileUploaded queues the default analysis of the new file against all files.
+// A file deleted before its event was delivered is skipped.
+func (s *EventService) handleFileUploaded(ctx context.Context, e *event.Event) error {
+	payload, err := e.FileUploaded()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.analysisJobService.Enqueue(ctx, payload.FileID, "", plagiarism.ExclusionOptions{}, job.ScopeAll, "")
+	if errors.Is(err, ErrFileDeleted) {
+		log.Printf("Skipping event %s of deleted file %s", e.ID, payload.FileID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to queue analysis of uploaded file: %w", err)
+	}
+
+	return nil
+}
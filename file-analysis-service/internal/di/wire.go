@@ -5,7 +5,7 @@ package di
 
 import (
 	"fileanalysisservice/internal/infrastructure/filestoringservice"
-	"fileanalysisservice/internal/infrastructure/quickchart"
+	"fileanalysisservice/internal/infrastructure/wordcloud"
 	"fileanalysisservice/internal/interfaces/repository"
 
 	"github.com/google/wire"
@@ -22,8 +22,16 @@ import (
 var RepositorySet = wire.NewSet(
 	postgres.NewAnalysisRepository,
 	wire.Bind(new(repository.AnalysisRepository), new(*postgres.AnalysisRepository)),
+	postgres.NewJobRepository,
+	wire.Bind(new(repository.JobRepository), new(*postgres.JobRepository)),
 	postgres.NewShingleRepository,
 	wire.Bind(new(repository.ShingleRepository), new(*postgres.ShingleRepository)),
+	postgres.NewSignatureRepository,
+	wire.Bind(new(repository.SignatureRepository), new(*postgres.SignatureRepository)),
+	postgres.NewEventRepository,
+	wire.Bind(new(repository.EventRepository), new(*postgres.EventRepository)),
+	postgres.NewDeletedFileRepository,
+	wire.Bind(new(repository.DeletedFileRepository), new(*postgres.DeletedFileRepository)),
 )
 
 // InitializeApplication wires up all the dependencies
@@ -37,7 +45,7 @@ func InitializeApplication() (*Application, error) {
 
 		// External Services.
 		filestoringservice.NewFileStoringService,
-		quickchart.NewQuickChart,
+		wordcloud.NewBackend,
 
 		// Repositories.
 		RepositorySet,
@@ -47,9 +55,14 @@ func InitializeApplication() (*Application, error) {
 
 		// Services.
 		service.NewContentAnalyserService,
+		service.NewAnalysisJobService,
+		service.NewEventService,
 
 		// Handlers.
 		handler.NewAnalysisHandler,
+		handler.NewJobHandler,
+		handler.NewTemplateHandler,
+		handler.NewEventHandler,
 		handler.NewInfoHandler,
 		handler.NewDocsHandler,
 
@@ -65,14 +78,18 @@ func InitializeApplication() (*Application, error) {
 
 // Application is the main application container
 type Application struct {
-	Router *router.Router
-	Config *config.Config
+	Router                 *router.Router
+	Config                 *config.Config
+	AnalysisJobService     *service.AnalysisJobService
+	ContentAnalyserService *service.ContentAnalyserService
 }
 
 // NewApplication creates a new application
-func NewApplication(router *router.Router, config *config.Config) *Application {
+func NewApplication(router *router.Router, config *config.Config, analysisJobService *service.AnalysisJobService, contentAnalyserService *service.ContentAnalyserService) *Application {
 	return &Application{
-		Router: router,
-		Config: config,
+		Router:                 router,
+		Config:                 config,
+		AnalysisJobService:     analysisJobService,
+		ContentAnalyserService: contentAnalyserService,
 	}
 }
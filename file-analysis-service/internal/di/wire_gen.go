@@ -11,8 +11,8 @@ import (
 	"fileanalysisservice/internal/infrastructure/config"
 	"fileanalysisservice/internal/infrastructure/filestoringservice"
 	"fileanalysisservice/internal/infrastructure/persistence/postgres"
-	"fileanalysisservice/internal/infrastructure/quickchart"
 	"fileanalysisservice/internal/infrastructure/storage/s3"
+	"fileanalysisservice/internal/infrastructure/wordcloud"
 	"fileanalysisservice/internal/interfaces/api/handler"
 	"fileanalysisservice/internal/interfaces/api/router"
 	"fileanalysisservice/internal/interfaces/repository"
@@ -33,36 +33,55 @@ func InitializeApplication() (*Application, error) {
 	}
 	analysisRepository := postgres.NewAnalysisRepository(db)
 	shingleRepository := postgres.NewShingleRepository(db)
-	fileStoringService := filestoringservice.NewFileStoringService(configConfig)
-	quickChart := quickchart.NewQuickChart(configConfig)
+	signatureRepository := postgres.NewSignatureRepository(db)
+	deletedFileRepository := postgres.NewDeletedFileRepository(db)
+	jobRepository := postgres.NewJobRepository(db)
+	fileStoringService, err := filestoringservice.NewFileStoringService(configConfig)
+	if err != nil {
+		return nil, err
+	}
+	wordCloudRenderer, err := wordcloud.NewBackend(configConfig)
+	if err != nil {
+		return nil, err
+	}
 	fileStorage, err := s3.NewFileStorage(configConfig)
 	if err != nil {
 		return nil, err
 	}
-	contentAnalyserService := service.NewContentAnalyserService(analysisRepository, shingleRepository, fileStoringService, quickChart, fileStorage)
+	contentAnalyserService := service.NewContentAnalyserService(analysisRepository, shingleRepository, signatureRepository, deletedFileRepository, jobRepository, fileStoringService, wordCloudRenderer, fileStorage, configConfig)
 	analyseHandler := handler.NewAnalysisHandler(contentAnalyserService)
+	analysisJobService := service.NewAnalysisJobService(jobRepository, contentAnalyserService, configConfig)
+	jobHandler := handler.NewJobHandler(analysisJobService)
+	templateHandler := handler.NewTemplateHandler(contentAnalyserService)
+	eventRepository := postgres.NewEventRepository(db)
+	eventService := service.NewEventService(eventRepository, analysisJobService)
+	eventHandler := handler.NewEventHandler(eventService)
 	infoHandler := handler.NewInfoHandler()
 	docsHandler := handler.NewDocsHandler()
-	routerRouter := router.NewRouter(analyseHandler, infoHandler, docsHandler)
-	application := NewApplication(routerRouter, configConfig)
+	routerRouter := router.NewRouter(analyseHandler, jobHandler, templateHandler, eventHandler, infoHandler, docsHandler)
+	application := NewApplication(routerRouter, configConfig, analysisJobService, contentAnalyserService)
 	return application, nil
 }
 
 // wire.go:
 
 // RepositorySet provides repository implementations
-var RepositorySet = wire.NewSet(postgres.NewAnalysisRepository, wire.Bind(new(repository.AnalysisRepository), new(*postgres.AnalysisRepository)), postgres.NewShingleRepository, wire.Bind(new(repository.ShingleRepository), new(*postgres.ShingleRepository)))
+var RepositorySet = wire.NewSet(postgres.NewAnalysisRepository, wire.Bind(new(repository.AnalysisRepository), new(*postgres.AnalysisRepository)), postgres.NewJobRepository, wire.Bind(new(repository.JobRepository), new(*postgres.JobRepository)), postgres.NewShingleRepository, wire.Bind(new(repository.ShingleRepository), new(*postgres.ShingleRepository)), postgres.NewSignatureRepository, wire.Bind(new(repository.SignatureRepository), new(*postgres.SignatureRepository)), postgres.NewEventRepository, wire.Bind(new(repository.EventRepository), new(*postgres.EventRepository)), postgres.NewDeletedFileRepository, wire.Bind(new(repository.DeletedFileRepository), new(*postgres.DeletedFileRepository)))
 
 // Application is the main application container
 type Application struct {
-	Router *router.Router
-	Config *config.Config
+	Router                 *router.Router
+	Config                 *config.Config
+	AnalysisJobService     *service.AnalysisJobService
+	ContentAnalyserService *service.ContentAnalyserService
 }
 
 // NewApplication creates a new application
-func NewApplication(router2 *router.Router, config2 *config.Config) *Application {
+func NewApplication(router2 *router.Router, config2 *config.Config, analysisJobService *service.AnalysisJobService, contentAnalyserService *service.ContentAnalyserService) *Application {
 	return &Application{
-		Router: router2,
-		Config: config2,
+		Router:                 router2,
+		Config:                 config2,
+		AnalysisJobService:     analysisJobService,
+		ContentAnalyserService: contentAnalyserService,
 	}
 }
@@ -8,34 +8,109 @@ import (
 
 // PlagiarismMatch represents a single plagiarism match
 type PlagiarismMatch struct {
-	Source      string  `json:"source"`     // URL или название источника
-	Similarity  float64 `json:"similarity"` // Процент схожести (0-100)
-	MatchedText string  `json:"matched_text"`
-	StartPos    int     `json:"start_pos"`
-	EndPos      int     `json:"end_pos"`
+	FileID              string           `json:"file_id"`
+	Source              string           `json:"source"`               // URL или название источника
+	Similarity          float64          `json:"similarity"`           // Процент схожести (0-100)
+	EstimatedSimilarity float64          `json:"estimated_similarity"` // Оценка схожести по MinHash (0-100)
+	MatchedShingles     int              `json:"matched_shingles"`     // Шинглы документа, найденные в источнике
+	ExclusiveShingles   int              `json:"exclusive_shingles"`   // Найденные только в этом источнике
+	SharedShingles      int              `json:"shared_shingles"`      // Найденные также в других источниках
+	ExclusivePercentage float64          `json:"exclusive_percentage"` // Доля эксклюзивных шинглов от всех шинглов документа
+	SharedPercentage    float64          `json:"shared_percentage"`    // Доля общих с другими источниками шинглов
+	MatchedText         string           `json:"matched_text"`
+	StartPos            int              `json:"start_pos"`            // Начало совпадения в анализируемом документе (байты)
+	EndPos              int              `json:"end_pos"`              // Конец совпадения в анализируемом документе (байты)
+	StartRune           int              `json:"start_rune"`           // Начало совпадения в символах
+	EndRune             int              `json:"end_rune"`             // Конец совпадения в символах
+	StartLine           int              `json:"start_line,omitempty"` // Первая строка совпадения в исходном коде
+	EndLine             int              `json:"end_line,omitempty"`   // Последняя строка совпадения в исходном коде
+	Passages            []MatchedPassage `json:"passages"`             // Непрерывные совпавшие фрагменты
+}
+
+// TextSpan is a fragment of an original document text
+type TextSpan struct {
+	StartPos  int `json:"start_pos"`            // Начало в байтах
+	EndPos    int `json:"end_pos"`              // Конец в байтах
+	StartRune int `json:"start_rune"`           // Начало в символах
+	EndRune   int `json:"end_rune"`             // Конец в символах
+	StartLine int `json:"start_line,omitempty"` // Первая строка, только для исходного кода
+	EndLine   int `json:"end_line,omitempty"`   // Последняя строка, только для исходного кода
+}
+
+// MatchedPassage is a contiguous passage of the analysed document found in a source document
+type MatchedPassage struct {
+	Document  TextSpan `json:"document"`   // Фрагмент анализируемого документа
+	Source    TextSpan `json:"source"`     // Фрагмент документа-источника
+	WordCount int      `json:"word_count"` // Длина фрагмента в словах
+	Text      string   `json:"text"`       // Текст фрагмента анализируемого документа
+}
+
+// SimilarityEstimate represents a candidate document found through LSH
+type SimilarityEstimate struct {
+	FileID              string  `json:"file_id"`
+	EstimatedSimilarity float64 `json:"estimated_similarity"` // Оценка сходства по Жаккару по MinHash (0-100)
+	SharedBands         int     `json:"shared_bands"`         // Количество совпавших LSH полос
+}
+
+// AlgorithmInfo describes the plagiarism detection algorithm used for a report
+type AlgorithmInfo struct {
+	Name         string `json:"name"`                    // shingles или winnowing
+	KGramSize    int    `json:"kgram_size"`              // Размер шингла в словах или k-граммы в символах
+	WindowSize   int    `json:"window_size,omitempty"`   // Размер окна winnowing
+	CodeLanguage string `json:"code_language,omitempty"` // Язык программирования в режиме исходного кода
+}
+
+// ObfuscationReport counts characters that were normalized before the comparison: they change shingle hashes
+// without changing how the text looks, so they are a sign of an attempt to evade the check
+type ObfuscationReport struct {
+	HomoglyphSubstitutions  int `json:"homoglyph_substitutions"`  // Буквы другого алфавита внутри слов (а/a, о/o, е/e, с/c)
+	InvisibleCharacters     int `json:"invisible_characters"`     // Символы нулевой ширины и мягкие переносы внутри слов
+	CompatibilityCharacters int `json:"compatibility_characters"` // Символы, замененные NFKC (полноширинные, лигатуры)
+	AffectedWords           int `json:"affected_words"`           // Слова, в которых найдены замены
+}
+
+// ExcludedRange is a fragment of the analysed document excluded from the uniqueness score
+type ExcludedRange struct {
+	Kind string   `json:"kind"` // quote, block_quote, bibliography или template
+	Span TextSpan `json:"span"` // Позиция в исходном тексте
 }
 
 // PlagiarismReport represents the plagiarism analysis report
 type PlagiarismReport struct {
-	UniquenessPercentage float64           `json:"uniqueness_percentage"` // Процент уникальности
-	TotalShingles        int               `json:"total_shingles"`        // Общее количество шинглов
-	UniqueShingles       int               `json:"unique_shingles"`       // Количество уникальных шинглов
-	Matches              []PlagiarismMatch `json:"matches"`               // Найденные совпадения
-	ProcessedAt          time.Time         `json:"processed_at"`          // Время обработки
+	Algorithm            AlgorithmInfo        `json:"algorithm"`                 // Использованный алгоритм
+	UniquenessPercentage float64              `json:"uniqueness_percentage"`     // Процент уникальности
+	TotalShingles        int                  `json:"total_shingles"`            // Общее количество шинглов
+	UniqueShingles       int                  `json:"unique_shingles"`           // Количество уникальных шинглов
+	MatchedShingles      int                  `json:"matched_shingles"`          // Шинглы, найденные хотя бы в одном источнике
+	SharedShingles       int                  `json:"shared_shingles"`           // Шинглы, найденные в нескольких источниках
+	Matches              []PlagiarismMatch    `json:"matches"`                   // Найденные совпадения
+	SimilarityEstimates  []SimilarityEstimate `json:"similarity_estimates"`      // Кандидаты, найденные через LSH
+	ExcludedShingles     int                  `json:"excluded_shingles"`         // Шинглы в исключенных фрагментах, не входят в total_shingles
+	TemplateShingles     int                  `json:"template_shingles"`         // Шинглы из шаблонов задания, не входят в total_shingles
+	ExcludedRanges       []ExcludedRange      `json:"excluded_ranges,omitempty"` // Цитаты и список литературы, исключенные из расчета
+	Obfuscation          *ObfuscationReport   `json:"obfuscation,omitempty"`     // Найденные попытки обойти проверку
+	ProcessedAt          time.Time            `json:"processed_at"`              // Время обработки
 }
 
 // TextStatistics represents text analysis statistics
 type TextStatistics struct {
-	ParagraphCount int `json:"paragraph_count"`
-	WordCount      int `json:"word_count"`
-	CharacterCount int `json:"character_count"`
-	SentenceCount  int `json:"sentence_count"`
+	ParagraphCount     int            `json:"paragraph_count"`
+	WordCount          int            `json:"word_count"`
+	CharacterCount     int            `json:"character_count"`
+	SentenceCount      int            `json:"sentence_count"`
+	Language           string         `json:"language"`            // Язык документа (ISO 639-1)
+	ParagraphLanguages map[string]int `json:"paragraph_languages"` // Количество абзацев на каждом языке
 }
 
 // Analysis represents an analysis entity in the domain
 type Analysis struct {
 	ID               string            `json:"id"`
 	FileID           string            `json:"file_id"`
+	Version          int               `json:"version"`   // Номер версии анализа файла, начиная с 1
+	Algorithm        string            `json:"algorithm"` // Ключ алгоритма с параметрами, например winnowing:5:4
+	Options          string            `json:"options"`   // Ключ области поиска и исключений, с которыми сделан анализ
+	Rerun            bool              `json:"rerun"`     // Сохранен повторным анализом, а не первым с этими параметрами
+	ImageKey         string            `json:"image_key"` // Ключ PNG облака слов в хранилище
 	ImageLocation    string            `json:"image_location"`
 	PlagiarismReport *PlagiarismReport `json:"plagiarism_report,omitempty"` // JSON отчет об антиплагиате
 	Statistics       *TextStatistics   `json:"statistics,omitempty"`        // JSON статистика текста
@@ -54,6 +129,11 @@ func NewAnalysis(fileID string) (*Analysis, error) {
 	}, nil
 }
 
+// SVGImageKey returns the storage key of the SVG word cloud, it is stored next to the PNG one
+func (a *Analysis) SVGImageKey() string {
+	return a.ImageKey + ".svg"
+}
+
 // SetImageLocation sets the analysis image location (words cloud)
 func (a *Analysis) SetImageLocation(location string) error {
 	if location == "" {
@@ -137,3 +217,47 @@ func (a *Analysis) SetStatisticsFromJSON(jsonData string) error {
 	a.UpdatedAt = time.Now()
 	return nil
 }
+
+// Comparison represents a direct comparison of two documents
+type Comparison struct {
+	FileA          string           `json:"file_a"`
+	FileB          string           `json:"file_b"`
+	Algorithm      AlgorithmInfo    `json:"algorithm"`
+	SimilarityAB   float64          `json:"similarity_a_to_b"`  // Доля шинглов A, найденных в B (0-100)
+	SimilarityBA   float64          `json:"similarity_b_to_a"`  // Доля шинглов B, найденных в A (0-100)
+	Jaccard        float64          `json:"jaccard"`            // Коэффициент Жаккара множеств шинглов (0-100)
+	ContainmentAB  float64          `json:"containment_a_in_b"` // Доля различных шинглов A, содержащихся в B (0-100)
+	ContainmentBA  float64          `json:"containment_b_in_a"` // Доля различных шинглов B, содержащихся в A (0-100)
+	TotalShinglesA int              `json:"total_shingles_a"`
+	TotalShinglesB int              `json:"total_shingles_b"`
+	CommonShingles int              `json:"common_shingles"` // Количество различных общих шинглов
+	Passages       []MatchedPassage `json:"passages"`        // document — фрагмент A, source — фрагмент B
+	ComparedAt     time.Time        `json:"compared_at"`
+}
+
+// SimilarityEdge connects two documents whose similarity reaches the clustering threshold
+type SimilarityEdge struct {
+	FileA        string  `json:"file_a"`
+	FileB        string  `json:"file_b"`
+	SimilarityAB float64 `json:"similarity_a_to_b"` // Доля шинглов A, найденных в B (0-100)
+	SimilarityBA float64 `json:"similarity_b_to_a"` // Доля шинглов B, найденных в A (0-100)
+	Similarity   float64 `json:"similarity"`        // Большее из двух направлений
+}
+
+// Cluster is a group of documents connected by similarity edges
+type Cluster struct {
+	FileIDs       []string `json:"file_ids"`
+	MaxSimilarity float64  `json:"max_similarity"`
+}
+
+// ClusterReport represents the pairwise similarity of a set of documents and their clusters
+type ClusterReport struct {
+	FileIDs        []string         `json:"file_ids"` // Порядок строк и столбцов матрицы
+	Algorithm      AlgorithmInfo    `json:"algorithm"`
+	Threshold      float64          `json:"threshold"`
+	Matrix         [][]float64      `json:"matrix"` // matrix[i][j] — доля шинглов file_ids[i], найденных в file_ids[j] (0-100)
+	Edges          []SimilarityEdge `json:"edges"`
+	Clusters       []Cluster        `json:"clusters"`
+	MissingFileIDs []string         `json:"missing_file_ids"` // Файлы без шинглов выбранного алгоритма (еще не проанализированы)
+	CreatedAt      time.Time        `json:"created_at"`
+}
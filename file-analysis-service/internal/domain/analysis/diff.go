@@ -0,0 +1,114 @@
+package analysis
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ErrNoPlagiarismReport is returned when an analysis version to compare has no plagiarism report
+var ErrNoPlagiarismReport = errors.New("analysis has no plagiarism report")
+
+// SourceChange is a source document found by one of the compared versions or by both
+type SourceChange struct {
+	FileID           string  `json:"file_id"`
+	SimilarityBefore float64 `json:"similarity_before"` // 0, если источник появился в новой версии
+	SimilarityAfter  float64 `json:"similarity_after"`  // 0, если источник исчез в новой версии
+	Delta            float64 `json:"delta"`             // Изменение схожести в процентных пунктах
+}
+
+// VersionDiff describes how the analysis of a file changed between two versions
+type VersionDiff struct {
+	FileID           string         `json:"file_id"`
+	FromVersion      int            `json:"from_version"`
+	ToVersion        int            `json:"to_version"`
+	FromAlgorithm    AlgorithmInfo  `json:"from_algorithm"`
+	ToAlgorithm      AlgorithmInfo  `json:"to_algorithm"`
+	AlgorithmChanged bool           `json:"algorithm_changed"` // Версии получены разными алгоритмами, сравнивать их нужно с осторожностью
+	UniquenessBefore float64        `json:"uniqueness_before"` // Процент уникальности в старой версии
+	UniquenessAfter  float64        `json:"uniqueness_after"`  // Процент уникальности в новой версии
+	UniquenessDelta  float64        `json:"uniqueness_delta"`  // Изменение уникальности в процентных пунктах
+	NewSources       []SourceChange `json:"new_sources"`       // Источники, найденные только новой версией
+	RemovedSources   []SourceChange `json:"removed_sources"`   // Источники, которых нет в новой версии (например, удаленные файлы)
+	ChangedSources   []SourceChange `json:"changed_sources"`   // Источники обеих версий с изменившейся схожестью
+	UnchangedSources int            `json:"unchanged_sources"` // Источники обеих версий с той же схожестью
+}
+
+// Diff compares an older version of the analysis of a file with a newer one. Sources are ordered
+// by the size of the change, the largest first.
+func Diff(from *Analysis, to *Analysis) (*VersionDiff, error) {
+	if from.PlagiarismReport == nil {
+		return nil, fmt.Errorf("%w: version %d", ErrNoPlagiarismReport, from.Version)
+	}
+	if to.PlagiarismReport == nil {
+		return nil, fmt.Errorf("%w: version %d", ErrNoPlagiarismReport, to.Version)
+	}
+
+	before, after := from.PlagiarismReport, to.PlagiarismReport
+	diff := &VersionDiff{
+		FileID:           to.FileID,
+		FromVersion:      from.Version,
+		ToVersion:        to.Version,
+		FromAlgorithm:    before.Algorithm,
+		ToAlgorithm:      after.Algorithm,
+		AlgorithmChanged: before.Algorithm != after.Algorithm,
+		UniquenessBefore: before.UniquenessPercentage,
+		UniquenessAfter:  after.UniquenessPercentage,
+		UniquenessDelta:  roundDelta(after.UniquenessPercentage - before.UniquenessPercentage),
+		NewSources:       []SourceChange{},
+		RemovedSources:   []SourceChange{},
+		ChangedSources:   []SourceChange{},
+	}
+
+	beforeSimilarity := sourceSimilarities(before)
+	afterSimilarity := sourceSimilarities(after)
+
+	for fileID, similarity := range afterSimilarity {
+		previous, found := beforeSimilarity[fileID]
+		change := SourceChange{FileID: fileID, SimilarityBefore: previous, SimilarityAfter: similarity, Delta: roundDelta(similarity - previous)}
+
+		switch {
+		case !found:
+			diff.NewSources = append(diff.NewSources, change)
+		case change.Delta != 0:
+			diff.ChangedSources = append(diff.ChangedSources, change)
+		default:
+			diff.UnchangedSources++
+		}
+	}
+
+	for fileID, similarity := range beforeSimilarity {
+		if _, found := afterSimilarity[fileID]; !found {
+			diff.RemovedSources = append(diff.RemovedSources, SourceChange{FileID: fileID, SimilarityBefore: similarity, Delta: roundDelta(-similarity)})
+		}
+	}
+
+	for _, changes := range [][]SourceChange{diff.NewSources, diff.RemovedSources, diff.ChangedSources} {
+		sortByChange(changes)
+	}
+
+	return diff, nil
+}
+
+// sourceSimilarities maps the source documents of a report to their similarity
+func sourceSimilarities(report *PlagiarismReport) map[string]float64 {
+	similarities := make(map[string]float64, len(report.Matches))
+	for _, match := range report.Matches {
+		similarities[match.FileID] = match.Similarity
+	}
+	return similarities
+}
+
+// sortByChange orders sources by the absolute change of similarity, ties by file ID
+func sortByChange(changes []SourceChange) {
+	slices.SortFunc(changes, func(a, b SourceChange) int {
+		return cmp.Or(cmp.Compare(math.Abs(b.Delta), math.Abs(a.Delta)), cmp.Compare(a.FileID, b.FileID))
+	})
+}
+
+// roundDelta rounds a difference of percentages to two decimals, hiding floating point noise
+func roundDelta(delta float64) float64 {
+	return math.Round(delta*100) / 100
+}
@@ -0,0 +1,65 @@
+package analysis
+
+import (
+	"errors"
+	"testing"
+)
+
+func versionWithMatches(version int, uniqueness float64, similarities map[string]float64) *Analysis {
+	report := &PlagiarismReport{
+		Algorithm:            AlgorithmInfo{Name: "shingles", KGramSize: 4},
+		UniquenessPercentage: uniqueness,
+	}
+	for fileID, similarity := range similarities {
+		report.Matches = append(report.Matches, PlagiarismMatch{FileID: fileID, Similarity: similarity})
+	}
+
+	return &Analysis{ID: "analysis", FileID: "file1", Version: version, PlagiarismReport: report}
+}
+
+func TestDiff(t *testing.T) {
+	from := versionWithMatches(1, 80, map[string]float64{"kept": 10, "grown": 5, "deleted": 7})
+	to := versionWithMatches(2, 55.3, map[string]float64{"kept": 10, "grown": 12.5, "late": 20, "later": 3})
+
+	diff, err := Diff(from, to)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	if diff.FromVersion != 1 || diff.ToVersion != 2 || diff.AlgorithmChanged {
+		t.Errorf("Diff() versions = %d-%d, algorithm changed = %v", diff.FromVersion, diff.ToVersion, diff.AlgorithmChanged)
+	}
+	if diff.UniquenessBefore != 80 || diff.UniquenessAfter != 55.3 || diff.UniquenessDelta != -24.7 {
+		t.Errorf("Uniqueness = %v -> %v (%v), want 80 -> 55.3 (-24.7)", diff.UniquenessBefore, diff.UniquenessAfter, diff.UniquenessDelta)
+	}
+
+	// Submissions uploaded after the first version appear as new sources, the largest first
+	if len(diff.NewSources) != 2 || diff.NewSources[0].FileID != "late" || diff.NewSources[1].FileID != "later" {
+		t.Errorf("New sources = %+v, want late and later", diff.NewSources)
+	}
+	if len(diff.RemovedSources) != 1 || diff.RemovedSources[0].FileID != "deleted" || diff.RemovedSources[0].Delta != -7 {
+		t.Errorf("Removed sources = %+v, want deleted", diff.RemovedSources)
+	}
+	if len(diff.ChangedSources) != 1 || diff.ChangedSources[0] != (SourceChange{FileID: "grown", SimilarityBefore: 5, SimilarityAfter: 12.5, Delta: 7.5}) {
+		t.Errorf("Changed sources = %+v, want grown", diff.ChangedSources)
+	}
+	if diff.UnchangedSources != 1 {
+		t.Errorf("Unchanged sources = %d, want 1", diff.UnchangedSources)
+	}
+}
+
+func TestDiff_AlgorithmChanged(t *testing.T) {
+	from := versionWithMatches(1, 100, nil)
+	to := versionWithMatches(2, 100, nil)
+	to.PlagiarismReport.Algorithm = AlgorithmInfo{Name: "winnowing", KGramSize: 25, WindowSize: 20}
+
+	diff, err := Diff(from, to)
+	if err != nil || !diff.AlgorithmChanged || diff.NewSources == nil {
+		t.Errorf("Diff() = %+v, %v, want the algorithm change reported", diff, err)
+	}
+
+	to.PlagiarismReport = nil
+	if _, err := Diff(from, to); !errors.Is(err, ErrNoPlagiarismReport) {
+		t.Errorf("Diff() error = %v, want ErrNoPlagiarismReport", err)
+	}
+}
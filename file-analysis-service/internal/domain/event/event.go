@@ -0,0 +1,51 @@
+package event
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TypeFileUploaded is sent by file-storing-service for every new file
+const TypeFileUploaded = "file.uploaded"
+
+// ErrInvalidEvent is returned for an event without an ID, a type or a valid payload
+var ErrInvalidEvent = errors.New("invalid event")
+
+// Event is a message of another service. Events are delivered at least once, so they are deduplicated by ID.
+type Event struct {
+	ID        string
+	Type      string
+	Payload   json.RawMessage
+	CreatedAt time.Time
+}
+
+// FileUploaded is the payload of TypeFileUploaded
+type FileUploaded struct {
+	FileID       string `json:"file_id"`
+	AssignmentID string `json:"assignment_id"` // Пусто, если файл не сдан в задание
+}
+
+// Validate checks the fields every event must have
+func (e *Event) Validate() error {
+	if e.ID == "" {
+		return fmt.Errorf("%w: event ID cannot be empty", ErrInvalidEvent)
+	}
+	if e.Type == "" {
+		return fmt.Errorf("%w: event type cannot be empty", ErrInvalidEvent)
+	}
+	return nil
+}
+
+// FileUploaded decodes the payload of a TypeFileUploaded event
+func (e *Event) FileUploaded() (*FileUploaded, error) {
+	var payload FileUploaded
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	if payload.FileID == "" {
+		return nil, fmt.Errorf("%w: file ID cannot be empty", ErrInvalidEvent)
+	}
+	return &payload, nil
+}
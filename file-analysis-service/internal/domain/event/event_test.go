@@ -0,0 +1,47 @@
+package event
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEvent_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+		valid bool
+	}{
+		{"Valid", Event{ID: "e1", Type: TypeFileUploaded}, true},
+		{"Unknown type is valid", Event{ID: "e1", Type: "file.renamed"}, true},
+		{"No ID", Event{Type: TypeFileUploaded}, false},
+		{"No type", Event{ID: "e1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.event.Validate()
+			if (err == nil) != tt.valid {
+				t.Errorf("Validate() error = %v, want valid = %v", err, tt.valid)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidEvent) {
+				t.Errorf("Validate() error = %v, want ErrInvalidEvent", err)
+			}
+		})
+	}
+}
+
+func TestEvent_FileUploaded(t *testing.T) {
+	e := Event{ID: "e1", Type: TypeFileUploaded, Payload: []byte(`{"file_id":"file1","assignment_id":"hw1","size":42}`)}
+
+	payload, err := e.FileUploaded()
+	if err != nil || payload.FileID != "file1" || payload.AssignmentID != "hw1" {
+		t.Errorf("FileUploaded() = %+v, %v", payload, err)
+	}
+
+	for _, raw := range []string{`{"assignment_id":"hw1"}`, `not json`, ``} {
+		e.Payload = []byte(raw)
+		if _, err := e.FileUploaded(); !errors.Is(err, ErrInvalidEvent) {
+			t.Errorf("FileUploaded(%q) error = %v, want ErrInvalidEvent", raw, err)
+		}
+	}
+}
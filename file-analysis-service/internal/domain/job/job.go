@@ -0,0 +1,186 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Status represents the state of an analysis job
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusDone      Status = "done"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+const (
+	// MaxAttempts is how many times a job is run before it is reported as failed
+	MaxAttempts = 5
+	// BaseBackoff is the delay before the first retry, it doubles with every further attempt
+	BaseBackoff = 5 * time.Second
+	// MaxBackoff caps the delay between retries
+	MaxBackoff = 5 * time.Minute
+	// Lease is how long a worker may run a job, a job running longer is considered abandoned
+	// (e.g. the service was restarted) and is picked up again
+	Lease = 10 * time.Minute
+)
+
+// ErrNotRunning is returned when finishing a job that is not running
+var ErrNotRunning = errors.New("job is not running")
+
+// ErrInvalidScope is returned for an unknown search scope
+var ErrInvalidScope = errors.New("invalid search scope")
+
+// Scope is the set of submissions a file is compared with, file-storing-service resolves it to file IDs
+type Scope string
+
+const (
+	ScopeAssignment Scope = "assignment"  // Работы того же задания
+	ScopeCourse     Scope = "course"      // Работы всех заданий курса
+	ScopeAll        Scope = "all"         // Все проанализированные файлы
+	ScopePriorYears Scope = "prior_years" // Работы курса с тем же названием за прошлые годы
+)
+
+// ParseScope validates a scope received from a client, the empty scope means all files
+func ParseScope(value string) (Scope, error) {
+	switch scope := Scope(value); scope {
+	case "":
+		return ScopeAll, nil
+	case ScopeAssignment, ScopeCourse, ScopeAll, ScopePriorYears:
+		return scope, nil
+	default:
+		return "", fmt.Errorf("%w: scope must be one of: %s, %s, %s, %s", ErrInvalidScope, ScopeAssignment, ScopeCourse, ScopeAll, ScopePriorYears)
+	}
+}
+
+// Job represents a queued analysis of a file
+type Job struct {
+	ID                  string
+	FileID              string
+	Algorithm           string // Plagiarism detection algorithm, empty for the default one
+	ExcludeQuotes       bool   // Quoted spans are excluded from the uniqueness score
+	ExcludeBibliography bool   // The trailing bibliography is excluded from the uniqueness score
+	AssignmentID        string // Text of the assignment templates is excluded from the uniqueness score
+	Scope               Scope  // Submissions the file is compared with
+	CodeLanguage        string // Programming language of a source code file, empty for natural text
+	Rerun               bool   // A new analysis version is made even if the file has been analysed
+	Status              Status
+	Attempts            int
+	MaxAttempts         int
+	LastError           string
+	AnalysisID          string
+	RunAt               time.Time  // The job is not picked up before this time
+	LeaseUntil          *time.Time // Set while the job is running
+	FinishedAt          *time.Time
+	UpdatedAt           time.Time
+	CreatedAt           time.Time
+}
+
+// NewJob creates a new queued job analysing the file with the given algorithm
+func NewJob(fileID string, algorithm string) (*Job, error) {
+	if fileID == "" {
+		return nil, errors.New("file ID cannot be empty")
+	}
+
+	now := time.Now()
+	return &Job{
+		FileID:      fileID,
+		Algorithm:   algorithm,
+		Scope:       ScopeAll,
+		Status:      StatusQueued,
+		MaxAttempts: MaxAttempts,
+		RunAt:       now,
+		UpdatedAt:   now,
+		CreatedAt:   now,
+	}, nil
+}
+
+// Start marks the job as running by a worker
+func (j *Job) Start(now time.Time) {
+	leaseUntil := now.Add(Lease)
+	j.Status = StatusRunning
+	j.Attempts++
+	j.LeaseUntil = &leaseUntil
+	j.UpdatedAt = now
+}
+
+// Succeed marks the job as done and links the resulting analysis
+func (j *Job) Succeed(analysisID string, now time.Time) error {
+	if j.Status != StatusRunning {
+		return ErrNotRunning
+	}
+	j.Status = StatusDone
+	j.AnalysisID = analysisID
+	j.LastError = ""
+	j.LeaseUntil = nil
+	j.FinishedAt = &now
+	j.UpdatedAt = now
+	return nil
+}
+
+// Fail records the failure reason and schedules a retry, or fails the job once it is out of attempts
+func (j *Job) Fail(reason error, now time.Time) error {
+	if j.Status != StatusRunning {
+		return ErrNotRunning
+	}
+	j.LastError = reason.Error()
+	j.LeaseUntil = nil
+	j.UpdatedAt = now
+
+	if j.Attempts >= j.MaxAttempts {
+		j.Status = StatusFailed
+		j.FinishedAt = &now
+		return nil
+	}
+
+	j.Status = StatusQueued
+	j.RunAt = now.Add(Backoff(j.Attempts))
+	return nil
+}
+
+// Release puts an interrupted job back into the queue without counting the attempt
+func (j *Job) Release(now time.Time) error {
+	if j.Status != StatusRunning {
+		return ErrNotRunning
+	}
+	j.Status = StatusQueued
+	j.Attempts--
+	j.LeaseUntil = nil
+	j.RunAt = now
+	j.UpdatedAt = now
+	return nil
+}
+
+// Cancel stops a job that is no longer needed, e.g. because its file has been deleted
+func (j *Job) Cancel(reason error, now time.Time) error {
+	if j.IsFinished() {
+		return fmt.Errorf("job is already %s", j.Status)
+	}
+	j.Status = StatusCancelled
+	j.LastError = reason.Error()
+	j.LeaseUntil = nil
+	j.FinishedAt = &now
+	j.UpdatedAt = now
+	return nil
+}
+
+// IsFinished reports whether the job will not be run anymore
+func (j *Job) IsFinished() bool {
+	return j.Status == StatusDone || j.Status == StatusFailed || j.Status == StatusCancelled
+}
+
+// Backoff returns the delay before the retry following the given attempt
+func Backoff(attempt int) time.Duration {
+	delay := BaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= MaxBackoff {
+			return MaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,138 @@
+package job
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJob_RetriesWithBackoff(t *testing.T) {
+	j, err := NewJob("file-id", "")
+	if err != nil {
+		t.Fatalf("NewJob() error = %v", err)
+	}
+
+	now := time.Now()
+	for attempt := 1; attempt < MaxAttempts; attempt++ {
+		j.Start(now)
+		if j.Status != StatusRunning || j.Attempts != attempt {
+			t.Fatalf("Start() status = %v, attempts = %v", j.Status, j.Attempts)
+		}
+
+		if err := j.Fail(errors.New("file storing service is unavailable"), now); err != nil {
+			t.Fatalf("Fail() error = %v", err)
+		}
+
+		if j.Status != StatusQueued {
+			t.Fatalf("Fail() status = %v, want %v", j.Status, StatusQueued)
+		}
+		if want := now.Add(Backoff(attempt)); !j.RunAt.Equal(want) {
+			t.Errorf("Fail() run at = %v, want %v", j.RunAt, want)
+		}
+	}
+
+	j.Start(now)
+	if err := j.Fail(errors.New("file storing service is unavailable"), now); err != nil {
+		t.Fatalf("Fail() error = %v", err)
+	}
+
+	if j.Status != StatusFailed || !j.IsFinished() {
+		t.Errorf("Fail() status = %v after the last attempt, want %v", j.Status, StatusFailed)
+	}
+	if j.LastError != "file storing service is unavailable" {
+		t.Errorf("Fail() last error = %q", j.LastError)
+	}
+}
+
+func TestJob_SucceedAndRelease(t *testing.T) {
+	j, err := NewJob("file-id", "")
+	if err != nil {
+		t.Fatalf("NewJob() error = %v", err)
+	}
+
+	now := time.Now()
+	if err := j.Succeed("analysis-id", now); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Succeed() error = %v, want ErrNotRunning", err)
+	}
+
+	j.Start(now)
+	if err := j.Release(now); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if j.Status != StatusQueued || j.Attempts != 0 {
+		t.Errorf("Release() status = %v, attempts = %v", j.Status, j.Attempts)
+	}
+
+	j.Start(now)
+	if err := j.Succeed("analysis-id", now); err != nil {
+		t.Fatalf("Succeed() error = %v", err)
+	}
+	if j.Status != StatusDone || j.AnalysisID != "analysis-id" || j.FinishedAt == nil {
+		t.Errorf("Succeed() = %+v", j)
+	}
+}
+
+func TestJob_Cancel(t *testing.T) {
+	j, err := NewJob("file-id", "")
+	if err != nil {
+		t.Fatalf("NewJob() error = %v", err)
+	}
+
+	now := time.Now()
+	j.Start(now)
+	if err := j.Cancel(errors.New("file has been deleted"), now); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if j.Status != StatusCancelled || j.LastError != "file has been deleted" || j.FinishedAt == nil || !j.IsFinished() {
+		t.Errorf("Cancel() = %+v", j)
+	}
+
+	if err := j.Cancel(errors.New("file has been deleted"), now); err == nil {
+		t.Error("Cancel() of a finished job error = nil")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: BaseBackoff},
+		{attempt: 2, want: 2 * BaseBackoff},
+		{attempt: 3, want: 4 * BaseBackoff},
+		{attempt: 20, want: MaxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := Backoff(tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestParseScope(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Scope
+		wantErr bool
+	}{
+		{value: "", want: ScopeAll},
+		{value: "assignment", want: ScopeAssignment},
+		{value: "course", want: ScopeCourse},
+		{value: "prior_years", want: ScopePriorYears},
+		{value: "semester", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		scope, err := ParseScope(tt.value)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidScope) {
+				t.Errorf("ParseScope(%q) error = %v, want ErrInvalidScope", tt.value, err)
+			}
+			continue
+		}
+		if err != nil || scope != tt.want {
+			t.Errorf("ParseScope(%q) = %v, %v, want %v", tt.value, scope, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package plagiarism
+
+import (
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"hash/fnv"
+
+	"fileanalysisservice/internal/domain/analysis"
+)
+
+const (
+	// AlgorithmShingles compares every word shingle of the documents
+	AlgorithmShingles = "shingles"
+	// AlgorithmWinnowing compares only the character k-grams selected by winnowing
+	AlgorithmWinnowing = "winnowing"
+
+	// DefaultKGramSize and DefaultWindowSize of winnowing guarantee that any common passage
+	// of at least DefaultKGramSize+DefaultWindowSize-1 characters (about 6 words) is detected
+	DefaultKGramSize  = 25
+	DefaultWindowSize = 20
+)
+
+// ErrUnknownAlgorithm is returned for an algorithm that isn't registered in the service
+var ErrUnknownAlgorithm = errors.New("unknown plagiarism detection algorithm")
+
+// Fingerprint is a piece of a processed text stored and compared between documents.
+// Positions point to the words of the original text the piece was made of.
+type Fingerprint struct {
+	Hash      string
+	Text      string
+	StartPos  int // Byte offsets in the original text
+	EndPos    int
+	StartRune int // Rune offsets in the original text
+	EndRune   int
+	StartLine int // Lines of the original source code, 0 for natural text
+	EndLine   int
+}
+
+// newFingerprint creates a fingerprint spanning the original text from the first to the last token
+func newFingerprint(hash, text string, first, last Token) Fingerprint {
+	return Fingerprint{
+		Hash:      hash,
+		Text:      text,
+		StartPos:  first.StartPos,
+		EndPos:    last.EndPos,
+		StartRune: first.StartRune,
+		EndRune:   last.EndRune,
+		StartLine: first.StartLine,
+		EndLine:   last.EndLine,
+	}
+}
+
+// Algorithm selects the fingerprints of a processed text.
+// Documents are only compared with documents fingerprinted by an algorithm with the same key.
+type Algorithm interface {
+	// Info describes the algorithm and its parameters for the report
+	Info() analysis.AlgorithmInfo
+	// Key identifies the algorithm together with the parameters affecting its fingerprints
+	Key() string
+	// Fingerprints selects the fingerprints of a text tokenized by TextProcessor or TokenizeCode
+	Fingerprints(tokens []Token) []Fingerprint
+}
+
+// Shingling keeps every word shingle of the text
+type Shingling struct {
+	size int
+}
+
+// NewShingling creates a full shingling algorithm with shingles of the given amount of words
+func NewShingling(size int) *Shingling {
+	return &Shingling{
+		size: size,
+	}
+}
+
+func (s *Shingling) Info() analysis.AlgorithmInfo {
+	return analysis.AlgorithmInfo{
+		Name:      AlgorithmShingles,
+		KGramSize: s.size,
+	}
+}
+
+func (s *Shingling) Key() string {
+	return fmt.Sprintf("%s:%d", AlgorithmShingles, s.size)
+}
+
+func (s *Shingling) Fingerprints(tokens []Token) []Fingerprint {
+	if len(tokens) == 0 {
+		return []Fingerprint{}
+	}
+
+	// A text shorter than a shingle is a single shingle, as in GenerateShingles
+	size := min(s.size, len(tokens))
+	fingerprints := make([]Fingerprint, 0, len(tokens)-size+1)
+	for i := 0; i+size <= len(tokens); i++ {
+		text := JoinTokens(tokens[i : i+size])
+		fingerprints = append(fingerprints, newFingerprint(
+			fmt.Sprintf("%x", md5.Sum([]byte(text))),
+			text,
+			tokens[i],
+			tokens[i+size-1],
+		))
+	}
+
+	return fingerprints
+}
+
+// Winnowing selects a small subset of character k-gram hashes, as done by MOSS
+// (Schleimer, Wilkerson, Aiken "Winnowing: Local Algorithms for Document Fingerprinting").
+// In every window of WindowSize consecutive k-grams the one with the minimal hash is kept,
+// so roughly 2/(WindowSize+1) of the k-grams are stored instead of every word shingle.
+type Winnowing struct {
+	kGramSize  int
+	windowSize int
+}
+
+// NewWinnowing creates a winnowing algorithm, sizes below 1 fall back to the defaults
+func NewWinnowing(kGramSize, windowSize int) *Winnowing {
+	if kGramSize < 1 {
+		kGramSize = DefaultKGramSize
+	}
+	if windowSize < 1 {
+		windowSize = DefaultWindowSize
+	}
+
+	return &Winnowing{
+		kGramSize:  kGramSize,
+		windowSize: windowSize,
+	}
+}
+
+func (wn *Winnowing) Info() analysis.AlgorithmInfo {
+	return analysis.AlgorithmInfo{
+		Name:       AlgorithmWinnowing,
+		KGramSize:  wn.kGramSize,
+		WindowSize: wn.windowSize,
+	}
+}
+
+func (wn *Winnowing) Key() string {
+	return fmt.Sprintf("%s:%d:%d", AlgorithmWinnowing, wn.kGramSize, wn.windowSize)
+}
+
+func (wn *Winnowing) Fingerprints(tokens []Token) []Fingerprint {
+	if len(tokens) == 0 {
+		return []Fingerprint{}
+	}
+
+	// The processed text with the token of every rune, -1 for the spaces between tokens
+	var runes []rune
+	var owners []int
+	for i, token := range tokens {
+		if i > 0 {
+			runes = append(runes, ' ')
+			owners = append(owners, -1)
+		}
+		for _, r := range token.Text {
+			runes = append(runes, r)
+			owners = append(owners, i)
+		}
+	}
+
+	// A text shorter than a k-gram is a single k-gram
+	kGramCount := max(len(runes)-wn.kGramSize+1, 1)
+	kGramSize := min(wn.kGramSize, len(runes))
+
+	hashes := make([]uint64, kGramCount)
+	for i := range hashes {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(string(runes[i : i+kGramSize])))
+		hashes[i] = h.Sum64()
+	}
+
+	fingerprint := func(i int) Fingerprint {
+		// A k-gram covers the original words of the tokens it overlaps
+		first, last := owners[i], owners[i+kGramSize-1]
+		if first < 0 {
+			first = owners[i+1]
+		}
+		if last < 0 {
+			last = owners[i+kGramSize-2]
+		}
+		last = max(first, last)
+
+		return newFingerprint(fmt.Sprintf("%016x", hashes[i]), string(runes[i:i+kGramSize]), tokens[first], tokens[last])
+	}
+
+	windowSize := min(wn.windowSize, kGramCount)
+	var fingerprints []Fingerprint
+	selected := -1
+
+	for start := 0; start+windowSize <= kGramCount; start++ {
+		// The rightmost minimum is taken, so a minimum shared by consecutive windows is recorded once
+		minimum := start
+		for i := start + 1; i < start+windowSize; i++ {
+			if hashes[i] <= hashes[minimum] {
+				minimum = i
+			}
+		}
+
+		if minimum != selected {
+			selected = minimum
+			fingerprints = append(fingerprints, fingerprint(minimum))
+		}
+	}
+
+	return fingerprints
+}
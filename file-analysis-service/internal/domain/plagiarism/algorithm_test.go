@@ -0,0 +1,100 @@
+package plagiarism
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWinnowing_Fingerprints(t *testing.T) {
+	winnowing := NewWinnowing(5, 4)
+	text := "Алгоритм выбирает небольшое подмножество хэшей символьных грамм документа."
+	tokens := NewTextProcessor().Tokenize(text)
+	processed := JoinTokens(tokens)
+
+	fingerprints := winnowing.Fingerprints(tokens)
+	kGrams := len([]rune(processed)) - 5 + 1
+
+	// Every window of 4 consecutive k-grams contributes a fingerprint, so at least every 4th k-gram is kept
+	if len(fingerprints) < kGrams/4 || len(fingerprints) >= kGrams {
+		t.Fatalf("len(Fingerprints()) = %v, want a subset of %v k-grams of at least %v", len(fingerprints), kGrams, kGrams/4)
+	}
+
+	for _, fingerprint := range fingerprints {
+		if len([]rune(fingerprint.Text)) != 5 || !strings.Contains(processed, fingerprint.Text) {
+			t.Errorf("Fingerprint text %q is not a 5-gram of the processed text", fingerprint.Text)
+		}
+
+		// The k-gram comes from the original words it points to
+		original := NewTextProcessor().ProcessText(text[fingerprint.StartPos:fingerprint.EndPos])
+		if !strings.Contains(original, strings.TrimSpace(fingerprint.Text)) {
+			t.Errorf("Fingerprint %q points to %q", fingerprint.Text, text[fingerprint.StartPos:fingerprint.EndPos])
+		}
+	}
+}
+
+func TestWinnowing_SharedPassage(t *testing.T) {
+	winnowing := NewWinnowing(5, 4)
+	processor := NewTextProcessor()
+	passage := "общий фрагмент текста скопированный целиком"
+
+	a := winnowing.Fingerprints(processor.Tokenize("первый документ начинается так " + passage))
+	b := winnowing.Fingerprints(processor.Tokenize(passage + " второй документ заканчивается иначе"))
+
+	hashes := make(map[string]bool)
+	for _, fingerprint := range a {
+		hashes[fingerprint.Hash] = true
+	}
+
+	shared := 0
+	for _, fingerprint := range b {
+		if hashes[fingerprint.Hash] {
+			shared++
+		}
+	}
+
+	// A passage of at least k+w-1 characters is guaranteed to share a fingerprint
+	if shared == 0 {
+		t.Error("Documents with a common passage should share fingerprints")
+	}
+}
+
+func TestWinnowing_ShortText(t *testing.T) {
+	processor := NewTextProcessor()
+
+	fingerprints := NewWinnowing(25, 20).Fingerprints(processor.Tokenize("Текст"))
+	if len(fingerprints) != 1 || fingerprints[0].Text != "текст" || fingerprints[0].EndPos != len("Текст") {
+		t.Errorf("Fingerprints() = %v, want the whole text as one fingerprint", fingerprints)
+	}
+
+	if len(NewWinnowing(25, 20).Fingerprints(processor.Tokenize(""))) != 0 {
+		t.Error("Fingerprints() of an empty text should be empty")
+	}
+}
+
+func TestShingling_Fingerprints(t *testing.T) {
+	text := "Первое слово, <b>второе</b> слово и третье слово!"
+	fingerprints := NewShingling(2).Fingerprints(NewTextProcessor().Tokenize(text))
+
+	// "и" is a stop word, so the words are: первое слово второе слово третье слово
+	if len(fingerprints) != 5 {
+		t.Fatalf("len(Fingerprints()) = %v, want 5", len(fingerprints))
+	}
+
+	if got := text[fingerprints[1].StartPos:fingerprints[1].EndPos]; got != "слово, <b>второе" {
+		t.Errorf("Second shingle spans %q, want %q", got, "слово, <b>второе")
+	}
+
+	if fingerprints[0].StartRune != 0 || fingerprints[0].EndRune != len([]rune("Первое слово")) {
+		t.Errorf("First shingle runes = [%d, %d), want [0, %d)", fingerprints[0].StartRune, fingerprints[0].EndRune, len([]rune("Первое слово")))
+	}
+}
+
+func TestAlgorithm_Keys(t *testing.T) {
+	if key := NewShingling(4).Key(); key != "shingles:4" {
+		t.Errorf("Shingling key = %v, want shingles:4", key)
+	}
+
+	if key := NewWinnowing(0, 0).Key(); key != "winnowing:25:20" {
+		t.Errorf("Winnowing key = %v, want winnowing:25:20 (defaults)", key)
+	}
+}
@@ -0,0 +1,230 @@
+package plagiarism
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fileanalysisservice/internal/domain/analysis"
+)
+
+// MaxClusterFiles is the maximum amount of documents in one similarity matrix
+const MaxClusterFiles = 500
+
+var (
+	ErrInvalidThreshold = errors.New("threshold must be between 0 and 100")
+	ErrInvalidFileSet   = fmt.Errorf("from 2 to %d file IDs are required", MaxClusterFiles)
+)
+
+// SimilarityClusters computes the pairwise similarity matrix of the documents from their stored shingles
+// and groups documents connected by a similarity of at least threshold percent (connected components).
+// Documents that haven't been analysed with the algorithm are reported as missing.
+func (ps *Service) SimilarityClusters(ctx context.Context, fileIDs []string, algorithmName string, threshold float64) (*analysis.ClusterReport, error) {
+	algorithm, err := ps.Algorithm(algorithmName)
+	if err != nil {
+		return nil, err
+	}
+
+	if threshold <= 0 || threshold > 100 {
+		return nil, ErrInvalidThreshold
+	}
+
+	fileIDs = uniqueFileIDs(fileIDs)
+	if len(fileIDs) < 2 || len(fileIDs) > MaxClusterFiles {
+		return nil, ErrInvalidFileSet
+	}
+
+	shingles, err := ps.shingleRepository.FindShinglesByFileIDs(ctx, algorithm.Key(), fileIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shingles: %w", err)
+	}
+
+	hashesByFile := make(map[string][]string, len(fileIDs))
+	for _, shingle := range shingles {
+		hashesByFile[shingle.FileID] = append(hashesByFile[shingle.FileID], shingle.ShingleHash)
+	}
+
+	report := &analysis.ClusterReport{
+		FileIDs:        []string{},
+		Algorithm:      algorithm.Info(),
+		Threshold:      threshold,
+		Edges:          []analysis.SimilarityEdge{},
+		Clusters:       []analysis.Cluster{},
+		MissingFileIDs: []string{},
+		CreatedAt:      time.Now(),
+	}
+
+	for _, fileID := range fileIDs {
+		if len(hashesByFile[fileID]) == 0 {
+			report.MissingFileIDs = append(report.MissingFileIDs, fileID)
+			continue
+		}
+		report.FileIDs = append(report.FileIDs, fileID)
+	}
+
+	report.Matrix = similarityMatrix(report.FileIDs, hashesByFile)
+
+	components := newDisjointSet(len(report.FileIDs))
+	for i := range report.FileIDs {
+		for j := i + 1; j < len(report.FileIDs); j++ {
+			similarity := max(report.Matrix[i][j], report.Matrix[j][i])
+			if similarity < threshold {
+				continue
+			}
+
+			report.Edges = append(report.Edges, analysis.SimilarityEdge{
+				FileA:        report.FileIDs[i],
+				FileB:        report.FileIDs[j],
+				SimilarityAB: report.Matrix[i][j],
+				SimilarityBA: report.Matrix[j][i],
+				Similarity:   similarity,
+			})
+			components.union(i, j)
+		}
+	}
+
+	report.Clusters = clusters(report, components)
+	return report, nil
+}
+
+// similarityMatrix returns the share of positions of every document whose hash is found in every other document.
+// An inverted index keeps the work proportional to the amount of shared hashes instead of all pairs of shingles.
+func similarityMatrix(fileIDs []string, hashesByFile map[string][]string) [][]float64 {
+	filesByHash := make(map[string][]int)
+	for i, fileID := range fileIDs {
+		for _, hash := range hashesByFile[fileID] {
+			files := filesByHash[hash]
+			if len(files) == 0 || files[len(files)-1] != i {
+				filesByHash[hash] = append(files, i)
+			}
+		}
+	}
+
+	matrix := make([][]float64, len(fileIDs))
+	for i, fileID := range fileIDs {
+		covered := make([]int, len(fileIDs))
+		hashes := hashesByFile[fileID]
+		for _, hash := range hashes {
+			for _, j := range filesByHash[hash] {
+				covered[j]++
+			}
+		}
+
+		matrix[i] = make([]float64, len(fileIDs))
+		for j := range fileIDs {
+			matrix[i][j] = percentage(covered[j], len(hashes))
+		}
+	}
+
+	return matrix
+}
+
+// clusters collects connected components of more than one document, the largest first
+func clusters(report *analysis.ClusterReport, components *disjointSet) []analysis.Cluster {
+	byRoot := make(map[int]*analysis.Cluster)
+	indices := make(map[string]int, len(report.FileIDs))
+	var roots []int
+	for i, fileID := range report.FileIDs {
+		indices[fileID] = i
+		root := components.find(i)
+		cluster, ok := byRoot[root]
+		if !ok {
+			cluster = &analysis.Cluster{}
+			byRoot[root] = cluster
+			roots = append(roots, root)
+		}
+		cluster.FileIDs = append(cluster.FileIDs, fileID)
+	}
+
+	for _, edge := range report.Edges {
+		cluster := byRoot[components.find(indices[edge.FileA])]
+		cluster.MaxSimilarity = max(cluster.MaxSimilarity, edge.Similarity)
+	}
+
+	result := []analysis.Cluster{}
+	for _, root := range roots {
+		if len(byRoot[root].FileIDs) > 1 {
+			result = append(result, *byRoot[root])
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return len(result[i].FileIDs) > len(result[j].FileIDs)
+	})
+
+	return result
+}
+
+// ClusterDOT renders the similarity graph in the Graphviz DOT language, every cluster is drawn as a subgraph
+func ClusterDOT(report *analysis.ClusterReport) string {
+	var b strings.Builder
+	b.WriteString("graph similarity {\n")
+	b.WriteString("\tnode [shape=box];\n")
+
+	clustered := make(map[string]bool)
+	for i, cluster := range report.Clusters {
+		fmt.Fprintf(&b, "\tsubgraph cluster_%d {\n", i+1)
+		fmt.Fprintf(&b, "\t\tlabel=%s;\n", strconv.Quote(fmt.Sprintf("Cluster %d", i+1)))
+		for _, fileID := range cluster.FileIDs {
+			fmt.Fprintf(&b, "\t\t%s;\n", strconv.Quote(fileID))
+			clustered[fileID] = true
+		}
+		b.WriteString("\t}\n")
+	}
+
+	for _, fileID := range report.FileIDs {
+		if !clustered[fileID] {
+			fmt.Fprintf(&b, "\t%s;\n", strconv.Quote(fileID))
+		}
+	}
+
+	for _, edge := range report.Edges {
+		fmt.Fprintf(&b, "\t%s -- %s [label=\"%.1f%%\"];\n", strconv.Quote(edge.FileA), strconv.Quote(edge.FileB), edge.Similarity)
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// uniqueFileIDs removes empty and repeated file IDs keeping the order
+func uniqueFileIDs(fileIDs []string) []string {
+	seen := make(map[string]bool, len(fileIDs))
+	result := make([]string, 0, len(fileIDs))
+	for _, fileID := range fileIDs {
+		if fileID == "" || seen[fileID] {
+			continue
+		}
+		seen[fileID] = true
+		result = append(result, fileID)
+	}
+	return result
+}
+
+// disjointSet is a union-find structure over indices
+type disjointSet struct {
+	parent []int
+}
+
+func newDisjointSet(size int) *disjointSet {
+	parent := make([]int, size)
+	for i := range parent {
+		parent[i] = i
+	}
+	return &disjointSet{parent: parent}
+}
+
+func (s *disjointSet) find(i int) int {
+	for s.parent[i] != i {
+		s.parent[i] = s.parent[s.parent[i]]
+		i = s.parent[i]
+	}
+	return i
+}
+
+func (s *disjointSet) union(i, j int) {
+	s.parent[s.find(i)] = s.find(j)
+}
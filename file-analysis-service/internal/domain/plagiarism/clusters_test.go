@@ -0,0 +1,93 @@
+package plagiarism
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"fileanalysisservice/internal/interfaces/repository"
+)
+
+func clusterShingles(fileID string, hashes ...string) []repository.ShingleMatch {
+	shingles := make([]repository.ShingleMatch, len(hashes))
+	for i, hash := range hashes {
+		shingles[i] = repository.ShingleMatch{FileID: fileID, ShingleHash: hash}
+	}
+	return shingles
+}
+
+func TestPlagiarismService_SimilarityClusters(t *testing.T) {
+	shingleRepo := NewMockShingleRepository()
+	var shingles []repository.ShingleMatch
+	shingles = append(shingles, clusterShingles("a", "h1", "h2", "h3", "h4")...)
+	shingles = append(shingles, clusterShingles("b", "h1", "h2", "h3", "x1")...)
+	shingles = append(shingles, clusterShingles("c", "h3", "h4", "y1", "y2", "y3", "y4", "y5", "y6")...)
+	shingles = append(shingles, clusterShingles("d", "z1", "z2")...)
+	shingles = append(shingles, clusterShingles("e", "z1", "z2", "z3")...)
+	shingles = append(shingles, clusterShingles("f", "w1")...)
+	shingleRepo.SetMatches(shingles)
+
+	service := NewPlagiarismService(&MockAnalysisRepository{}, shingleRepo, NewMockSignatureRepository())
+
+	report, err := service.SimilarityClusters(context.Background(), []string{"a", "b", "c", "d", "e", "f", "missing", "a"}, "", 50)
+	if err != nil {
+		t.Fatalf("SimilarityClusters() error = %v", err)
+	}
+
+	if len(report.FileIDs) != 6 || len(report.MissingFileIDs) != 1 || report.MissingFileIDs[0] != "missing" {
+		t.Fatalf("FileIDs = %v, MissingFileIDs = %v", report.FileIDs, report.MissingFileIDs)
+	}
+
+	// 3 of 4 shingles of a are in b, 2 of 8 shingles of c are in a
+	if report.Matrix[0][1] != 75 || report.Matrix[2][0] != 25 || report.Matrix[0][2] != 50 || report.Matrix[0][0] != 100 {
+		t.Errorf("Matrix = %v", report.Matrix)
+	}
+
+	if len(report.Edges) != 3 {
+		t.Fatalf("Edges = %+v, want a-b, a-c and d-e", report.Edges)
+	}
+
+	if len(report.Clusters) != 2 {
+		t.Fatalf("Clusters = %+v, want {a, b, c} and {d, e}", report.Clusters)
+	}
+	if strings.Join(report.Clusters[0].FileIDs, ",") != "a,b,c" || strings.Join(report.Clusters[1].FileIDs, ",") != "d,e" {
+		t.Errorf("Clusters = %+v", report.Clusters)
+	}
+	if report.Clusters[1].MaxSimilarity != 100 {
+		t.Errorf("MaxSimilarity = %v, want 100", report.Clusters[1].MaxSimilarity)
+	}
+
+	dot := ClusterDOT(report)
+	for _, want := range []string{"graph similarity {", "subgraph cluster_1", `"a" -- "b" [label="75.0%"]`, "\t\"f\";\n"} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("ClusterDOT() = %s, want it to contain %q", dot, want)
+		}
+	}
+}
+
+func TestPlagiarismService_SimilarityClusters_Validation(t *testing.T) {
+	service := NewPlagiarismService(&MockAnalysisRepository{}, NewMockShingleRepository(), NewMockSignatureRepository())
+
+	tests := []struct {
+		name      string
+		fileIDs   []string
+		algorithm string
+		threshold float64
+		want      error
+	}{
+		{"one file", []string{"a", "a"}, "", 50, ErrInvalidFileSet},
+		{"zero threshold", []string{"a", "b"}, "", 0, ErrInvalidThreshold},
+		{"threshold above 100", []string{"a", "b"}, "", 101, ErrInvalidThreshold},
+		{"unknown algorithm", []string{"a", "b"}, "unknown", 50, ErrUnknownAlgorithm},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.SimilarityClusters(context.Background(), tt.fileIDs, tt.algorithm, tt.threshold)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("SimilarityClusters() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
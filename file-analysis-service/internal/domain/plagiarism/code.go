@@ -0,0 +1,355 @@
+package plagiarism
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"fileanalysisservice/internal/domain/analysis"
+)
+
+// Programming languages of the source code mode
+const (
+	CodeLanguageGo     = "go"
+	CodeLanguagePython = "python"
+	CodeLanguageJava   = "java"
+	CodeLanguageC      = "c"
+)
+
+// CodeShingleSize is the amount of code tokens in a shingle, a single statement is usually several tokens long
+const CodeShingleSize = 10
+
+// Normalized code tokens: names and literals are replaced, so renaming a variable or changing a constant
+// doesn't change the fingerprints
+const (
+	codeIdentifier = "$id"
+	codeNumber     = "$num"
+	codeString     = "$str"
+	codeChar       = "$char"
+)
+
+// codeKeyPrefix separates the fingerprints of source code from the fingerprints of natural text
+const codeKeyPrefix = "code:"
+
+// ErrUnknownCodeLanguage is returned for a programming language without a tokenizer
+var ErrUnknownCodeLanguage = errors.New("unknown programming language")
+
+// IsCodeLanguage reports whether source code in the language can be tokenized
+func IsCodeLanguage(language string) bool {
+	_, ok := codeLexers[language]
+	return ok
+}
+
+// TokenizeCode splits source code into normalized tokens: keywords, operators and names of the standard library
+// are kept, other identifiers and literals are replaced by placeholders, comments are dropped.
+// Go code is tokenized by walking its syntax tree, code that doesn't parse and other languages by a lexer.
+// Tokens are ordered by position and carry the lines of the original code.
+func TokenizeCode(language string, source string) ([]Token, error) {
+	lexer, ok := codeLexers[language]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCodeLanguage, language)
+	}
+
+	var tokens []Token
+	if language == CodeLanguageGo {
+		tokens = tokenizeGo(source)
+	}
+	if tokens == nil {
+		tokens = lexer.tokenize(source)
+	}
+
+	setCodePositions(source, tokens)
+	return tokens, nil
+}
+
+// codeLexer splits the source code of a C-like or Python-like language into tokens
+type codeLexer struct {
+	keywords      map[string]bool // Ключевые слова и имена стандартной библиотеки, сохраняются как есть
+	lineComment   string
+	blockComments bool   // Комментарии /* ... */
+	quotes        string // Кавычки строковых литералов
+	charQuote     rune   // Кавычка символьного литерала, 0 если ее нет
+	tripleQuotes  bool   // Строки в тройных кавычках (Python)
+	stringPrefix  map[string]bool
+}
+
+// codeOperators are the operators of several characters, longer ones first
+var codeOperators = []string{
+	">>>=", "<<=", ">>=", ">>>", "...", "**=", "//=", "&^=",
+	"->", "++", "--", "&&", "||", "==", "!=", "<=", ">=", "+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=",
+	"<<", ">>", "::", "**", "//", ":=", "<-", "&^",
+}
+
+var codeLexers = map[string]*codeLexer{
+	CodeLanguageGo: {
+		keywords: wordSet(
+			"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for", "func",
+			"go", "goto", "if", "import", "interface", "map", "package", "range", "return", "select", "struct",
+			"switch", "type", "var",
+			"bool", "byte", "complex64", "complex128", "error", "float32", "float64", "int", "int8", "int16",
+			"int32", "int64", "rune", "string", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "any",
+			"true", "false", "iota", "nil", "append", "cap", "close", "copy", "delete", "len", "make", "new",
+			"panic", "print", "println", "recover", "min", "max", "fmt", "strings", "strconv", "sort", "os",
+		),
+		lineComment:   "//",
+		blockComments: true,
+		quotes:        "\"`",
+		charQuote:     '\'',
+	},
+	CodeLanguagePython: {
+		keywords: wordSet(
+			"and", "as", "assert", "async", "await", "break", "class", "continue", "def", "del", "elif", "else",
+			"except", "finally", "for", "from", "global", "if", "import", "in", "is", "lambda", "nonlocal", "not",
+			"or", "pass", "raise", "return", "try", "while", "with", "yield", "None", "True", "False", "self",
+			"print", "input", "len", "range", "enumerate", "zip", "map", "filter", "sorted", "reversed", "sum",
+			"min", "max", "abs", "int", "float", "str", "bool", "list", "dict", "set", "tuple", "open", "append",
+			"split", "join", "strip", "isinstance", "super", "__init__", "__name__", "__main__",
+		),
+		lineComment:  "#",
+		quotes:       "\"'",
+		tripleQuotes: true,
+		stringPrefix: wordSet("r", "u", "b", "f", "br", "rb", "fr", "rf"),
+	},
+	CodeLanguageJava: {
+		keywords: wordSet(
+			"abstract", "assert", "boolean", "break", "byte", "case", "catch", "char", "class", "const", "continue",
+			"default", "do", "double", "else", "enum", "extends", "final", "finally", "float", "for", "goto", "if",
+			"implements", "import", "instanceof", "int", "interface", "long", "native", "new", "package", "private",
+			"protected", "public", "return", "short", "static", "strictfp", "super", "switch", "synchronized",
+			"this", "throw", "throws", "transient", "try", "var", "void", "volatile", "while", "record", "true",
+			"false", "null", "String", "Object", "Integer", "Long", "Double", "Boolean", "Character", "Math",
+			"System", "out", "in", "println", "print", "printf", "length", "size", "get", "add", "put",
+			"List", "ArrayList", "Map", "HashMap", "Set", "HashSet", "Scanner", "Arrays", "Collections",
+		),
+		lineComment:   "//",
+		blockComments: true,
+		quotes:        "\"",
+		charQuote:     '\'',
+	},
+	CodeLanguageC: {
+		keywords: wordSet(
+			"auto", "break", "case", "char", "const", "continue", "default", "do", "double", "else", "enum",
+			"extern", "float", "for", "goto", "if", "inline", "int", "long", "register", "restrict", "return",
+			"short", "signed", "sizeof", "static", "struct", "switch", "typedef", "union", "unsigned", "void",
+			"volatile", "while", "bool", "true", "false", "NULL", "size_t", "FILE", "include", "define",
+			"printf", "scanf", "puts", "gets", "fgets", "fprintf", "malloc", "calloc", "realloc", "free",
+			"memcpy", "memset", "strlen", "strcpy", "strcmp", "strcat", "fopen", "fclose", "exit", "stdin",
+			"stdout", "stderr", "main",
+		),
+		lineComment:   "//",
+		blockComments: true,
+		quotes:        "\"",
+		charQuote:     '\'',
+	},
+}
+
+// tokenize splits the code into tokens with byte offsets, rune offsets and lines are set by the caller
+func (l *codeLexer) tokenize(source string) []Token {
+	var tokens []Token
+	emit := func(text string, start, end int) {
+		tokens = append(tokens, Token{Text: text, StartPos: start, EndPos: end})
+	}
+
+	for i := 0; i < len(source); {
+		r, size := utf8.DecodeRuneInString(source[i:])
+		rest := source[i:]
+
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case l.lineComment != "" && strings.HasPrefix(rest, l.lineComment):
+			i += lineEnd(rest)
+		case l.blockComments && strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				i = len(source)
+			} else {
+				i += end + 4
+			}
+		case isCodeLetter(r):
+			end := i + size
+			for end < len(source) {
+				next, nextSize := utf8.DecodeRuneInString(source[end:])
+				if !isCodeLetter(next) && !unicode.IsDigit(next) {
+					break
+				}
+				end += nextSize
+			}
+
+			word := source[i:end]
+			if end < len(source) && strings.ContainsRune(l.quotes, rune(source[end])) && l.stringPrefix[strings.ToLower(word)] {
+				// A string with a prefix such as r"..." or f"..."
+				stringEnd := end + l.stringLength(source[end:])
+				emit(codeString, i, stringEnd)
+				i = stringEnd
+				continue
+			}
+
+			if l.keywords[word] {
+				emit(word, i, end)
+			} else {
+				emit(codeIdentifier, i, end)
+			}
+			i = end
+		case unicode.IsDigit(r) || (r == '.' && len(rest) > 1 && rest[1] >= '0' && rest[1] <= '9'):
+			end := i + numberLength(rest)
+			emit(codeNumber, i, end)
+			i = end
+		case strings.ContainsRune(l.quotes, r):
+			end := i + l.stringLength(rest)
+			emit(codeString, i, end)
+			i = end
+		case l.charQuote != 0 && r == l.charQuote:
+			end := i + quotedLength(rest, byte(r))
+			emit(codeChar, i, end)
+			i = end
+		default:
+			operator := string(r)
+			for _, candidate := range codeOperators {
+				if strings.HasPrefix(rest, candidate) {
+					operator = candidate
+					break
+				}
+			}
+			emit(operator, i, i+len(operator))
+			i += len(operator)
+		}
+	}
+
+	return tokens
+}
+
+// stringLength returns the length of the string literal at the start of the code
+func (l *codeLexer) stringLength(code string) int {
+	quote := code[0]
+	if l.tripleQuotes && strings.HasPrefix(code, strings.Repeat(string(quote), 3)) {
+		end := strings.Index(code[3:], strings.Repeat(string(quote), 3))
+		if end < 0 {
+			return len(code)
+		}
+		return end + 6
+	}
+
+	// Go raw strings span lines and have no escapes
+	if quote == '`' {
+		end := strings.IndexByte(code[1:], '`')
+		if end < 0 {
+			return len(code)
+		}
+		return end + 2
+	}
+
+	return quotedLength(code, quote)
+}
+
+// quotedLength returns the length of the literal in quotes at the start of the code, an unterminated literal ends
+// with the line
+func quotedLength(code string, quote byte) int {
+	for i := 1; i < len(code); i++ {
+		switch code[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		case '\n':
+			return i
+		}
+	}
+
+	return len(code)
+}
+
+// numberLength returns the length of the number literal at the start of the code: 42, 0x2A, 1.5e-3, 10L
+func numberLength(code string) int {
+	i := 0
+	for i < len(code) {
+		c := code[i]
+		switch {
+		case c >= '0' && c <= '9', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_', c == '.':
+			i++
+		case (c == '+' || c == '-') && i > 0 && strings.ContainsRune("eEpP", rune(code[i-1])) && !strings.HasPrefix(code, "0x") && !strings.HasPrefix(code, "0X"):
+			i++
+		default:
+			return i
+		}
+	}
+
+	return i
+}
+
+// lineEnd returns the position of the end of the first line
+func lineEnd(code string) int {
+	if end := strings.IndexByte(code, '\n'); end >= 0 {
+		return end
+	}
+	return len(code)
+}
+
+func isCodeLetter(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+// setCodePositions fills rune offsets and lines of tokens ordered by position
+func setCodePositions(source string, tokens []Token) {
+	lineStarts := []int{0}
+	for i := 0; i < len(source); i++ {
+		if source[i] == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	line := func(pos int) int {
+		return sort.Search(len(lineStarts), func(i int) bool {
+			return lineStarts[i] > pos
+		})
+	}
+
+	pos, runes := 0, 0
+	for i := range tokens {
+		runes += utf8.RuneCountInString(source[pos:tokens[i].StartPos])
+		pos = tokens[i].StartPos
+
+		tokens[i].StartRune = runes
+		tokens[i].EndRune = runes + utf8.RuneCountInString(source[tokens[i].StartPos:tokens[i].EndPos])
+		tokens[i].StartLine = line(tokens[i].StartPos)
+		tokens[i].EndLine = line(max(tokens[i].EndPos-1, tokens[i].StartPos))
+	}
+}
+
+// codeAlgorithm fingerprints code tokens, its key differs from the key of the wrapped algorithm,
+// so source code is never compared with natural text
+type codeAlgorithm struct {
+	Algorithm
+	language string
+}
+
+func (c *codeAlgorithm) Info() analysis.AlgorithmInfo {
+	info := c.Algorithm.Info()
+	info.CodeLanguage = c.language
+	return info
+}
+
+func (c *codeAlgorithm) Key() string {
+	return codeKeyPrefix + c.Algorithm.Key()
+}
+
+// CodeAlgorithm returns the registered algorithm with the given name adapted to the source code in the language.
+// Full shingling compares shingles of CodeShingleSize tokens, as a few tokens match in any program.
+func (ps *Service) CodeAlgorithm(name string, language string) (Algorithm, error) {
+	if !IsCodeLanguage(language) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCodeLanguage, language)
+	}
+
+	algorithm, err := ps.Algorithm(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if algorithm.Info().Name == AlgorithmShingles {
+		algorithm = NewShingling(CodeShingleSize)
+	}
+
+	return &codeAlgorithm{Algorithm: algorithm, language: language}, nil
+}
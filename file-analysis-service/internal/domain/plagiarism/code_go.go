@@ -0,0 +1,206 @@
+package plagiarism
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+)
+
+// goTokenizer turns a Go syntax tree into normalized tokens. Parentheses, comments and formatting are not part
+// of the tree, so they never affect the fingerprints. x++ is tokenized as x += 1.
+type goTokenizer struct {
+	fileSet  *token.FileSet
+	file     *token.File
+	packages map[string]bool // Имена импортированных пакетов, сохраняются как есть
+	tokens   []Token
+}
+
+// tokenizeGo tokenizes a Go source file, nil if it doesn't parse
+func tokenizeGo(source string) []Token {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "", source, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	t := &goTokenizer{
+		fileSet:  fileSet,
+		file:     fileSet.File(file.Pos()),
+		packages: make(map[string]bool),
+	}
+
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path[lastSlash(path)+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		t.packages[name] = true
+	}
+
+	// The package clause and imports are the same in most submissions
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			continue
+		}
+		ast.Inspect(decl, t.visit)
+	}
+
+	// The tree is walked depth first, binary operators come before their operands
+	sort.SliceStable(t.tokens, func(i, j int) bool {
+		return t.tokens[i].StartPos < t.tokens[j].StartPos
+	})
+
+	if t.tokens == nil {
+		return []Token{}
+	}
+	return t.tokens
+}
+
+func lastSlash(path string) int {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == '/' {
+			return i
+		}
+	}
+	return -1
+}
+
+// emit adds a token spanning length bytes from the position
+func (t *goTokenizer) emit(text string, pos token.Pos, length int) {
+	if !pos.IsValid() {
+		return
+	}
+
+	start := t.file.Offset(pos)
+	t.tokens = append(t.tokens, Token{Text: text, StartPos: start, EndPos: start + length})
+}
+
+// emitNode adds a token spanning the whole node
+func (t *goTokenizer) emitNode(text string, node ast.Node) {
+	start, end := t.file.Offset(node.Pos()), t.file.Offset(node.End())
+	t.tokens = append(t.tokens, Token{Text: text, StartPos: start, EndPos: end})
+}
+
+func (t *goTokenizer) visit(node ast.Node) bool {
+	switch n := node.(type) {
+	case *ast.Ident:
+		switch {
+		case n.Name == "_":
+			t.emitNode("_", n)
+		case types.Universe.Lookup(n.Name) != nil, t.packages[n.Name]:
+			t.emitNode(n.Name, n)
+		default:
+			t.emitNode(codeIdentifier, n)
+		}
+	case *ast.BasicLit:
+		switch n.Kind {
+		case token.STRING:
+			t.emitNode(codeString, n)
+		case token.CHAR:
+			t.emitNode(codeChar, n)
+		default:
+			t.emitNode(codeNumber, n)
+		}
+	case *ast.SelectorExpr:
+		// Functions of imported packages keep their names: fmt.Println stays as is
+		if x, ok := n.X.(*ast.Ident); ok && t.packages[x.Name] {
+			t.emitNode(x.Name+"."+n.Sel.Name, n)
+			return false
+		}
+		t.emit(".", n.Sel.Pos()-1, 1)
+	case *ast.FuncDecl:
+		t.emit("func", n.Type.Func, len("func"))
+	case *ast.FuncLit:
+		t.emit("func", n.Type.Func, len("func"))
+	case *ast.GenDecl:
+		t.emit(n.Tok.String(), n.TokPos, len(n.Tok.String()))
+	case *ast.BlockStmt:
+		t.emit("{", n.Lbrace, 1)
+		t.emit("}", n.Rbrace, 1)
+	case *ast.CallExpr:
+		t.emit("call", n.Lparen, 1)
+	case *ast.IndexExpr:
+		t.emit("[", n.Lbrack, 1)
+	case *ast.IndexListExpr:
+		t.emit("[", n.Lbrack, 1)
+	case *ast.SliceExpr:
+		t.emit("[:]", n.Lbrack, 1)
+	case *ast.CompositeLit:
+		t.emit("{", n.Lbrace, 1)
+		t.emit("}", n.Rbrace, 1)
+	case *ast.StarExpr:
+		t.emit("*", n.Star, 1)
+	case *ast.UnaryExpr:
+		t.emit(n.Op.String(), n.OpPos, len(n.Op.String()))
+	case *ast.BinaryExpr:
+		t.emit(n.Op.String(), n.OpPos, len(n.Op.String()))
+	case *ast.KeyValueExpr:
+		t.emit(":", n.Colon, 1)
+	case *ast.TypeAssertExpr:
+		t.emit(".(", n.Lparen-1, 2)
+	case *ast.ArrayType:
+		t.emit("[]", n.Lbrack, 1)
+	case *ast.MapType:
+		t.emit("map", n.Map, len("map"))
+	case *ast.ChanType:
+		t.emit("chan", n.Begin, len("chan"))
+	case *ast.StructType:
+		t.emit("struct", n.Struct, len("struct"))
+	case *ast.InterfaceType:
+		t.emit("interface", n.Interface, len("interface"))
+	case *ast.Ellipsis:
+		t.emit("...", n.Ellipsis, 3)
+	case *ast.AssignStmt:
+		// := and var declarations are told apart, = and op= are kept
+		t.emit(n.Tok.String(), n.TokPos, len(n.Tok.String()))
+	case *ast.IncDecStmt:
+		op := "+="
+		if n.Tok == token.DEC {
+			op = "-="
+		}
+		t.emit(op, n.TokPos, 2)
+		t.emit(codeNumber, n.TokPos, 2)
+	case *ast.SendStmt:
+		t.emit("<-", n.Arrow, 2)
+	case *ast.GoStmt:
+		t.emit("go", n.Go, len("go"))
+	case *ast.DeferStmt:
+		t.emit("defer", n.Defer, len("defer"))
+	case *ast.ReturnStmt:
+		t.emit("return", n.Return, len("return"))
+	case *ast.BranchStmt:
+		t.emit(n.Tok.String(), n.TokPos, len(n.Tok.String()))
+	case *ast.IfStmt:
+		t.emit("if", n.If, len("if"))
+		if n.Else != nil {
+			// The else keyword isn't in the tree, it precedes the else branch
+			t.emit("else", n.Else.Pos(), 0)
+		}
+	case *ast.CaseClause:
+		t.emit("case", n.Case, len("case"))
+	case *ast.CommClause:
+		t.emit("case", n.Case, len("case"))
+	case *ast.SwitchStmt:
+		t.emit("switch", n.Switch, len("switch"))
+	case *ast.TypeSwitchStmt:
+		t.emit("switch", n.Switch, len("switch"))
+	case *ast.SelectStmt:
+		t.emit("select", n.Select, len("select"))
+	case *ast.ForStmt:
+		t.emit("for", n.For, len("for"))
+	case *ast.RangeStmt:
+		t.emit("for", n.For, len("for"))
+		t.emit("range", n.TokPos, len(n.Tok.String()))
+	case *ast.LabeledStmt:
+		t.emit(":", n.Colon, 1)
+	}
+
+	return true
+}
@@ -0,0 +1,270 @@
+package plagiarism
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"fileanalysisservice/internal/interfaces/repository"
+)
+
+const goOriginal = `package main
+
+import "fmt"
+
+// sum adds up the numbers
+func sum(numbers []int) int {
+	total := 0
+	for _, n := range numbers {
+		total += n
+	}
+	return total
+}
+
+func main() {
+	fmt.Println(sum([]int{1, 2, 3}))
+}
+`
+
+// goRenamed is goOriginal with renamed identifiers, other constants, comments, parentheses and x++
+const goRenamed = `package solution
+
+import "fmt"
+
+func add(values []int) int {
+	result := 0 // accumulator
+	for _, value := range values {
+		result += (value)
+	}
+	return result
+}
+
+/* entry point */
+func main() {
+	fmt.Println(add([]int{4, 5, 6}))
+}
+`
+
+func codeTexts(t *testing.T, language string, source string) []string {
+	t.Helper()
+
+	tokens, err := TokenizeCode(language, source)
+	if err != nil {
+		t.Fatalf("TokenizeCode(%s) error = %v", language, err)
+	}
+
+	texts := make([]string, len(tokens))
+	for i, token := range tokens {
+		texts[i] = token.Text
+	}
+	return texts
+}
+
+func TestTokenizeCode_RenamedIdentifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		original string
+		renamed  string
+	}{
+		{"go", CodeLanguageGo, goOriginal, goRenamed},
+		{
+			"python",
+			CodeLanguagePython,
+			"def mean(xs):\n    # average\n    total = sum(xs)\n    return total / len(xs)\n",
+			"def average(values):\n    s = sum(values)  # sum first\n    return s / len(values)\n",
+		},
+		{
+			"java",
+			CodeLanguageJava,
+			"int count = 0;\nfor (int i = 0; i < n; i++) { count += a[i]; }",
+			"int c = 10; /* start */\nfor (int j = 0; j < len; j++) {\n    c += arr[j];\n}",
+		},
+		{
+			"c without parsing go",
+			CodeLanguageC,
+			"printf(\"%d\\n\", x * 2);",
+			"printf(\"result: %d\\n\", y * 3); // print",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := codeTexts(t, tt.language, tt.original)
+			renamed := codeTexts(t, tt.language, tt.renamed)
+
+			if !slices.Equal(original, renamed) {
+				t.Errorf("Tokens differ:\n%v\n%v", original, renamed)
+			}
+		})
+	}
+}
+
+func TestTokenizeCode_Go(t *testing.T) {
+	texts := codeTexts(t, CodeLanguageGo, goOriginal)
+
+	// Package clause and imports are dropped, builtins and package functions keep their names
+	if texts[0] != "func" || slices.Contains(texts, "package") || slices.Contains(texts, "import") {
+		t.Errorf("Tokens = %v, want the first declaration first", texts)
+	}
+	for _, name := range []string{"int", "fmt.Println", "range", codeIdentifier, codeNumber} {
+		if !slices.Contains(texts, name) {
+			t.Errorf("Tokens = %v, want %q", texts, name)
+		}
+	}
+	if slices.Contains(texts, "sum") || slices.Contains(texts, "total") {
+		t.Errorf("Tokens = %v, want identifiers replaced", texts)
+	}
+
+	// x++ is the same as x += 1
+	increment := codeTexts(t, CodeLanguageGo, "package p\nfunc f(x int) { x++ }")
+	addition := codeTexts(t, CodeLanguageGo, "package p\nfunc f(y int) { y += 1 }")
+	if !slices.Equal(increment, addition) {
+		t.Errorf("Tokens of x++ = %v, of y += 1 = %v", increment, addition)
+	}
+
+	// Code that doesn't parse is tokenized by the lexer
+	broken := codeTexts(t, CodeLanguageGo, "func broken( {\n\ttotal := 0")
+	if !slices.Equal(broken, []string{"func", codeIdentifier, "(", "{", codeIdentifier, ":=", codeNumber}) {
+		t.Errorf("Tokens of unparsable code = %v", broken)
+	}
+}
+
+func TestTokenizeCode_Positions(t *testing.T) {
+	source := "# коммент\nимя = 'строка'\nx = \"\"\"много\nстрок\"\"\"\n"
+	tokens, err := TokenizeCode(CodeLanguagePython, source)
+	if err != nil {
+		t.Fatalf("TokenizeCode() error = %v", err)
+	}
+
+	want := []struct {
+		text       string
+		original   string
+		start, end int
+	}{
+		{codeIdentifier, "имя", 2, 2},
+		{"=", "=", 2, 2},
+		{codeString, "'строка'", 2, 2},
+		{codeIdentifier, "x", 3, 3},
+		{"=", "=", 3, 3},
+		{codeString, "\"\"\"много\nстрок\"\"\"", 3, 4},
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("TokenizeCode() = %+v, want %d tokens", tokens, len(want))
+	}
+
+	runes := []rune(source)
+	for i, w := range want {
+		token := tokens[i]
+		if token.Text != w.text || source[token.StartPos:token.EndPos] != w.original {
+			t.Errorf("Token %d = %q at %q, want %q at %q", i, token.Text, source[token.StartPos:token.EndPos], w.text, w.original)
+		}
+		if string(runes[token.StartRune:token.EndRune]) != w.original {
+			t.Errorf("Token %d runes = %q, want %q", i, string(runes[token.StartRune:token.EndRune]), w.original)
+		}
+		if token.StartLine != w.start || token.EndLine != w.end {
+			t.Errorf("Token %d lines = %d-%d, want %d-%d", i, token.StartLine, token.EndLine, w.start, w.end)
+		}
+	}
+}
+
+func TestTokenizeCode_UnknownLanguage(t *testing.T) {
+	_, err := TokenizeCode("cobol", "DISPLAY 'HELLO'.")
+	if !errors.Is(err, ErrUnknownCodeLanguage) {
+		t.Errorf("TokenizeCode() error = %v, want ErrUnknownCodeLanguage", err)
+	}
+}
+
+func TestPlagiarismService_AnalyzeCode(t *testing.T) {
+	shingleRepo := NewMockShingleRepository()
+	signatureRepo := NewMockSignatureRepository()
+	service := NewPlagiarismService(&MockAnalysisRepository{}, shingleRepo, signatureRepo)
+	service.RegisterAlgorithm(NewWinnowing(10, 5))
+	ctx := context.Background()
+
+	for _, name := range []string{AlgorithmShingles, AlgorithmWinnowing} {
+		t.Run(name, func(t *testing.T) {
+			algorithm, err := service.CodeAlgorithm(name, CodeLanguageGo)
+			if err != nil {
+				t.Fatalf("CodeAlgorithm() error = %v", err)
+			}
+			textAlgorithm, _ := service.Algorithm(name)
+			if algorithm.Key() == textAlgorithm.Key() || algorithm.Info().CodeLanguage != CodeLanguageGo {
+				t.Errorf("Code algorithm key = %s, info = %+v, want it to differ from the text one", algorithm.Key(), algorithm.Info())
+			}
+
+			// The earlier submission is the original program
+			tokens, _ := TokenizeCode(CodeLanguageGo, goOriginal)
+			var matches []repository.ShingleMatch
+			for _, fingerprint := range algorithm.Fingerprints(tokens) {
+				matches = append(matches, repository.ShingleMatch{
+					FileID:      "original",
+					ShingleHash: fingerprint.Hash,
+					ShingleText: fingerprint.Text,
+					StartLine:   fingerprint.StartLine,
+					EndLine:     fingerprint.EndLine,
+				})
+			}
+			shingleRepo.SetMatches(matches)
+			signatureRepo.SetCandidates([]repository.LSHCandidate{{FileID: "original", SharedBands: 10}})
+
+			report, err := service.AnalyzeCode(ctx, goRenamed, "renamed", name, CodeLanguageGo, "", nil)
+			if err != nil {
+				t.Fatalf("AnalyzeCode() error = %v", err)
+			}
+			if report.UniquenessPercentage != 0 || len(report.Matches) != 1 {
+				t.Fatalf("Uniqueness = %.2f with %d matches, want the renamed program to match completely", report.UniquenessPercentage, len(report.Matches))
+			}
+
+			// Winnowing selects some of the k-grams, the last one may end before the closing brace
+			match := report.Matches[0]
+			if match.StartLine != 5 || match.EndLine < 15 || match.EndLine > 16 {
+				t.Errorf("Match lines = %d-%d, want 5-16", match.StartLine, match.EndLine)
+			}
+			if len(match.Passages) != 1 || match.Passages[0].Source.StartLine != 6 || match.Passages[0].Source.EndLine < 15 {
+				t.Errorf("Passages = %+v, want the source lines from 6", match.Passages)
+			}
+			if report.Algorithm.CodeLanguage != CodeLanguageGo {
+				t.Errorf("Report algorithm = %+v, want the code language", report.Algorithm)
+			}
+		})
+	}
+
+	_, err := service.AnalyzeCode(ctx, goRenamed, "renamed", "", "cobol", "", nil)
+	if !errors.Is(err, ErrUnknownCodeLanguage) {
+		t.Errorf("AnalyzeCode() error = %v, want ErrUnknownCodeLanguage", err)
+	}
+}
+
+func TestPlagiarismService_CodeTemplates(t *testing.T) {
+	shingleRepo := NewMockShingleRepository()
+	signatureRepo := NewMockSignatureRepository()
+	service := NewPlagiarismService(&MockAnalysisRepository{}, shingleRepo, signatureRepo)
+	ctx := context.Background()
+
+	err := service.RegisterTemplate(ctx, "hw1", "starter", goOriginal, CodeLanguageGo)
+	if err != nil {
+		t.Fatalf("RegisterTemplate() error = %v", err)
+	}
+
+	// The starter code is excluded from the code analyses of the assignment only
+	report, err := service.AnalyzeCode(ctx, goRenamed, "renamed", "", CodeLanguageGo, "hw1", nil)
+	if err != nil {
+		t.Fatalf("AnalyzeCode() error = %v", err)
+	}
+	if report.TotalShingles != 0 || report.TemplateShingles == 0 || len(report.ExcludedRanges) != 1 {
+		t.Fatalf("Total shingles = %d, template shingles = %d, ranges = %+v, want the starter code excluded", report.TotalShingles, report.TemplateShingles, report.ExcludedRanges)
+	}
+	if span := report.ExcludedRanges[0].Span; span.StartLine != 5 || span.EndLine != 16 {
+		t.Errorf("Template lines = %d-%d, want 5-16", span.StartLine, span.EndLine)
+	}
+
+	report, err = service.AnalyzePlagiarism(ctx, goRenamed, "renamed", "", ExclusionOptions{Assignment: "hw1"}, nil)
+	if err != nil {
+		t.Fatalf("AnalyzePlagiarism() error = %v", err)
+	}
+	if report.TemplateShingles != 0 {
+		t.Errorf("Template shingles = %d in a text analysis, want 0", report.TemplateShingles)
+	}
+}
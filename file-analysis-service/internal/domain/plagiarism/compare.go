@@ -0,0 +1,88 @@
+package plagiarism
+
+import (
+	"time"
+
+	"fileanalysisservice/internal/domain/analysis"
+	"fileanalysisservice/internal/interfaces/repository"
+)
+
+// Compare compares two documents directly, without the stored corpus.
+// Nothing is stored, so documents can be compared any number of times.
+func (ps *Service) Compare(fileA, textA, fileB, textB string, algorithmName string) (*analysis.Comparison, error) {
+	algorithm, err := ps.Algorithm(algorithmName)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprintsA := algorithm.Fingerprints(ps.textProcessor.Tokenize(textA))
+	fingerprintsB := algorithm.Fingerprints(ps.textProcessor.Tokenize(textB))
+
+	hashesA := fingerprintHashes(fingerprintsA)
+	hashesB := fingerprintHashes(fingerprintsB)
+
+	common := 0
+	for hash := range hashesA {
+		if hashesB[hash] {
+			common++
+		}
+	}
+
+	// B is compared like a stored source document, so passages carry positions in both texts
+	sourceB := make([]repository.ShingleMatch, len(fingerprintsB))
+	for i, fingerprint := range fingerprintsB {
+		sourceB[i] = repository.ShingleMatch{
+			FileID:      fileB,
+			ShingleHash: fingerprint.Hash,
+			ShingleText: fingerprint.Text,
+			StartPos:    fingerprint.StartPos,
+			EndPos:      fingerprint.EndPos,
+			StartRune:   fingerprint.StartRune,
+			EndRune:     fingerprint.EndRune,
+		}
+	}
+
+	return &analysis.Comparison{
+		FileA:          fileA,
+		FileB:          fileB,
+		Algorithm:      algorithm.Info(),
+		SimilarityAB:   coveredPercentage(fingerprintsA, hashesB),
+		SimilarityBA:   coveredPercentage(fingerprintsB, hashesA),
+		Jaccard:        percentage(common, len(hashesA)+len(hashesB)-common),
+		ContainmentAB:  percentage(common, len(hashesA)),
+		ContainmentBA:  percentage(common, len(hashesB)),
+		TotalShinglesA: len(fingerprintsA),
+		TotalShinglesB: len(fingerprintsB),
+		CommonShingles: common,
+		Passages:       findPassages(textA, fingerprintsA, sourceB),
+		ComparedAt:     time.Now(),
+	}, nil
+}
+
+// fingerprintHashes returns the set of fingerprint hashes
+func fingerprintHashes(fingerprints []Fingerprint) map[string]bool {
+	hashes := make(map[string]bool, len(fingerprints))
+	for _, fingerprint := range fingerprints {
+		hashes[fingerprint.Hash] = true
+	}
+	return hashes
+}
+
+// coveredPercentage returns the share of fingerprints whose hash is in the set
+func coveredPercentage(fingerprints []Fingerprint, hashes map[string]bool) float64 {
+	covered := 0
+	for _, fingerprint := range fingerprints {
+		if hashes[fingerprint.Hash] {
+			covered++
+		}
+	}
+	return percentage(covered, len(fingerprints))
+}
+
+// percentage returns part/total in percent, 0 for an empty total
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
@@ -0,0 +1,62 @@
+package plagiarism
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPlagiarismService_Compare(t *testing.T) {
+	shingleRepo := NewMockShingleRepository()
+	signatureRepo := NewMockSignatureRepository()
+	service := NewPlagiarismService(&MockAnalysisRepository{}, shingleRepo, signatureRepo)
+
+	textA := "Студент переписал введение курсовой работы из чужого отчета. Выводы написаны самостоятельно и отличаются."
+	textB := "Введение курсовой работы из чужого отчета было опубликовано раньше. Остальной текст источника другой."
+
+	comparison, err := service.Compare("fileA", textA, "fileB", textB, "")
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+
+	if comparison.CommonShingles == 0 || len(comparison.Passages) == 0 {
+		t.Fatalf("Compare() found no common shingles or passages: %+v", comparison)
+	}
+
+	passage := comparison.Passages[0]
+	if got := textB[passage.Source.StartPos:passage.Source.EndPos]; got != "Введение курсовой работы из чужого отчета" {
+		t.Errorf("Passage source span = %q", got)
+	}
+	if got := textA[passage.Document.StartPos:passage.Document.EndPos]; got != "введение курсовой работы из чужого отчета" {
+		t.Errorf("Passage document span = %q", got)
+	}
+
+	if comparison.Jaccard <= 0 || comparison.Jaccard > comparison.ContainmentAB || comparison.Jaccard > comparison.ContainmentBA {
+		t.Errorf("Jaccard = %v must be positive and not above containment %v / %v", comparison.Jaccard, comparison.ContainmentAB, comparison.ContainmentBA)
+	}
+
+	if comparison.SimilarityAB <= 0 || comparison.SimilarityAB > 100 || comparison.SimilarityBA <= 0 || comparison.SimilarityBA > 100 {
+		t.Errorf("Similarity = %v / %v, want between 0 and 100", comparison.SimilarityAB, comparison.SimilarityBA)
+	}
+
+	if len(shingleRepo.storedShingles) != 0 || len(signatureRepo.signatures) != 0 {
+		t.Error("Compare() must not store anything")
+	}
+}
+
+func TestPlagiarismService_Compare_Identical(t *testing.T) {
+	service := NewPlagiarismService(&MockAnalysisRepository{}, NewMockShingleRepository(), NewMockSignatureRepository())
+	text := "Один и тот же документ сравнивается сам с собой целиком"
+
+	comparison, err := service.Compare("fileA", text, "fileB", text, AlgorithmShingles)
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+
+	if comparison.Jaccard != 100 || comparison.SimilarityAB != 100 || comparison.SimilarityBA != 100 {
+		t.Errorf("Comparison of identical texts = %+v, want 100%% everywhere", comparison)
+	}
+
+	if _, err := service.Compare("fileA", text, "fileB", text, "unknown"); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("Compare() error = %v, want ErrUnknownAlgorithm", err)
+	}
+}
@@ -0,0 +1,223 @@
+package plagiarism
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ExclusionQuote        = "quote"
+	ExclusionBlockQuote   = "block_quote"
+	ExclusionBibliography = "bibliography"
+	ExclusionTemplate     = "template"
+)
+
+// bibliographyMinShare is the part of the text before which a bibliography heading is ignored,
+// such a heading is most likely an entry of the table of contents
+const bibliographyMinShare = 0.2
+
+// bibliographyHeadings are the lower case headings of a bibliography or references section
+var bibliographyHeadings = wordSet(
+	"список литературы", "список использованной литературы", "список использованных источников",
+	"список источников", "список использованных источников и литературы", "библиографический список",
+	"библиография", "литература", "использованная литература", "источники",
+	"references", "bibliography", "works cited", "literature", "sources", "reference list",
+)
+
+var (
+	blockQuoteTagRegex = regexp.MustCompile(`(?is)<blockquote[^>]*>.*?</blockquote>`)
+	quotedLineRegex    = regexp.MustCompile(`(?m)^[ \t]*>.*$`)
+	headingLineRegex   = regexp.MustCompile(`(?m)^.+$`)
+	// headingNumberRegex matches numbering and markup before a heading: "5.", "5.1", "V.", "##"
+	headingNumberRegex = regexp.MustCompile(`^(#+|\d+(\.\d+)*\.?|[ivxlc]+\.)\s*`)
+)
+
+// ExclusionOptions selects the regions of a text excluded from the uniqueness score
+type ExclusionOptions struct {
+	Quotes       bool   // Цитаты в кавычках и блочные цитаты
+	Bibliography bool   // Список литературы в конце документа
+	Assignment   string // Задание, текст шаблонов которого не учитывается
+}
+
+// ExcludedRange is a region of the original text excluded from the uniqueness score
+type ExcludedRange struct {
+	Kind      string
+	StartPos  int // Смещения в исходном тексте в байтах
+	EndPos    int
+	StartRune int // Смещения в исходном тексте в символах (рунах)
+	EndRune   int
+	StartLine int // Строки исходного кода, 0 для текста
+	EndLine   int
+}
+
+// contains reports whether the byte span lies within the range
+func (r ExcludedRange) contains(start, end int) bool {
+	return start >= r.StartPos && end <= r.EndPos
+}
+
+// FindExclusions finds quoted spans («...», “...”, "...", block quotes) and the trailing bibliography section
+// selected by the options. Ranges are ordered by position and don't overlap.
+func (tp *TextProcessor) FindExclusions(text string, options ExclusionOptions) []ExcludedRange {
+	var ranges []ExcludedRange
+
+	if options.Quotes {
+		ranges = append(ranges, findQuotes(text)...)
+		ranges = append(ranges, findBlockQuotes(text)...)
+	}
+
+	if options.Bibliography {
+		if start := findBibliography(text); start >= 0 {
+			ranges = append(ranges, ExcludedRange{Kind: ExclusionBibliography, StartPos: start, EndPos: len(text)})
+		}
+	}
+
+	ranges = mergeRanges(ranges)
+	setRuneOffsets(text, ranges)
+	return ranges
+}
+
+// findQuotes finds top level spans in quotation marks, quotes left open at the end of a paragraph are ignored
+func findQuotes(text string) []ExcludedRange {
+	var ranges []ExcludedRange
+	guillemets := 0
+	quoteStart, closing := -1, rune(0)
+
+	open := func(start int, closingMark rune) {
+		if quoteStart < 0 {
+			quoteStart, closing = start, closingMark
+		}
+	}
+	closeQuote := func(end int) {
+		ranges = append(ranges, ExcludedRange{Kind: ExclusionQuote, StartPos: quoteStart, EndPos: end})
+		quoteStart, closing, guillemets = -1, 0, 0
+	}
+
+	previous := rune(0)
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+
+		switch {
+		case r == '\n' && previous == '\n':
+			quoteStart, closing, guillemets = -1, 0, 0
+		case r == '«' && (quoteStart < 0 || closing == '»'):
+			open(i, '»')
+			guillemets++
+		case r == '»' && closing == '»':
+			guillemets--
+			if guillemets == 0 {
+				closeQuote(i + size)
+			}
+		case quoteStart >= 0 && closing == '”' && (r == '”' || r == '“'):
+			closeQuote(i + size)
+		case quoteStart >= 0 && closing == '"' && r == '"':
+			closeQuote(i + size)
+		case r == '“' || r == '„':
+			open(i, '”')
+		case r == '"':
+			open(i, '"')
+		}
+
+		previous = r
+		i += size
+	}
+
+	return ranges
+}
+
+// findBlockQuotes finds HTML block quotes and runs of lines quoted with ">"
+func findBlockQuotes(text string) []ExcludedRange {
+	var ranges []ExcludedRange
+	for _, match := range blockQuoteTagRegex.FindAllStringIndex(text, -1) {
+		ranges = append(ranges, ExcludedRange{Kind: ExclusionBlockQuote, StartPos: match[0], EndPos: match[1]})
+	}
+
+	for _, match := range quotedLineRegex.FindAllStringIndex(text, -1) {
+		// A quoted line right after another one continues the same block quote
+		if last := len(ranges) - 1; last >= 0 && ranges[last].EndPos <= match[0] && strings.TrimSpace(text[ranges[last].EndPos:match[0]]) == "" {
+			ranges[last].EndPos = match[1]
+			continue
+		}
+		ranges = append(ranges, ExcludedRange{Kind: ExclusionBlockQuote, StartPos: match[0], EndPos: match[1]})
+	}
+
+	return ranges
+}
+
+// findBibliography returns the position of the last bibliography heading, -1 if the text has none
+func findBibliography(text string) int {
+	minStart := int(float64(len(text)) * bibliographyMinShare)
+
+	start := -1
+	for _, match := range headingLineRegex.FindAllStringIndex(text, -1) {
+		if match[0] < minStart {
+			continue
+		}
+
+		heading := strings.ToLower(strings.TrimSpace(text[match[0]:match[1]]))
+		heading = headingNumberRegex.ReplaceAllString(heading, "")
+		heading = strings.TrimRight(heading, ":. ")
+		if bibliographyHeadings[heading] {
+			start = match[0]
+		}
+	}
+
+	return start
+}
+
+// mergeRanges orders ranges by position and merges overlapping ones, the earlier range keeps its kind
+func mergeRanges(ranges []ExcludedRange) []ExcludedRange {
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].StartPos < ranges[j].StartPos
+	})
+
+	var merged []ExcludedRange
+	for _, r := range ranges {
+		if last := len(merged) - 1; last >= 0 && r.StartPos < merged[last].EndPos {
+			if r.EndPos > merged[last].EndPos {
+				merged[last].EndPos, merged[last].EndRune, merged[last].EndLine = r.EndPos, r.EndRune, r.EndLine
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+// setRuneOffsets fills rune offsets of ordered non-overlapping ranges in one pass over the text
+func setRuneOffsets(text string, ranges []ExcludedRange) {
+	pos, runes := 0, 0
+	advance := func(to int) int {
+		runes += utf8.RuneCountInString(text[pos:to])
+		pos = to
+		return runes
+	}
+
+	for i := range ranges {
+		ranges[i].StartRune = advance(ranges[i].StartPos)
+		ranges[i].EndRune = advance(ranges[i].EndPos)
+	}
+}
+
+// excludeFingerprints splits fingerprints into the scored ones and the amount of those lying within excluded ranges
+func excludeFingerprints(fingerprints []Fingerprint, ranges []ExcludedRange) ([]Fingerprint, int) {
+	if len(ranges) == 0 {
+		return fingerprints, 0
+	}
+
+	scored := make([]Fingerprint, 0, len(fingerprints))
+	for _, fingerprint := range fingerprints {
+		// Ranges are ordered, the first one ending after the fingerprint start is the only one that may contain it
+		i := sort.Search(len(ranges), func(i int) bool {
+			return ranges[i].EndPos > fingerprint.StartPos
+		})
+		if i < len(ranges) && ranges[i].contains(fingerprint.StartPos, fingerprint.EndPos) {
+			continue
+		}
+		scored = append(scored, fingerprint)
+	}
+
+	return scored, len(fingerprints) - len(scored)
+}
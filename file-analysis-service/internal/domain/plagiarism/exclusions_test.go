@@ -0,0 +1,153 @@
+package plagiarism
+
+import (
+	"context"
+	"testing"
+
+	"fileanalysisservice/internal/interfaces/repository"
+)
+
+func TestTextProcessor_FindExclusions(t *testing.T) {
+	processor := NewTextProcessor()
+
+	tests := []struct {
+		name     string
+		text     string
+		options  ExclusionOptions
+		expected []string
+		kinds    []string
+	}{
+		{
+			name:     "Guillemets with nested quotes",
+			text:     "Автор писал: «Язык — это «дом бытия»». Дальше свой текст.",
+			options:  ExclusionOptions{Quotes: true},
+			expected: []string{"«Язык — это «дом бытия»»"},
+			kinds:    []string{ExclusionQuote},
+		},
+		{
+			name:     "Curly and straight quotes",
+			text:     "He said “to be or not to be” and then \"that is the question\" again.",
+			options:  ExclusionOptions{Quotes: true},
+			expected: []string{"“to be or not to be”", "\"that is the question\""},
+			kinds:    []string{ExclusionQuote, ExclusionQuote},
+		},
+		{
+			name:     "Low and high quotes",
+			text:     "Он ответил „нет“ и ушел.",
+			options:  ExclusionOptions{Quotes: true},
+			expected: []string{"„нет“"},
+			kinds:    []string{ExclusionQuote},
+		},
+		{
+			name:     "Unclosed quote ends with the paragraph",
+			text:     "Открытая «кавычка без пары\n\nНовый абзац «цитата» здесь.",
+			options:  ExclusionOptions{Quotes: true},
+			expected: []string{"«цитата»"},
+			kinds:    []string{ExclusionQuote},
+		},
+		{
+			name:     "Block quotes",
+			text:     "Начало.\n> первая строка цитаты\n> вторая строка цитаты\nКонец. <blockquote>HTML цитата</blockquote>",
+			options:  ExclusionOptions{Quotes: true},
+			expected: []string{"> первая строка цитаты\n> вторая строка цитаты", "<blockquote>HTML цитата</blockquote>"},
+			kinds:    []string{ExclusionBlockQuote, ExclusionBlockQuote},
+		},
+		{
+			name:     "Trailing bibliography",
+			text:     "Основной текст работы достаточно длинный, чтобы заголовок был в конце.\n\n5. Список литературы:\n1. Иванов И. И. «Книга». М., 2020.",
+			options:  ExclusionOptions{Bibliography: true},
+			expected: []string{"5. Список литературы:\n1. Иванов И. И. «Книга». М., 2020."},
+			kinds:    []string{ExclusionBibliography},
+		},
+		{
+			name:     "Quotes within the bibliography are merged into it",
+			text:     "Основной текст «с цитатой» и еще немного слов для длины.\n\nReferences\nSmith J. \"A title\". 2020.",
+			options:  ExclusionOptions{Quotes: true, Bibliography: true},
+			expected: []string{"«с цитатой»", "References\nSmith J. \"A title\". 2020."},
+			kinds:    []string{ExclusionQuote, ExclusionBibliography},
+		},
+		{
+			name:     "Table of contents entry is not a bibliography",
+			text:     "Литература\nВведение\nОсновной текст работы, в котором нет раздела со списком источников в конце.",
+			options:  ExclusionOptions{Bibliography: true},
+			expected: nil,
+		},
+		{
+			name:     "Nothing is excluded without options",
+			text:     "Текст «с цитатой».\n\nСписок литературы\n1. Книга.",
+			options:  ExclusionOptions{},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranges := processor.FindExclusions(tt.text, tt.options)
+			if len(ranges) != len(tt.expected) {
+				t.Fatalf("FindExclusions() = %+v, want %d ranges", ranges, len(tt.expected))
+			}
+
+			runes := []rune(tt.text)
+			for i, r := range ranges {
+				if got := tt.text[r.StartPos:r.EndPos]; got != tt.expected[i] {
+					t.Errorf("Range %d = %q, want %q", i, got, tt.expected[i])
+				}
+				if got := string(runes[r.StartRune:r.EndRune]); got != tt.expected[i] {
+					t.Errorf("Rune offsets of range %d point to %q", i, got)
+				}
+				if r.Kind != tt.kinds[i] {
+					t.Errorf("Range %d kind = %s, want %s", i, r.Kind, tt.kinds[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPlagiarismService_AnalyzePlagiarism_Exclusions(t *testing.T) {
+	shingleRepo := NewMockShingleRepository()
+	signatureRepo := NewMockSignatureRepository()
+	service := NewPlagiarismService(&MockAnalysisRepository{}, shingleRepo, signatureRepo)
+
+	quote := "Рукописи не горят, потому что настоящая литература переживает своих гонителей и цензоров"
+	algorithm, err := service.Algorithm("")
+	if err != nil {
+		t.Fatalf("Algorithm() error = %v", err)
+	}
+
+	var matches []repository.ShingleMatch
+	for _, fingerprint := range algorithm.Fingerprints(NewTextProcessor().Tokenize(quote)) {
+		matches = append(matches, repository.ShingleMatch{
+			FileID:      "source",
+			ShingleHash: fingerprint.Hash,
+			ShingleText: fingerprint.Text,
+			StartPos:    fingerprint.StartPos,
+			EndPos:      fingerprint.EndPos,
+		})
+	}
+	shingleRepo.SetMatches(matches)
+	signatureRepo.SetCandidates([]repository.LSHCandidate{{FileID: "source", SharedBands: 10}})
+
+	text := "Булгаков утверждал: «" + quote + "». Студент разбирает роман самостоятельно"
+
+	report, err := service.AnalyzePlagiarism(context.Background(), text, "file1", "", ExclusionOptions{}, nil)
+	if err != nil {
+		t.Fatalf("AnalyzePlagiarism() error = %v", err)
+	}
+	if report.UniquenessPercentage >= 100 || report.ExcludedShingles != 0 || len(report.ExcludedRanges) != 0 {
+		t.Fatalf("Without exclusions uniqueness = %.2f, excluded = %d, want the quote to be matched", report.UniquenessPercentage, report.ExcludedShingles)
+	}
+
+	report, err = service.AnalyzePlagiarism(context.Background(), text, "file2", "", ExclusionOptions{Quotes: true}, nil)
+	if err != nil {
+		t.Fatalf("AnalyzePlagiarism() error = %v", err)
+	}
+	if report.UniquenessPercentage != 100 || len(report.Matches) != 0 {
+		t.Errorf("With excluded quotes uniqueness = %.2f with %d matches, want 100 without matches", report.UniquenessPercentage, len(report.Matches))
+	}
+	if report.ExcludedShingles == 0 || len(report.ExcludedRanges) != 1 || report.ExcludedRanges[0].Kind != ExclusionQuote {
+		t.Errorf("Excluded shingles = %d, ranges = %+v, want the quote", report.ExcludedShingles, report.ExcludedRanges)
+	}
+	if report.UniqueShingles+report.MatchedShingles != report.TotalShingles {
+		t.Errorf("Unique %d + matched %d != total %d", report.UniqueShingles, report.MatchedShingles, report.TotalShingles)
+	}
+}
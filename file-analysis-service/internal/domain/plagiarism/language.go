@@ -0,0 +1,156 @@
+package plagiarism
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kljensen/snowball/english"
+	"github.com/kljensen/snowball/french"
+	"github.com/kljensen/snowball/russian"
+	"github.com/kljensen/snowball/spanish"
+)
+
+const (
+	LanguageRussian = "ru"
+	LanguageEnglish = "en"
+	LanguageFrench  = "fr"
+	LanguageSpanish = "es"
+)
+
+// LanguageProfile describes how words of one language are processed
+type LanguageProfile struct {
+	Code          string              // Код ISO 639-1
+	Script        *unicode.RangeTable // Алфавит языка
+	MinWordLength int                 // Более короткие слова (в символах) отбрасываются
+	StopWords     map[string]bool
+	Stem          func(word string) string
+}
+
+// wordSet returns the set of the words
+func wordSet(list ...string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, word := range list {
+		set[word] = true
+	}
+	return set
+}
+
+// RussianProfile returns the Russian language profile with the Snowball stemmer
+func RussianProfile() *LanguageProfile {
+	return &LanguageProfile{
+		Code:          LanguageRussian,
+		Script:        unicode.Cyrillic,
+		MinWordLength: 2,
+		StopWords: wordSet(
+			"и", "в", "на", "с", "по", "для", "от", "до", "из", "к", "о", "об",
+			"что", "как", "так", "но", "а", "или", "же", "бы", "ли", "не", "ни", "то",
+			"это", "этот", "эта", "эти", "тот", "та", "те", "он", "она", "оно", "они", "мы",
+			"вы", "я", "ты", "его", "её", "их", "наш", "ваш", "мой", "твой", "свой",
+			"который", "которая", "которое", "которые",
+			"где", "когда", "почему", "зачем", "куда", "откуда", "сколько", "чем", "чего", "кого",
+			"кому", "кем", "чему", "чём", "при", "под", "над", "за", "перед", "между",
+			"через", "без", "против", "вместо", "кроме", "после", "во", "со", "ко",
+		),
+		Stem: func(word string) string {
+			// Snowball не различает е и ё
+			return russian.Stem(strings.ReplaceAll(word, "ё", "е"), true)
+		},
+	}
+}
+
+// EnglishProfile returns the English language profile with the Snowball (Porter2) stemmer
+func EnglishProfile() *LanguageProfile {
+	return &LanguageProfile{
+		Code:          LanguageEnglish,
+		Script:        unicode.Latin,
+		MinWordLength: 3,
+		StopWords: wordSet(
+			"the", "and", "for", "are", "but", "not", "you", "all", "any", "can", "had", "her",
+			"was", "one", "our", "out", "has", "him", "his", "how", "its", "who", "did", "yes",
+			"she", "too", "use", "that", "this", "with", "from", "they", "them", "then", "than",
+			"have", "been", "were", "will", "would", "there", "their", "which", "what", "when",
+			"where", "while", "into", "onto", "about", "also", "more", "most", "such", "some",
+			"only", "other", "these", "those", "each", "very", "just", "over", "under", "your",
+			"because", "between", "being", "both", "could", "should", "does", "doing", "here",
+			"a", "an", "in", "on", "of", "to", "is", "it", "as", "at", "be", "by", "or", "we",
+		),
+		Stem: func(word string) string {
+			return english.Stem(word, true)
+		},
+	}
+}
+
+// FrenchProfile returns the French language profile with the Snowball stemmer
+func FrenchProfile() *LanguageProfile {
+	return &LanguageProfile{
+		Code:          LanguageFrench,
+		Script:        unicode.Latin,
+		MinWordLength: 3,
+		StopWords: wordSet(
+			"le", "la", "les", "un", "une", "des", "du", "de", "et", "en", "au", "aux", "ce",
+			"ces", "cet", "cette", "que", "qui", "quoi", "dans", "par", "pour", "sur", "avec",
+			"sans", "sous", "est", "sont", "pas", "plus", "mais", "ou", "où", "donc", "car",
+			"ne", "se", "sa", "son", "ses", "leur", "leurs", "nous", "vous", "ils", "elle",
+			"elles", "il", "je", "tu", "on", "été", "être", "avoir", "fait", "comme", "aussi",
+		),
+		Stem: func(word string) string {
+			return french.Stem(word, true)
+		},
+	}
+}
+
+// SpanishProfile returns the Spanish language profile with the Snowball stemmer
+func SpanishProfile() *LanguageProfile {
+	return &LanguageProfile{
+		Code:          LanguageSpanish,
+		Script:        unicode.Latin,
+		MinWordLength: 3,
+		StopWords: wordSet(
+			"el", "la", "los", "las", "un", "una", "unos", "unas", "de", "del", "al", "y",
+			"en", "que", "por", "para", "con", "sin", "sobre", "entre", "es", "son", "está",
+			"están", "fue", "ser", "pero", "como", "más", "muy", "también", "se", "su", "sus",
+			"lo", "le", "les", "ya", "nos", "este", "esta", "estos", "estas", "ese", "esa",
+			"cuando", "donde", "porque", "hay", "todo", "todos", "ha", "han", "no", "o",
+		),
+		Stem: func(word string) string {
+			return spanish.Stem(word, true)
+		},
+	}
+}
+
+// keeps reports whether a lower case word is kept by the profile
+func (p *LanguageProfile) keeps(word string) bool {
+	return !p.StopWords[word] && utf8.RuneCountInString(word) >= p.MinWordLength
+}
+
+// languageScore measures how much words look like a language: first the amount of words written
+// in its script, then, for languages sharing a script, the amount of its stop words
+type languageScore struct {
+	scriptWords int
+	stopWords   int
+}
+
+func (s languageScore) greater(other languageScore) bool {
+	if s.scriptWords != other.scriptWords {
+		return s.scriptWords > other.scriptWords
+	}
+	return s.stopWords > other.stopWords
+}
+
+// score measures how much the lower case words look like the language
+func (p *LanguageProfile) score(words []string) languageScore {
+	var score languageScore
+	for _, word := range words {
+		r, _ := utf8.DecodeRuneInString(word)
+		if !unicode.Is(p.Script, r) {
+			continue
+		}
+
+		score.scriptWords++
+		if p.StopWords[word] {
+			score.stopWords++
+		}
+	}
+	return score
+}
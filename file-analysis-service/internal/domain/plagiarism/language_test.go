@@ -0,0 +1,93 @@
+package plagiarism
+
+import (
+	"testing"
+)
+
+func TestTextProcessor_DetectLanguage(t *testing.T) {
+	processor := NewTextProcessor()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Russian", "Студенты написали курсовые работы по программированию", LanguageRussian},
+		{"English", "The students have written their term papers about programming", LanguageEnglish},
+		{"French", "Les étudiants ont écrit leurs mémoires sur la programmation", LanguageFrench},
+		{"Spanish", "Los estudiantes escribieron sus trabajos sobre la programación", LanguageSpanish},
+		{"No words", "12345 !!!", LanguageRussian},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := processor.DetectLanguage(tt.input).Code; got != tt.expected {
+				t.Errorf("DetectLanguage() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTextProcessor_Tokenize_English(t *testing.T) {
+	processor := NewTextProcessor()
+
+	tokens := processor.Tokenize("The students were running the experiments of the connected networks")
+
+	expected := "student run experi connect network"
+	if JoinTokens(tokens) != expected {
+		t.Errorf("JoinTokens(Tokenize()) = %q, want %q", JoinTokens(tokens), expected)
+	}
+}
+
+func TestTextProcessor_Tokenize_Russian(t *testing.T) {
+	processor := NewTextProcessor()
+
+	tokens := processor.Tokenize("Студенты писали курсовые работы о программировании")
+
+	expected := "студент писа курсов работ программирован"
+	if JoinTokens(tokens) != expected {
+		t.Errorf("JoinTokens(Tokenize()) = %q, want %q", JoinTokens(tokens), expected)
+	}
+}
+
+func TestTextProcessor_DetectLanguages_Paragraphs(t *testing.T) {
+	processor := NewTextProcessor()
+
+	text := `Введение курсовой работы написано на русском языке, как и большая часть текста.
+
+The abstract is written in English and it is processed with the English stemmer.
+
+Заключение также написано на русском языке.`
+
+	language, paragraphs := processor.DetectLanguages(text)
+	if language != LanguageRussian {
+		t.Errorf("DetectLanguages() language = %v, want %v", language, LanguageRussian)
+	}
+	if paragraphs[LanguageRussian] != 2 || paragraphs[LanguageEnglish] != 1 {
+		t.Errorf("DetectLanguages() paragraphs = %v, want 2 ru and 1 en", paragraphs)
+	}
+
+	// English stop words of the English paragraph are removed even though the document is Russian
+	for _, token := range processor.Tokenize(text) {
+		if token.Text == "the" || token.Text == "and" {
+			t.Errorf("Tokenize() kept the English stop word %q", token.Text)
+		}
+	}
+
+	stats := processor.CalculateTextStatistics(text)
+	if stats.Language != LanguageRussian || stats.ParagraphLanguages[LanguageEnglish] != 1 {
+		t.Errorf("CalculateTextStatistics() language = %v, paragraphs = %v", stats.Language, stats.ParagraphLanguages)
+	}
+}
+
+func TestTextProcessor_RegisterLanguage(t *testing.T) {
+	processor := NewTextProcessor()
+
+	english := EnglishProfile()
+	english.StopWords["students"] = true
+	processor.RegisterLanguage(english)
+
+	if got := JoinTokens(processor.Tokenize("The students were running")); got != "run" {
+		t.Errorf("JoinTokens(Tokenize()) = %q, want %q", got, "run")
+	}
+}
@@ -0,0 +1,120 @@
+package plagiarism
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+)
+
+const (
+	// SignatureSize is the number of hash functions of a MinHash signature
+	SignatureSize = 128
+	// LSHBands and LSHRows split a signature into bands, documents sharing any band become candidates.
+	// With 64 bands of 2 rows documents are likely to become candidates from about 12% Jaccard similarity,
+	// low enough not to miss documents that copy only a part of another one.
+	LSHBands = 64
+	LSHRows  = SignatureSize / LSHBands
+	// MaxCandidates caps the amount of documents compared shingle by shingle
+	MaxCandidates = 50
+)
+
+// Signature is a MinHash signature of a document's shingle set
+type Signature []uint64
+
+// MinHasher computes MinHash signatures with a fixed family of hash functions.
+// The family is derived from a constant seed, so signatures stay comparable across restarts.
+type MinHasher struct {
+	seeds []uint64
+}
+
+// NewMinHasher creates a MinHasher producing signatures of SignatureSize values
+func NewMinHasher() *MinHasher {
+	seeds := make([]uint64, SignatureSize)
+	state := uint64(0x5eed_1ea5_cafe_f00d)
+	for i := range seeds {
+		state += 0x9e3779b97f4a7c15
+		seeds[i] = mix64(state)
+	}
+
+	return &MinHasher{seeds: seeds}
+}
+
+// Signature computes the MinHash signature of a set of shingle hashes (hex MD5 as produced by HashShingles)
+func (mh *MinHasher) Signature(shingleHashes []string) Signature {
+	signature := make(Signature, len(mh.seeds))
+	for i := range signature {
+		signature[i] = ^uint64(0)
+	}
+
+	for _, shingleHash := range shingleHashes {
+		value := shingleValue(shingleHash)
+		for i, seed := range mh.seeds {
+			if h := mix64(value ^ seed); h < signature[i] {
+				signature[i] = h
+			}
+		}
+	}
+
+	return signature
+}
+
+// Bands returns the LSH bucket keys of the signature, one per band
+func (s Signature) Bands() []string {
+	if len(s) != SignatureSize {
+		return nil
+	}
+
+	bands := make([]string, LSHBands)
+	buf := make([]byte, 8)
+	for band := 0; band < LSHBands; band++ {
+		h := fnv.New64a()
+		for _, value := range s[band*LSHRows : (band+1)*LSHRows] {
+			for i := range buf {
+				buf[i] = byte(value >> (8 * i))
+			}
+			_, _ = h.Write(buf)
+		}
+		bands[band] = fmt.Sprintf("%02d:%016x", band, h.Sum64())
+	}
+
+	return bands
+}
+
+// EstimateSimilarity estimates the Jaccard similarity (0-1) of the documents the signatures were computed for
+func EstimateSimilarity(a, b Signature) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	equal := 0
+	for i := range a {
+		if a[i] == b[i] {
+			equal++
+		}
+	}
+
+	return float64(equal) / float64(len(a))
+}
+
+// shingleValue turns a hex shingle hash into a 64-bit value
+func shingleValue(shingleHash string) uint64 {
+	if len(shingleHash) >= 16 {
+		if value, err := strconv.ParseUint(shingleHash[:16], 16, 64); err == nil {
+			return value
+		}
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(shingleHash))
+	return h.Sum64()
+}
+
+// mix64 is the SplitMix64 finalizer, a fast 64-bit hash with good avalanche
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
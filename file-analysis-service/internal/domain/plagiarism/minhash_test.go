@@ -0,0 +1,84 @@
+package plagiarism
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func testHashes(prefix string, count int) []string {
+	processor := NewTextProcessor()
+	shingles := make([]string, count)
+	for i := range shingles {
+		shingles[i] = fmt.Sprintf("%s шингл номер %d", prefix, i)
+	}
+	return processor.HashShingles(shingles)
+}
+
+func TestMinHasher_Signature(t *testing.T) {
+	hasher := NewMinHasher()
+	hashes := testHashes("первый", 100)
+
+	signature := hasher.Signature(hashes)
+	if len(signature) != SignatureSize {
+		t.Fatalf("len(Signature()) = %v, want %v", len(signature), SignatureSize)
+	}
+
+	again := NewMinHasher().Signature(hashes)
+	if EstimateSimilarity(signature, again) != 1 {
+		t.Error("Signatures of the same shingles should be equal across hashers")
+	}
+
+	if len(signature.Bands()) != LSHBands {
+		t.Errorf("len(Bands()) = %v, want %v", len(signature.Bands()), LSHBands)
+	}
+}
+
+func TestEstimateSimilarity(t *testing.T) {
+	hasher := NewMinHasher()
+	common := testHashes("общий", 300)
+
+	// Jaccard similarity of a and b is 300 / 500 = 0.6
+	a := hasher.Signature(append(testHashes("первый", 100), common...))
+	b := hasher.Signature(append(testHashes("второй", 100), common...))
+	other := hasher.Signature(testHashes("другой", 400))
+
+	if estimate := EstimateSimilarity(a, b); math.Abs(estimate-0.6) > 0.15 {
+		t.Errorf("EstimateSimilarity() = %v, want about 0.6", estimate)
+	}
+
+	if estimate := EstimateSimilarity(a, other); estimate > 0.1 {
+		t.Errorf("EstimateSimilarity() = %v for unrelated documents, want about 0", estimate)
+	}
+
+	if EstimateSimilarity(a, nil) != 0 {
+		t.Error("EstimateSimilarity() with a missing signature should be 0")
+	}
+}
+
+func TestSignature_Bands(t *testing.T) {
+	hasher := NewMinHasher()
+	common := testHashes("общий", 300)
+
+	a := hasher.Signature(append(testHashes("первый", 100), common...)).Bands()
+	b := hasher.Signature(append(testHashes("второй", 100), common...)).Bands()
+	other := hasher.Signature(testHashes("другой", 400)).Bands()
+
+	shared := func(x, y []string) int {
+		count := 0
+		for i := range x {
+			if x[i] == y[i] {
+				count++
+			}
+		}
+		return count
+	}
+
+	if shared(a, b) == 0 {
+		t.Error("Similar documents should share LSH bands")
+	}
+
+	if shared(a, other) > 2 {
+		t.Errorf("Unrelated documents share %d LSH bands", shared(a, other))
+	}
+}
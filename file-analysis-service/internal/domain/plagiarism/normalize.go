@@ -0,0 +1,125 @@
+package plagiarism
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// latinToCyrillic maps Latin letters to the Cyrillic letters looking the same
+var latinToCyrillic = map[rune]rune{
+	'a': 'а', 'c': 'с', 'e': 'е', 'o': 'о', 'p': 'р', 'x': 'х', 'y': 'у',
+	'A': 'А', 'B': 'В', 'C': 'С', 'E': 'Е', 'H': 'Н', 'K': 'К', 'M': 'М',
+	'O': 'О', 'P': 'Р', 'T': 'Т', 'X': 'Х', 'Y': 'У',
+}
+
+// cyrillicToLatin maps Cyrillic letters to the Latin letters looking the same
+var cyrillicToLatin = func() map[rune]rune {
+	confusables := map[rune]rune{'і': 'i', 'І': 'I', 'ј': 'j', 'Ј': 'J', 'ѕ': 's', 'Ѕ': 'S'}
+	for latin, cyrillic := range latinToCyrillic {
+		confusables[cyrillic] = latin
+	}
+	return confusables
+}()
+
+// Obfuscation counts characters changed by normalization, a normal text has none of them
+type Obfuscation struct {
+	Homoglyphs    int // Буквы другого алфавита внутри слова, замененные на похожие
+	Invisible     int // Невидимые символы внутри слов: нулевой ширины, мягкие переносы
+	Compatibility int // Символы, замененные нормализацией NFKC: полноширинные, лигатуры, математические
+	Words         int // Слова, в которых была хотя бы одна замена
+}
+
+// Detected reports whether any obfuscation was found
+func (o Obfuscation) Detected() bool {
+	return o.Words > 0
+}
+
+func (o *Obfuscation) add(other Obfuscation) {
+	o.Homoglyphs += other.Homoglyphs
+	o.Invisible += other.Invisible
+	o.Compatibility += other.Compatibility
+	o.Words += other.Words
+}
+
+// isInvisible reports whether the rune is a formatting code point that is not displayed,
+// such as zero width spaces, joiners, soft hyphens and byte order marks
+func isInvisible(r rune) bool {
+	return unicode.Is(unicode.Cf, r)
+}
+
+// normalizeWord brings a word of the original text to its canonical form: invisible characters are removed,
+// NFKC is applied and look-alike letters of another script are folded into the script of the word
+func normalizeWord(word string) (string, Obfuscation) {
+	var obfuscation Obfuscation
+
+	var b strings.Builder
+	for _, r := range word {
+		if isInvisible(r) {
+			obfuscation.Invisible++
+			continue
+		}
+		if !unicode.Is(unicode.M, r) && !norm.NFKC.IsNormalString(string(r)) {
+			obfuscation.Compatibility++
+		}
+		b.WriteRune(r)
+	}
+
+	normalized := norm.NFKC.String(b.String())
+	normalized, obfuscation.Homoglyphs = foldConfusables(normalized)
+
+	if obfuscation.Homoglyphs+obfuscation.Invisible+obfuscation.Compatibility > 0 {
+		obfuscation.Words = 1
+	}
+
+	return normalized, obfuscation
+}
+
+// foldConfusables replaces look-alike letters of a word mixing Cyrillic and Latin letters with the letters of
+// the script the word is written in: the one with more letters that have no look-alikes, or simply more letters
+func foldConfusables(word string) (string, int) {
+	var cyrillic, latin, cyrillicDistinct, latinDistinct int
+	for _, r := range word {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+			if _, ok := cyrillicToLatin[r]; !ok {
+				cyrillicDistinct++
+			}
+		case unicode.Is(unicode.Latin, r):
+			latin++
+			if _, ok := latinToCyrillic[r]; !ok {
+				latinDistinct++
+			}
+		}
+	}
+
+	if cyrillic == 0 || latin == 0 {
+		return word, 0
+	}
+
+	toCyrillic := cyrillic >= latin
+	if cyrillicDistinct != latinDistinct {
+		toCyrillic = cyrillicDistinct > latinDistinct
+	}
+
+	confusables, script := cyrillicToLatin, unicode.Cyrillic
+	if toCyrillic {
+		confusables, script = latinToCyrillic, unicode.Latin
+	}
+
+	substitutions := 0
+	folded := strings.Map(func(r rune) rune {
+		if !unicode.Is(script, r) {
+			return r
+		}
+		if replacement, ok := confusables[r]; ok {
+			substitutions++
+			return replacement
+		}
+		return r
+	}, word)
+
+	return folded, substitutions
+}
@@ -0,0 +1,82 @@
+package plagiarism
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNormalizeWord(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    string
+		obfuscation Obfuscation
+	}{
+		{"Plain Russian word", "работа", "работа", Obfuscation{}},
+		{"Plain English word", "paper", "paper", Obfuscation{}},
+		{"Latin letters in a Russian word", "pабoта", "работа", Obfuscation{Homoglyphs: 2, Words: 1}},
+		{"Cyrillic letters in an English word", "pаpеr", "paper", Obfuscation{Homoglyphs: 2, Words: 1}},
+		{"Upper case look-alikes", "КУРСОВАЯ", "КУРСОВАЯ", Obfuscation{}},
+		{"Mixed upper case", "KУPCOBAЯ", "КУРСОВАЯ", Obfuscation{Homoglyphs: 6, Words: 1}},
+		{"Zero width space and soft hyphen", "ра​бо­та", "работа", Obfuscation{Invisible: 2, Words: 1}},
+		{"Fullwidth letters", "ｐａｐｅｒ", "paper", Obfuscation{Compatibility: 5, Words: 1}},
+		{"Decomposed letter is composed", "йод", "йод", Obfuscation{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, obfuscation := normalizeWord(tt.input)
+			if normalized != tt.expected {
+				t.Errorf("normalizeWord() = %q, want %q", normalized, tt.expected)
+			}
+			if obfuscation != tt.obfuscation {
+				t.Errorf("normalizeWord() obfuscation = %+v, want %+v", obfuscation, tt.obfuscation)
+			}
+		})
+	}
+}
+
+func TestTextProcessor_Tokenize_Obfuscated(t *testing.T) {
+	processor := NewTextProcessor()
+
+	original := "Курсовая работа посвящена анализу текстов"
+	obfuscated := "Кypсовая ра​бота пoсвящена анализу текстов­"
+
+	tokens, obfuscation := processor.TokenizeWithObfuscation(obfuscated)
+	if JoinTokens(tokens) != processor.ProcessText(original) {
+		t.Errorf("JoinTokens(Tokenize()) = %q, want %q", JoinTokens(tokens), processor.ProcessText(original))
+	}
+
+	if obfuscation.Homoglyphs != 3 || obfuscation.Invisible != 1 || obfuscation.Words != 3 {
+		t.Errorf("Obfuscation = %+v, want 3 homoglyphs and 1 invisible character in 3 words", obfuscation)
+	}
+
+	// Positions still point to the original words, a trailing invisible character is not a part of the word
+	if got := obfuscated[tokens[1].StartPos:tokens[1].EndPos]; got != "ра​бота" {
+		t.Errorf("Token position points to %q", got)
+	}
+	last := tokens[len(tokens)-1]
+	if got := obfuscated[last.StartPos:last.EndPos]; got != "текстов" {
+		t.Errorf("Token position points to %q", got)
+	}
+}
+
+func TestPlagiarismService_AnalyzePlagiarism_Obfuscation(t *testing.T) {
+	service := NewPlagiarismService(&MockAnalysisRepository{}, NewMockShingleRepository(), NewMockSignatureRepository())
+
+	report, err := service.AnalyzePlagiarism(context.Background(), "Kурсовая рaбота посвящена анализу текстов", "file1", "", ExclusionOptions{}, nil)
+	if err != nil {
+		t.Fatalf("AnalyzePlagiarism() error = %v", err)
+	}
+	if report.Obfuscation == nil || report.Obfuscation.HomoglyphSubstitutions != 2 || report.Obfuscation.AffectedWords != 2 {
+		t.Errorf("Obfuscation = %+v, want 2 substitutions in 2 words", report.Obfuscation)
+	}
+
+	report, err = service.AnalyzePlagiarism(context.Background(), "Курсовая работа посвящена анализу текстов", "file2", "", ExclusionOptions{}, nil)
+	if err != nil {
+		t.Fatalf("AnalyzePlagiarism() error = %v", err)
+	}
+	if report.Obfuscation != nil {
+		t.Errorf("Obfuscation = %+v, want nil for a plain text", report.Obfuscation)
+	}
+}
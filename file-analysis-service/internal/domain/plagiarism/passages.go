@@ -0,0 +1,89 @@
+package plagiarism
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"fileanalysisservice/internal/domain/analysis"
+	"fileanalysisservice/internal/interfaces/repository"
+)
+
+// passageRun is a run of fingerprints following each other in both documents
+type passageRun struct {
+	first, last             int // Fingerprints of the analysed document
+	sourceFirst, sourceLast int // Shingles of the source document
+}
+
+// findPassages merges matching fingerprints into passages copied from the source.
+// A fingerprint extends a passage when it follows the passage's last fingerprint in the analysed document
+// and its match follows the passage's last shingle in the source, so reordered pieces become separate passages.
+// Source shingles must be ordered by position, as returned by the repository.
+func findPassages(text string, fingerprints []Fingerprint, source []repository.ShingleMatch) []analysis.MatchedPassage {
+	sourcePositions := make(map[string][]int)
+	for j, shingle := range source {
+		sourcePositions[shingle.ShingleHash] = append(sourcePositions[shingle.ShingleHash], j)
+	}
+
+	var runs []*passageRun
+	previous := map[int]*passageRun{}
+
+	for i, fingerprint := range fingerprints {
+		current := make(map[int]*passageRun)
+
+		for _, j := range sourcePositions[fingerprint.Hash] {
+			if run, ok := previous[j-1]; ok {
+				run.last, run.sourceLast = i, j
+				current[j] = run
+				continue
+			}
+
+			run := &passageRun{first: i, last: i, sourceFirst: j, sourceLast: j}
+			runs = append(runs, run)
+			current[j] = run
+		}
+
+		previous = current
+	}
+
+	passages := make([]analysis.MatchedPassage, 0, len(runs))
+	for _, run := range runs {
+		first, last := fingerprints[run.first], fingerprints[run.last]
+		sourceFirst, sourceLast := source[run.sourceFirst], source[run.sourceLast]
+		passageText := text[first.StartPos:last.EndPos]
+
+		passages = append(passages, analysis.MatchedPassage{
+			Text:      passageText,
+			WordCount: countWords(passageText),
+			Document: analysis.TextSpan{
+				StartPos:  first.StartPos,
+				EndPos:    last.EndPos,
+				StartRune: first.StartRune,
+				EndRune:   last.EndRune,
+				StartLine: first.StartLine,
+				EndLine:   last.EndLine,
+			},
+			Source: analysis.TextSpan{
+				StartPos:  sourceFirst.StartPos,
+				EndPos:    sourceLast.EndPos,
+				StartRune: sourceFirst.StartRune,
+				EndRune:   sourceLast.EndRune,
+				StartLine: sourceFirst.StartLine,
+				EndLine:   sourceLast.EndLine,
+			},
+		})
+	}
+
+	sort.SliceStable(passages, func(a, b int) bool {
+		return passages[a].Document.StartPos < passages[b].Document.StartPos
+	})
+
+	return passages
+}
+
+// countWords counts the words of an original text fragment, stop words included
+func countWords(text string) int {
+	return len(strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}))
+}
@@ -0,0 +1,63 @@
+package plagiarism
+
+import (
+	"testing"
+
+	"fileanalysisservice/internal/interfaces/repository"
+)
+
+func TestFindPassages(t *testing.T) {
+	processor := NewTextProcessor()
+	shingling := NewShingling(2)
+
+	document := "Первый абзац совпадает дословно. Между ними свой текст автора. Второй абзац тоже списан."
+	source := "Второй абзац тоже списан. Здесь что-то другое. Первый абзац совпадает дословно."
+
+	fingerprints := shingling.Fingerprints(processor.Tokenize(document))
+
+	var sourceShingles []repository.ShingleMatch
+	for _, fingerprint := range shingling.Fingerprints(processor.Tokenize(source)) {
+		sourceShingles = append(sourceShingles, repository.ShingleMatch{
+			FileID:      "source",
+			ShingleHash: fingerprint.Hash,
+			ShingleText: fingerprint.Text,
+			StartPos:    fingerprint.StartPos,
+			EndPos:      fingerprint.EndPos,
+			StartRune:   fingerprint.StartRune,
+			EndRune:     fingerprint.EndRune,
+		})
+	}
+
+	passages := findPassages(document, fingerprints, sourceShingles)
+	if len(passages) != 2 {
+		t.Fatalf("Expected 2 passages, got %d: %+v", len(passages), passages)
+	}
+
+	first, second := passages[0], passages[1]
+
+	if first.Text != "Первый абзац совпадает дословно" || first.WordCount != 4 {
+		t.Errorf("First passage = %q of %d words", first.Text, first.WordCount)
+	}
+
+	if got := source[first.Source.StartPos:first.Source.EndPos]; got != "Первый абзац совпадает дословно" {
+		t.Errorf("First passage source span = %q", got)
+	}
+
+	if second.Text != "Второй абзац тоже списан" {
+		t.Errorf("Second passage = %q", second.Text)
+	}
+
+	if got := source[second.Source.StartPos:second.Source.EndPos]; got != "Второй абзац тоже списан" {
+		t.Errorf("Second passage source span = %q", got)
+	}
+
+	if first.Document.StartRune != 0 || first.Document.EndRune != len([]rune(first.Text)) {
+		t.Errorf("First passage runes = [%d, %d)", first.Document.StartRune, first.Document.EndRune)
+	}
+}
+
+func TestCountWords(t *testing.T) {
+	if count := countWords("Это — текст из 5 слов!"); count != 5 {
+		t.Errorf("countWords() = %v, want 5", count)
+	}
+}
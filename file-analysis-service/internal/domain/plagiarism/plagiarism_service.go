@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"slices"
+	"sort"
 	"time"
 
 	"fileanalysisservice/internal/domain/analysis"
@@ -12,187 +14,506 @@ import (
 
 // Service handles plagiarism detection logic
 type Service struct {
-	textProcessor      *TextProcessor
-	analysisRepository repository.AnalysisRepository
-	shingleRepository  repository.ShingleRepository
-	shingleSize        int
+	textProcessor       *TextProcessor
+	analysisRepository  repository.AnalysisRepository
+	shingleRepository   repository.ShingleRepository
+	signatureRepository repository.SignatureRepository
+	minHasher           *MinHasher
+	algorithms          map[string]Algorithm
+	shingleSize         int
 }
 
 // NewPlagiarismService creates a new plagiarism service
-func NewPlagiarismService(analysisRepository repository.AnalysisRepository, shingleRepository repository.ShingleRepository) *Service {
+// Full shingling is the only registered algorithm, others are added with RegisterAlgorithm.
+func NewPlagiarismService(analysisRepository repository.AnalysisRepository, shingleRepository repository.ShingleRepository, signatureRepository repository.SignatureRepository) *Service {
+	textProcessor := NewTextProcessor()
+
 	return &Service{
-		textProcessor:      NewTextProcessor(),
-		analysisRepository: analysisRepository,
-		shingleRepository:  shingleRepository,
-		shingleSize:        4,
+		textProcessor:       textProcessor,
+		analysisRepository:  analysisRepository,
+		shingleRepository:   shingleRepository,
+		signatureRepository: signatureRepository,
+		minHasher:           NewMinHasher(),
+		algorithms: map[string]Algorithm{
+			AlgorithmShingles: NewShingling(4),
+		},
+		shingleSize: 4,
+	}
+}
+
+// RegisterAlgorithm makes an algorithm available by its name, replacing an algorithm with the same name
+func (ps *Service) RegisterAlgorithm(algorithm Algorithm) {
+	ps.algorithms[algorithm.Info().Name] = algorithm
+}
+
+// Algorithm returns the registered algorithm with the given name, full shingling if the name is empty
+func (ps *Service) Algorithm(name string) (Algorithm, error) {
+	if name == "" {
+		name = AlgorithmShingles
+	}
+
+	algorithm, ok := ps.algorithms[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAlgorithm, name)
+	}
+
+	return algorithm, nil
+}
+
+// document is a tokenized document prepared for the analysis
+type document struct {
+	fileID         string
+	text           string
+	tokens         []Token
+	algorithm      Algorithm
+	shingleSize    int             // Размер шинглов сигнатуры MinHash в токенах
+	excludedRanges []ExcludedRange // Фрагменты, не учитываемые в уникальности
+	assignmentID   string          // Задание, шаблоны которого не учитываются
+	sourceFileIDs  []string        // Документы, с которыми сравнивается документ, nil для всех
+	obfuscation    *analysis.ObfuscationReport
+}
+
+// AnalyzePlagiarism performs plagiarism analysis on the given text with the algorithm of the given name.
+// Fingerprints within the regions selected by the exclusion options or found in the templates of the assignment
+// are stored but don't affect uniqueness. Non-nil sourceFileIDs restrict the documents the text is compared with.
+func (ps *Service) AnalyzePlagiarism(ctx context.Context, text string, currentFileID string, algorithmName string, exclusions ExclusionOptions, sourceFileIDs []string) (*analysis.PlagiarismReport, error) {
+	algorithm, err := ps.Algorithm(algorithmName)
+	if err != nil {
+		return nil, err
 	}
+
+	log.Printf("Starting plagiarism analysis for file %s with %s", currentFileID, algorithm.Key())
+
+	tokens, obfuscation := ps.textProcessor.TokenizeWithObfuscation(text)
+	obfuscationReport := newObfuscationReport(obfuscation)
+	if obfuscationReport != nil {
+		log.Printf("Obfuscation detected in file %s: %d words changed by normalization", currentFileID, obfuscation.Words)
+	}
+
+	return ps.analyze(ctx, document{
+		fileID:         currentFileID,
+		text:           text,
+		tokens:         tokens,
+		algorithm:      algorithm,
+		shingleSize:    ps.shingleSize,
+		excludedRanges: ps.textProcessor.FindExclusions(text, exclusions),
+		assignmentID:   exclusions.Assignment,
+		sourceFileIDs:  sourceFileIDs,
+		obfuscation:    obfuscationReport,
+	})
 }
 
-// AnalyzePlagiarism performs plagiarism analysis on the given text
-func (ps *Service) AnalyzePlagiarism(ctx context.Context, text string, currentFileID string) (*analysis.PlagiarismReport, error) {
-	log.Printf("Starting plagiarism analysis for file %s", currentFileID)
+// AnalyzeCode performs plagiarism analysis on source code in the given programming language.
+// Identifiers and literals are normalized, so renamed variables still match, and the spans of the report
+// carry the lines of the code. Code is only compared with code fingerprinted for the same language and algorithm.
+func (ps *Service) AnalyzeCode(ctx context.Context, source string, currentFileID string, algorithmName string, language string, assignmentID string, sourceFileIDs []string) (*analysis.PlagiarismReport, error) {
+	algorithm, err := ps.CodeAlgorithm(algorithmName, language)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Starting source code plagiarism analysis for file %s with %s", currentFileID, algorithm.Key())
+
+	tokens, err := TokenizeCode(language, source)
+	if err != nil {
+		return nil, err
+	}
 
-	processedText := ps.textProcessor.ProcessText(text)
-	if processedText == "" {
+	return ps.analyze(ctx, document{
+		fileID:        currentFileID,
+		text:          source,
+		tokens:        tokens,
+		algorithm:     algorithm,
+		shingleSize:   CodeShingleSize,
+		assignmentID:  assignmentID,
+		sourceFileIDs: sourceFileIDs,
+	})
+}
+
+// analyze fingerprints the tokens of the document, stores the fingerprints and compares them with the candidates
+func (ps *Service) analyze(ctx context.Context, doc document) (*analysis.PlagiarismReport, error) {
+	algorithm := doc.algorithm
+
+	if len(doc.tokens) == 0 {
 		return &analysis.PlagiarismReport{
+			Algorithm:            algorithm.Info(),
 			UniquenessPercentage: 100.0,
 			TotalShingles:        0,
 			UniqueShingles:       0,
 			Matches:              []analysis.PlagiarismMatch{},
+			SimilarityEstimates:  []analysis.SimilarityEstimate{},
+			Obfuscation:          doc.obfuscation,
 			ProcessedAt:          time.Now(),
 		}, nil
 	}
 
-	shingles := ps.textProcessor.GenerateShingles(processedText, ps.shingleSize)
-	if len(shingles) == 0 {
+	fingerprints := algorithm.Fingerprints(doc.tokens)
+	if len(fingerprints) == 0 {
 		return &analysis.PlagiarismReport{
+			Algorithm:            algorithm.Info(),
 			UniquenessPercentage: 100.0,
 			TotalShingles:        0,
 			UniqueShingles:       0,
 			Matches:              []analysis.PlagiarismMatch{},
+			SimilarityEstimates:  []analysis.SimilarityEstimate{},
+			Obfuscation:          doc.obfuscation,
 			ProcessedAt:          time.Now(),
 		}, nil
 	}
 
-	currentHashes := ps.textProcessor.HashShingles(shingles)
-	currentHashSet := make(map[string]bool)
-	for _, hash := range currentHashes {
-		currentHashSet[hash] = true
+	// Signatures are always built from token shingles, so LSH finds candidates whatever the algorithm
+	shingleHashes := ps.textProcessor.HashShingles(ps.textProcessor.GenerateShingles(JoinTokens(doc.tokens), doc.shingleSize))
+	signature := ps.minHasher.Signature(shingleHashes)
+	estimates, err := ps.findCandidates(ctx, signature, doc.fileID, doc.sourceFileIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find candidates: %w", err)
 	}
 
-	err := ps.storeShingles(ctx, currentFileID, shingles, currentHashes)
+	err = ps.storeFingerprints(ctx, doc.fileID, algorithm.Key(), fingerprints)
 	if err != nil {
-		log.Printf("Failed to store shingles for file %s: %v", currentFileID, err)
+		log.Printf("Failed to store fingerprints for file %s: %v", doc.fileID, err)
 	}
 
-	matches, err := ps.findMatches(ctx, currentHashes, currentFileID)
+	err = ps.signatureRepository.StoreSignature(ctx, doc.fileID, signature, signature.Bands())
+	if err != nil {
+		log.Printf("Failed to store signature for file %s: %v", doc.fileID, err)
+	}
+
+	// Excluded regions only affect the score, the stored fingerprints stay complete for other documents
+	fingerprints, excludedShingles := excludeFingerprints(fingerprints, doc.excludedRanges)
+
+	templateCount := len(fingerprints)
+	fingerprints, templateRanges, err := ps.excludeTemplates(ctx, algorithm.Key(), doc.assignmentID, fingerprints)
+	if err != nil {
+		return nil, err
+	}
+	templateShingles := templateCount - len(fingerprints)
+	excludedRanges := mergeRanges(append(doc.excludedRanges, templateRanges...))
+
+	if len(fingerprints) == 0 {
+		return &analysis.PlagiarismReport{
+			Algorithm:            algorithm.Info(),
+			UniquenessPercentage: 100.0,
+			Matches:              []analysis.PlagiarismMatch{},
+			SimilarityEstimates:  estimates,
+			ExcludedShingles:     excludedShingles,
+			TemplateShingles:     templateShingles,
+			ExcludedRanges:       newExcludedRanges(excludedRanges),
+			Obfuscation:          doc.obfuscation,
+			ProcessedAt:          time.Now(),
+		}, nil
+	}
+
+	matches, coverages, unlisted, err := ps.findMatches(ctx, algorithm.Key(), doc.text, fingerprints, estimates)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find matches: %w", err)
 	}
 
-	uniqueShingles := ps.calculateUniqueShingles(currentHashSet, matches)
-	uniquenessPercentage := float64(uniqueShingles) / float64(len(shingles)) * 100
+	uniqueShingles, sharedShingles := ps.calculateUniqueShingles(len(fingerprints), matches, coverages, unlisted)
+	uniquenessPercentage := float64(uniqueShingles) / float64(len(fingerprints)) * 100
 
 	report := &analysis.PlagiarismReport{
+		Algorithm:            algorithm.Info(),
 		UniquenessPercentage: uniquenessPercentage,
-		TotalShingles:        len(shingles),
+		TotalShingles:        len(fingerprints),
 		UniqueShingles:       uniqueShingles,
+		MatchedShingles:      len(fingerprints) - uniqueShingles,
+		SharedShingles:       sharedShingles,
 		Matches:              matches,
+		SimilarityEstimates:  estimates,
+		ExcludedShingles:     excludedShingles,
+		TemplateShingles:     templateShingles,
+		ExcludedRanges:       newExcludedRanges(excludedRanges),
+		Obfuscation:          doc.obfuscation,
 		ProcessedAt:          time.Now(),
 	}
 
-	log.Printf("Plagiarism analysis completed for file %s: %.2f%% unique", currentFileID, uniquenessPercentage)
+	log.Printf("Plagiarism analysis completed for file %s: %.2f%% unique", doc.fileID, uniquenessPercentage)
 	return report, nil
 }
 
-// storeShingles stores shingles for the current file
-func (ps *Service) storeShingles(ctx context.Context, fileID string, shingles []string, hashes []string) error {
-	if len(shingles) != len(hashes) {
-		return fmt.Errorf("shingles and hashes length mismatch")
+// newObfuscationReport converts the normalization counters to the report section, nil if nothing was found
+func newObfuscationReport(obfuscation Obfuscation) *analysis.ObfuscationReport {
+	if !obfuscation.Detected() {
+		return nil
+	}
+
+	return &analysis.ObfuscationReport{
+		HomoglyphSubstitutions:  obfuscation.Homoglyphs,
+		InvisibleCharacters:     obfuscation.Invisible,
+		CompatibilityCharacters: obfuscation.Compatibility,
+		AffectedWords:           obfuscation.Words,
+	}
+}
+
+// newExcludedRanges converts the excluded regions to the report section
+func newExcludedRanges(ranges []ExcludedRange) []analysis.ExcludedRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	excluded := make([]analysis.ExcludedRange, len(ranges))
+	for i, r := range ranges {
+		excluded[i] = analysis.ExcludedRange{
+			Kind: r.Kind,
+			Span: analysis.TextSpan{
+				StartPos:  r.StartPos,
+				EndPos:    r.EndPos,
+				StartRune: r.StartRune,
+				EndRune:   r.EndRune,
+				StartLine: r.StartLine,
+				EndLine:   r.EndLine,
+			},
+		}
 	}
 
-	shingleData := make([]repository.ShingleData, len(shingles))
-	for i, shingle := range shingles {
+	return excluded
+}
+
+// storeFingerprints stores fingerprints for the current file
+func (ps *Service) storeFingerprints(ctx context.Context, fileID string, algorithmKey string, fingerprints []Fingerprint) error {
+	return ps.shingleRepository.StoreShingles(ctx, fileID, algorithmKey, toShingleData(fingerprints))
+}
+
+// toShingleData converts fingerprints to the stored format
+func toShingleData(fingerprints []Fingerprint) []repository.ShingleData {
+	shingleData := make([]repository.ShingleData, len(fingerprints))
+	for i, fingerprint := range fingerprints {
 		shingleData[i] = repository.ShingleData{
-			Hash:     hashes[i],
-			Text:     shingle,
-			StartPos: i * ps.shingleSize,
-			EndPos:   (i * ps.shingleSize) + len(shingle),
+			Hash:      fingerprint.Hash,
+			Text:      fingerprint.Text,
+			StartPos:  fingerprint.StartPos,
+			EndPos:    fingerprint.EndPos,
+			StartRune: fingerprint.StartRune,
+			EndRune:   fingerprint.EndRune,
+			StartLine: fingerprint.StartLine,
+			EndLine:   fingerprint.EndLine,
 		}
 	}
 
-	return ps.shingleRepository.StoreShingles(ctx, fileID, shingleData)
+	return shingleData
 }
 
-// findMatches searches for plagiarism matches in the database
-func (ps *Service) findMatches(ctx context.Context, currentHashes []string, currentFileID string) ([]analysis.PlagiarismMatch, error) {
-	dbMatches, err := ps.shingleRepository.FindMatchingShingles(ctx, currentHashes, currentFileID)
+// findCandidates finds documents likely similar to the signature through LSH and estimates their similarity
+func (ps *Service) findCandidates(ctx context.Context, signature Signature, currentFileID string, sourceFileIDs []string) ([]analysis.SimilarityEstimate, error) {
+	candidates, err := ps.signatureRepository.FindCandidates(ctx, signature.Bands(), currentFileID, MaxCandidates, sourceFileIDs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query database for matches: %w", err)
+		return nil, fmt.Errorf("failed to query LSH candidates: %w", err)
 	}
 
-	if len(dbMatches) == 0 {
-		return []analysis.PlagiarismMatch{}, nil
+	if len(candidates) == 0 {
+		return []analysis.SimilarityEstimate{}, nil
 	}
 
-	// Group matches by file ID and calculate similarity
-	fileMatches := make(map[string][]repository.ShingleMatch)
-	for _, match := range dbMatches {
-		fileMatches[match.FileID] = append(fileMatches[match.FileID], match)
+	fileIDs := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		fileIDs[i] = candidate.FileID
+	}
+
+	signatures, err := ps.signatureRepository.FindSignatures(ctx, fileIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query candidate signatures: %w", err)
+	}
+
+	estimates := make([]analysis.SimilarityEstimate, 0, len(candidates))
+	for _, candidate := range candidates {
+		estimates = append(estimates, analysis.SimilarityEstimate{
+			FileID:              candidate.FileID,
+			EstimatedSimilarity: EstimateSimilarity(signature, signatures[candidate.FileID]) * 100,
+			SharedBands:         candidate.SharedBands,
+		})
+	}
+
+	sort.SliceStable(estimates, func(i, j int) bool {
+		return estimates[i].EstimatedSimilarity > estimates[j].EstimatedSimilarity
+	})
+
+	return estimates, nil
+}
+
+// findMatches compares the fingerprints of the candidate documents made by the same algorithm with the current ones.
+// Positions of a match point to the original text of the current document.
+// Along with every match it returns the indexes of the current fingerprints matched by the source,
+// and separately those of the sources below the threshold, which are not listed in the report.
+func (ps *Service) findMatches(ctx context.Context, algorithmKey string, text string, fingerprints []Fingerprint, candidates []analysis.SimilarityEstimate) ([]analysis.PlagiarismMatch, [][]int, [][]int, error) {
+	if len(candidates) == 0 {
+		return []analysis.PlagiarismMatch{}, nil, nil, nil
+	}
+
+	fileIDs := make([]string, len(candidates))
+	estimatedSimilarity := make(map[string]float64, len(candidates))
+	for i, candidate := range candidates {
+		fileIDs[i] = candidate.FileID
+		estimatedSimilarity[candidate.FileID] = candidate.EstimatedSimilarity
+	}
+
+	candidateShingles, err := ps.shingleRepository.FindShinglesByFileIDs(ctx, algorithmKey, fileIDs)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to query database for matches: %w", err)
+	}
+
+	currentHashSet := make(map[string]bool, len(fingerprints))
+	for _, fingerprint := range fingerprints {
+		currentHashSet[fingerprint.Hash] = true
+	}
+
+	sourceShingles := make(map[string][]repository.ShingleMatch)
+	for _, shingle := range candidateShingles {
+		sourceShingles[shingle.FileID] = append(sourceShingles[shingle.FileID], shingle)
 	}
 
 	var matches []analysis.PlagiarismMatch
-	totalHashes := len(currentHashes)
-
-	for fileID, fileShingles := range fileMatches {
-		matchedHashes := len(fileShingles)
-		similarity := float64(matchedHashes) / float64(totalHashes) * 100
-
-		if similarity >= 5.0 {
-			startPos := fileShingles[0].StartPos
-			endPos := fileShingles[0].EndPos
-			matchedTexts := []string{fileShingles[0].ShingleText}
-
-			for _, shingle := range fileShingles[1:] {
-				if shingle.StartPos < startPos {
-					startPos = shingle.StartPos
-				}
-				if shingle.EndPos > endPos {
-					endPos = shingle.EndPos
-				}
-				matchedTexts = append(matchedTexts, shingle.ShingleText)
+	var coverages, unlisted [][]int
+	totalHashes := len(fingerprints)
+
+	for _, fileID := range fileIDs {
+		hashes := make(map[string]bool)
+		for _, shingle := range sourceShingles[fileID] {
+			if currentHashSet[shingle.ShingleHash] {
+				hashes[shingle.ShingleHash] = true
 			}
+		}
 
-			matchedText := matchedTexts[0]
-			if len(matchedTexts) > 1 {
-				matchedText += " ... " + matchedTexts[len(matchedTexts)-1]
+		// Similarity is the share of the current fingerprints found in the source
+		var coverage []int
+		for i, fingerprint := range fingerprints {
+			if hashes[fingerprint.Hash] {
+				coverage = append(coverage, i)
 			}
+		}
 
-			match := analysis.PlagiarismMatch{
-				Source:      fmt.Sprintf("Документ %s", fileID),
-				Similarity:  similarity,
-				MatchedText: matchedText,
-				StartPos:    startPos,
-				EndPos:      endPos,
+		if len(coverage) == 0 {
+			continue
+		}
+
+		// Sources below the threshold aren't listed, but what they match isn't unique either
+		similarity := float64(len(coverage)) / float64(totalHashes) * 100
+		if similarity < 5.0 {
+			unlisted = append(unlisted, coverage)
+			continue
+		}
+
+		// Passages are ordered by start, the last one to end may be any of them
+		passages := findPassages(text, fingerprints, sourceShingles[fileID])
+		first, lastIndex := passages[0], 0
+		for i, passage := range passages {
+			if passage.Document.EndPos > passages[lastIndex].Document.EndPos {
+				lastIndex = i
 			}
+		}
+		last := passages[lastIndex]
+
+		matchedText := first.Text
+		if lastIndex != 0 {
+			matchedText += " ... " + last.Text
+		}
 
-			matches = append(matches, match)
+		match := analysis.PlagiarismMatch{
+			FileID:              fileID,
+			Source:              fmt.Sprintf("Документ %s", fileID),
+			Similarity:          similarity,
+			EstimatedSimilarity: estimatedSimilarity[fileID],
+			MatchedShingles:     len(coverage),
+			MatchedText:         matchedText,
+			StartPos:            first.Document.StartPos,
+			EndPos:              last.Document.EndPos,
+			StartRune:           first.Document.StartRune,
+			EndRune:             last.Document.EndRune,
+			StartLine:           first.Document.StartLine,
+			EndLine:             last.Document.EndLine,
+			Passages:            passages,
 		}
+
+		matches = append(matches, match)
+		coverages = append(coverages, coverage)
 	}
 
-	return matches, nil
+	return matches, coverages, unlisted, nil
 }
 
-// calculateUniqueShingles calculates the number of unique shingles
-func (ps *Service) calculateUniqueShingles(currentHashes map[string]bool, matches []analysis.PlagiarismMatch) int {
-	totalHashes := len(currentHashes)
+// BackfillSignatures computes signatures of documents analysed before signatures existed,
+// without them such documents would never become LSH candidates
+func (ps *Service) BackfillSignatures(ctx context.Context, batchSize int) (int, error) {
+	fileIDs, err := ps.signatureRepository.FindUnsignedFileIDs(ctx, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find unsigned files: %w", err)
+	}
+
+	// Documents analysed before signatures existed were always fully shingled
+	shinglingKey := NewShingling(ps.shingleSize).Key()
+
+	for i, fileID := range fileIDs {
+		shingles, err := ps.shingleRepository.FindShinglesByFileIDs(ctx, shinglingKey, []string{fileID})
+		if err != nil {
+			return i, fmt.Errorf("failed to get shingles of file %s: %w", fileID, err)
+		}
 
-	matchedHashes := 0
-	for _, match := range matches {
-		estimatedMatched := int(float64(totalHashes) * match.Similarity / 100.0)
-		matchedHashes += estimatedMatched
+		hashes := make([]string, len(shingles))
+		for j, shingle := range shingles {
+			hashes[j] = shingle.ShingleHash
+		}
+
+		signature := ps.minHasher.Signature(hashes)
+		err = ps.signatureRepository.StoreSignature(ctx, fileID, signature, signature.Bands())
+		if err != nil {
+			return i, fmt.Errorf("failed to store signature of file %s: %w", fileID, err)
+		}
+	}
+
+	return len(fileIDs), nil
+}
+
+// calculateUniqueShingles counts the fingerprints of the current document not matched by any source
+// and the fingerprints matched by several sources. It also fills the contribution of every match:
+// its exclusive fingerprints aren't found in any other source, its shared ones are.
+// Sources that aren't listed count as well, so a fingerprint found in one of them is neither unique
+// nor exclusive to a listed match. Without unlisted sources unique, shared and all exclusive fingerprints
+// add up to the total.
+func (ps *Service) calculateUniqueShingles(total int, matches []analysis.PlagiarismMatch, coverages, unlisted [][]int) (int, int) {
+	sources := make([]int, total)
+	for _, coverage := range slices.Concat(coverages, unlisted) {
+		for _, i := range coverage {
+			sources[i]++
+		}
 	}
 
-	if matchedHashes > totalHashes {
-		matchedHashes = totalHashes
+	unique, shared := 0, 0
+	for _, count := range sources {
+		switch {
+		case count == 0:
+			unique++
+		case count > 1:
+			shared++
+		}
 	}
 
-	uniqueHashes := totalHashes - matchedHashes
-	if uniqueHashes < 0 {
-		uniqueHashes = 0
+	for k, coverage := range coverages {
+		exclusive := 0
+		for _, i := range coverage {
+			if sources[i] == 1 {
+				exclusive++
+			}
+		}
+
+		matches[k].ExclusiveShingles = exclusive
+		matches[k].SharedShingles = len(coverage) - exclusive
+		matches[k].ExclusivePercentage = float64(exclusive) / float64(total) * 100
+		matches[k].SharedPercentage = float64(len(coverage)-exclusive) / float64(total) * 100
 	}
 
-	return uniqueHashes
+	return unique, shared
 }
 
 // CalculateTextStatistics calculates text statistics
 func (ps *Service) CalculateTextStatistics(text string) *analysis.TextStatistics {
 	stats := ps.textProcessor.CalculateTextStatistics(text)
 	return &analysis.TextStatistics{
-		ParagraphCount: stats.ParagraphCount,
-		WordCount:      stats.WordCount,
-		CharacterCount: stats.CharacterCount,
-		SentenceCount:  stats.SentenceCount,
+		ParagraphCount:     stats.ParagraphCount,
+		WordCount:          stats.WordCount,
+		CharacterCount:     stats.CharacterCount,
+		SentenceCount:      stats.SentenceCount,
+		Language:           stats.Language,
+		ParagraphLanguages: stats.ParagraphLanguages,
 	}
 }
 
@@ -200,5 +521,6 @@ func (ps *Service) CalculateTextStatistics(text string) *analysis.TextStatistics
 func (ps *Service) SetShingleSize(size int) {
 	if size > 0 {
 		ps.shingleSize = size
+		ps.algorithms[AlgorithmShingles] = NewShingling(size)
 	}
 }
@@ -2,6 +2,10 @@ package plagiarism
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
 	"testing"
 
 	"fileanalysisservice/internal/domain/analysis"
@@ -11,34 +15,59 @@ import (
 // MockAnalysisRepository is a mock implementation of AnalysisRepository
 type MockAnalysisRepository struct{}
 
-func (m *MockAnalysisRepository) Store(ctx context.Context, analysis *analysis.Analysis) error {
-	return nil
+func (m *MockAnalysisRepository) Store(ctx context.Context, analysis *analysis.Analysis) (bool, error) {
+	return true, nil
 }
 
 func (m *MockAnalysisRepository) FindByID(ctx context.Context, id string) (*analysis.Analysis, error) {
 	return nil, nil
 }
 
+func (m *MockAnalysisRepository) FindByFileID(ctx context.Context, fileID string, algorithm string, options string) (*analysis.Analysis, error) {
+	return nil, nil
+}
+
+func (m *MockAnalysisRepository) FindAllByFileID(ctx context.Context, fileID string) ([]*analysis.Analysis, error) {
+	return nil, nil
+}
+
+func (m *MockAnalysisRepository) DeleteByFileID(ctx context.Context, fileID string) error {
+	return nil
+}
+
 // MockShingleRepository is a mock implementation of ShingleRepository
 type MockShingleRepository struct {
-	storedShingles map[string][]repository.ShingleData
-	matches        []repository.ShingleMatch
+	storedShingles   map[string][]repository.ShingleData
+	templateShingles map[string]map[string][]repository.ShingleData // Задание -> ключ алгоритма и файла -> шинглы
+	matches          []repository.ShingleMatch
 }
 
 func NewMockShingleRepository() *MockShingleRepository {
 	return &MockShingleRepository{
-		storedShingles: make(map[string][]repository.ShingleData),
-		matches:        []repository.ShingleMatch{},
+		storedShingles:   make(map[string][]repository.ShingleData),
+		templateShingles: make(map[string]map[string][]repository.ShingleData),
+		matches:          []repository.ShingleMatch{},
 	}
 }
 
-func (m *MockShingleRepository) StoreShingles(ctx context.Context, fileID string, shingles []repository.ShingleData) error {
+func (m *MockShingleRepository) StoreShingles(ctx context.Context, fileID string, algorithm string, shingles []repository.ShingleData) error {
 	m.storedShingles[fileID] = shingles
 	return nil
 }
 
-func (m *MockShingleRepository) FindMatchingShingles(ctx context.Context, hashes []string, excludeFileID string) ([]repository.ShingleMatch, error) {
-	return m.matches, nil
+func (m *MockShingleRepository) FindShinglesByFileIDs(ctx context.Context, algorithm string, fileIDs []string) ([]repository.ShingleMatch, error) {
+	requested := make(map[string]bool)
+	for _, fileID := range fileIDs {
+		requested[fileID] = true
+	}
+
+	var shingles []repository.ShingleMatch
+	for _, match := range m.matches {
+		if requested[match.FileID] {
+			shingles = append(shingles, match)
+		}
+	}
+	return shingles, nil
 }
 
 func (m *MockShingleRepository) DeleteShingles(ctx context.Context, fileID string) error {
@@ -46,20 +75,117 @@ func (m *MockShingleRepository) DeleteShingles(ctx context.Context, fileID strin
 	return nil
 }
 
+func (m *MockShingleRepository) StoreTemplateShingles(ctx context.Context, assignmentID string, fileID string, algorithm string, shingles []repository.ShingleData) error {
+	if m.templateShingles[assignmentID] == nil {
+		m.templateShingles[assignmentID] = make(map[string][]repository.ShingleData)
+	}
+	m.templateShingles[assignmentID][algorithm+"/"+fileID] = shingles
+	return nil
+}
+
+func (m *MockShingleRepository) FindTemplateHashes(ctx context.Context, assignmentID string, algorithm string) ([]string, error) {
+	var hashes []string
+	for key, shingles := range m.templateShingles[assignmentID] {
+		if !strings.HasPrefix(key, algorithm+"/") {
+			continue
+		}
+		for _, shingle := range shingles {
+			hashes = append(hashes, shingle.Hash)
+		}
+	}
+	return hashes, nil
+}
+
+func (m *MockShingleRepository) FindTemplateFileIDs(ctx context.Context, assignmentID string) ([]string, error) {
+	seen := make(map[string]bool)
+	fileIDs := []string{}
+	for key := range m.templateShingles[assignmentID] {
+		fileID := key[strings.Index(key, "/")+1:]
+		if !seen[fileID] {
+			seen[fileID] = true
+			fileIDs = append(fileIDs, fileID)
+		}
+	}
+	sort.Strings(fileIDs)
+	return fileIDs, nil
+}
+
+func (m *MockShingleRepository) DeleteTemplateShingles(ctx context.Context, assignmentID string, fileID string) error {
+	for key := range m.templateShingles[assignmentID] {
+		if strings.HasSuffix(key, "/"+fileID) {
+			delete(m.templateShingles[assignmentID], key)
+		}
+	}
+	return nil
+}
+
 func (m *MockShingleRepository) SetMatches(matches []repository.ShingleMatch) {
 	m.matches = matches
 }
 
+// MockSignatureRepository is a mock implementation of SignatureRepository
+type MockSignatureRepository struct {
+	signatures map[string][]uint64
+	candidates []repository.LSHCandidate
+}
+
+func NewMockSignatureRepository() *MockSignatureRepository {
+	return &MockSignatureRepository{
+		signatures: make(map[string][]uint64),
+		candidates: []repository.LSHCandidate{},
+	}
+}
+
+func (m *MockSignatureRepository) StoreSignature(ctx context.Context, fileID string, signature []uint64, bands []string) error {
+	m.signatures[fileID] = signature
+	return nil
+}
+
+func (m *MockSignatureRepository) FindCandidates(ctx context.Context, bands []string, excludeFileID string, limit int, sourceFileIDs []string) ([]repository.LSHCandidate, error) {
+	var candidates []repository.LSHCandidate
+	for _, candidate := range m.candidates {
+		if candidate.FileID != excludeFileID && (sourceFileIDs == nil || slices.Contains(sourceFileIDs, candidate.FileID)) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates, nil
+}
+
+func (m *MockSignatureRepository) FindSignatures(ctx context.Context, fileIDs []string) (map[string][]uint64, error) {
+	signatures := make(map[string][]uint64)
+	for _, fileID := range fileIDs {
+		if signature, ok := m.signatures[fileID]; ok {
+			signatures[fileID] = signature
+		}
+	}
+	return signatures, nil
+}
+
+func (m *MockSignatureRepository) FindUnsignedFileIDs(ctx context.Context, limit int) ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockSignatureRepository) DeleteSignature(ctx context.Context, fileID string) error {
+	delete(m.signatures, fileID)
+	return nil
+}
+
+func (m *MockSignatureRepository) SetCandidates(candidates []repository.LSHCandidate) {
+	m.candidates = candidates
+}
+
 func TestPlagiarismService_AnalyzePlagiarism(t *testing.T) {
 	analysisRepo := &MockAnalysisRepository{}
 	shingleRepo := NewMockShingleRepository()
-	service := NewPlagiarismService(analysisRepo, shingleRepo)
+	signatureRepo := NewMockSignatureRepository()
+	service := NewPlagiarismService(analysisRepo, shingleRepo, signatureRepo)
+	processor := NewTextProcessor()
 
 	tests := []struct {
 		name           string
 		text           string
 		fileID         string
-		setupMatches   func(*MockShingleRepository)
+		setupMatches   func(*MockShingleRepository, *MockSignatureRepository)
 		expectedUnique float64
 		expectMatches  bool
 	}{
@@ -67,7 +193,7 @@ func TestPlagiarismService_AnalyzePlagiarism(t *testing.T) {
 			name:           "Empty text",
 			text:           "",
 			fileID:         "file1",
-			setupMatches:   func(repo *MockShingleRepository) {},
+			setupMatches:   func(repo *MockShingleRepository, signatures *MockSignatureRepository) {},
 			expectedUnique: 100.0,
 			expectMatches:  false,
 		},
@@ -75,7 +201,7 @@ func TestPlagiarismService_AnalyzePlagiarism(t *testing.T) {
 			name:           "Unique text with no matches",
 			text:           "Это уникальный текст без совпадений в базе данных",
 			fileID:         "file1",
-			setupMatches:   func(repo *MockShingleRepository) {},
+			setupMatches:   func(repo *MockShingleRepository, signatures *MockSignatureRepository) {},
 			expectedUnique: 100.0,
 			expectMatches:  false,
 		},
@@ -83,24 +209,28 @@ func TestPlagiarismService_AnalyzePlagiarism(t *testing.T) {
 			name:   "Text with matches",
 			text:   "Это текст с некоторыми совпадениями в базе данных для тестирования",
 			fileID: "file1",
-			setupMatches: func(repo *MockShingleRepository) {
+			setupMatches: func(repo *MockShingleRepository, signatures *MockSignatureRepository) {
+				shingles := processor.GenerateShingles(processor.ProcessText("Это текст с некоторыми совпадениями в базе данных для тестирования"), 4)
+				hashes := processor.HashShingles(shingles)
+
 				matches := []repository.ShingleMatch{
 					{
 						FileID:      "file2",
-						ShingleHash: "hash1",
-						ShingleText: "текст некоторыми совпадениями базе",
+						ShingleHash: hashes[0],
+						ShingleText: shingles[0],
 						StartPos:    10,
 						EndPos:      50,
 					},
 					{
 						FileID:      "file2",
-						ShingleHash: "hash2",
-						ShingleText: "совпадениями базе данных для",
+						ShingleHash: hashes[1],
+						ShingleText: shingles[1],
 						StartPos:    30,
 						EndPos:      70,
 					},
 				}
 				repo.SetMatches(matches)
+				signatures.SetCandidates([]repository.LSHCandidate{{FileID: "file2", SharedBands: 3}})
 			},
 			expectedUnique: 85.0,
 			expectMatches:  true,
@@ -109,9 +239,9 @@ func TestPlagiarismService_AnalyzePlagiarism(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.setupMatches(shingleRepo)
+			tt.setupMatches(shingleRepo, signatureRepo)
 
-			report, err := service.AnalyzePlagiarism(context.Background(), tt.text, tt.fileID)
+			report, err := service.AnalyzePlagiarism(context.Background(), tt.text, tt.fileID, "", ExclusionOptions{}, nil)
 
 			if err != nil {
 				t.Errorf("AnalyzePlagiarism() error = %v", err)
@@ -138,6 +268,18 @@ func TestPlagiarismService_AnalyzePlagiarism(t *testing.T) {
 			if report.UniquenessPercentage < 0 || report.UniquenessPercentage > 100 {
 				t.Errorf("UniquenessPercentage = %v, want between 0 and 100", report.UniquenessPercentage)
 			}
+
+			if report.Algorithm.Name != AlgorithmShingles {
+				t.Errorf("Algorithm = %v, want %v", report.Algorithm.Name, AlgorithmShingles)
+			}
+
+			if tt.expectMatches && len(report.SimilarityEstimates) == 0 {
+				t.Error("Expected similarity estimates for candidates")
+			}
+
+			if tt.text != "" && signatureRepo.signatures[tt.fileID] == nil {
+				t.Error("Expected signature to be stored")
+			}
 		})
 	}
 }
@@ -145,7 +287,7 @@ func TestPlagiarismService_AnalyzePlagiarism(t *testing.T) {
 func TestPlagiarismService_findMatches(t *testing.T) {
 	analysisRepo := &MockAnalysisRepository{}
 	shingleRepo := NewMockShingleRepository()
-	service := NewPlagiarismService(analysisRepo, shingleRepo)
+	service := NewPlagiarismService(analysisRepo, shingleRepo, NewMockSignatureRepository())
 
 	mockMatches := []repository.ShingleMatch{
 		{
@@ -169,11 +311,36 @@ func TestPlagiarismService_findMatches(t *testing.T) {
 			StartPos:    10,
 			EndPos:      35,
 		},
+		{
+			FileID:      "file3",
+			ShingleHash: "hash6",
+			ShingleText: "несовпавший шингл",
+			StartPos:    30,
+			EndPos:      50,
+		},
+		{
+			FileID:      "file4",
+			ShingleHash: "hash1",
+			ShingleText: "шингл файла вне кандидатов",
+			StartPos:    0,
+			EndPos:      25,
+		},
 	}
 	shingleRepo.SetMatches(mockMatches)
 
-	hashes := []string{"hash1", "hash2", "hash3", "hash4", "hash5"}
-	matches, err := service.findMatches(context.Background(), hashes, "file1")
+	candidates := []analysis.SimilarityEstimate{
+		{FileID: "file2", EstimatedSimilarity: 40},
+		{FileID: "file3", EstimatedSimilarity: 20},
+	}
+
+	text := "первый второй третий четвертый пятый"
+	var fingerprints []Fingerprint
+	for i, hash := range []string{"hash1", "hash2", "hash3", "hash4", "hash5"} {
+		fingerprints = append(fingerprints, Fingerprint{Hash: hash, StartPos: i * 13, EndPos: i*13 + 12})
+	}
+	fingerprints[4].EndPos = len(text)
+
+	matches, _, unlisted, err := service.findMatches(context.Background(), "shingles:4", text, fingerprints, candidates)
 
 	if err != nil {
 		t.Errorf("findMatches() error = %v", err)
@@ -184,6 +351,10 @@ func TestPlagiarismService_findMatches(t *testing.T) {
 		t.Errorf("Expected 2 matches (one per file), got %d", len(matches))
 	}
 
+	if len(unlisted) != 0 {
+		t.Errorf("Expected every source above the threshold to be listed, got %d unlisted", len(unlisted))
+	}
+
 	for _, match := range matches {
 		if match.Similarity < 5.0 {
 			t.Errorf("Match similarity %v is below threshold", match.Similarity)
@@ -194,13 +365,119 @@ func TestPlagiarismService_findMatches(t *testing.T) {
 		if match.MatchedText == "" {
 			t.Error("Match text should not be empty")
 		}
+		if match.FileID == "file4" {
+			t.Error("Only candidates should be compared")
+		}
+		if match.FileID == "file3" && match.Similarity != 20.0 {
+			t.Errorf("Match similarity = %v, want 20 (only shared shingles count)", match.Similarity)
+		}
+		if match.FileID == "file2" && (match.StartPos != 0 || match.EndPos != 25) {
+			t.Errorf("Match span = [%d, %d), want the positions of the current document [0, 25)", match.StartPos, match.EndPos)
+		}
+		if match.FileID == "file2" {
+			if len(match.Passages) != 1 {
+				t.Fatalf("Expected consecutive shingles to merge into 1 passage, got %d", len(match.Passages))
+			}
+			passage := match.Passages[0]
+			if passage.Text != "первый второй" || passage.WordCount != 2 {
+				t.Errorf("Passage = %q of %d words, want \"первый второй\" of 2 words", passage.Text, passage.WordCount)
+			}
+			if passage.Source.StartPos != 0 || passage.Source.EndPos != 45 {
+				t.Errorf("Passage source span = [%d, %d), want [0, 45)", passage.Source.StartPos, passage.Source.EndPos)
+			}
+		}
+	}
+}
+
+func TestPlagiarismService_AnalyzePlagiarism_Algorithms(t *testing.T) {
+	shingleRepo := NewMockShingleRepository()
+	service := NewPlagiarismService(&MockAnalysisRepository{}, shingleRepo, NewMockSignatureRepository())
+	service.RegisterAlgorithm(NewWinnowing(10, 5))
+
+	text := "Алгоритм winnowing выбирает небольшое подмножество хэшей символьных k-грамм документа и сохраняет только их"
+
+	shingled, err := service.AnalyzePlagiarism(context.Background(), text, "file1", AlgorithmShingles, ExclusionOptions{}, nil)
+	if err != nil {
+		t.Fatalf("AnalyzePlagiarism() error = %v", err)
+	}
+
+	winnowed, err := service.AnalyzePlagiarism(context.Background(), text, "file2", AlgorithmWinnowing, ExclusionOptions{}, nil)
+	if err != nil {
+		t.Fatalf("AnalyzePlagiarism() error = %v", err)
+	}
+
+	if winnowed.Algorithm.Name != AlgorithmWinnowing || winnowed.Algorithm.KGramSize != 10 || winnowed.Algorithm.WindowSize != 5 {
+		t.Errorf("Algorithm = %+v, want winnowing with k=10 and window 5", winnowed.Algorithm)
+	}
+
+	if len(shingleRepo.storedShingles["file2"]) != winnowed.TotalShingles || winnowed.TotalShingles == 0 {
+		t.Errorf("Stored %d fingerprints, report has %d", len(shingleRepo.storedShingles["file2"]), winnowed.TotalShingles)
+	}
+
+	if shingled.Algorithm.Name != AlgorithmShingles {
+		t.Errorf("Algorithm = %v, want %v", shingled.Algorithm.Name, AlgorithmShingles)
+	}
+
+	_, err = service.AnalyzePlagiarism(context.Background(), text, "file3", "unknown", ExclusionOptions{}, nil)
+	if !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("AnalyzePlagiarism() error = %v, want ErrUnknownAlgorithm", err)
+	}
+}
+
+func TestPlagiarismService_calculateUniqueShingles(t *testing.T) {
+	service := NewPlagiarismService(&MockAnalysisRepository{}, NewMockShingleRepository(), NewMockSignatureRepository())
+
+	// Of 10 fingerprints the first source matches 0-5, the second 4-7, so 4 and 5 are shared
+	matches := []analysis.PlagiarismMatch{{FileID: "file2"}, {FileID: "file3"}}
+	coverages := [][]int{{0, 1, 2, 3, 4, 5}, {4, 5, 6, 7}}
+
+	unique, shared := service.calculateUniqueShingles(10, matches, coverages, nil)
+
+	if unique != 2 || shared != 2 {
+		t.Errorf("calculateUniqueShingles() = %d unique, %d shared, want 2 and 2", unique, shared)
+	}
+
+	if matches[0].ExclusiveShingles != 4 || matches[0].SharedShingles != 2 {
+		t.Errorf("First source = %d exclusive, %d shared, want 4 and 2", matches[0].ExclusiveShingles, matches[0].SharedShingles)
+	}
+
+	if matches[1].ExclusiveShingles != 2 || matches[1].SharedShingles != 2 {
+		t.Errorf("Second source = %d exclusive, %d shared, want 2 and 2", matches[1].ExclusiveShingles, matches[1].SharedShingles)
+	}
+
+	// Unique, shared and exclusive parts add up to the whole document
+	total := float64(unique)/10*100 + float64(shared)/10*100
+	for _, match := range matches {
+		total += match.ExclusivePercentage
+	}
+	if total != 100 {
+		t.Errorf("Percentages add up to %v, want 100", total)
+	}
+}
+
+func TestPlagiarismService_calculateUniqueShingles_Unlisted(t *testing.T) {
+	service := NewPlagiarismService(&MockAnalysisRepository{}, NewMockShingleRepository(), NewMockSignatureRepository())
+
+	// Of 100 fingerprints a listed source matches 0-9, unlisted ones match 9-12 and 50-53
+	matches := []analysis.PlagiarismMatch{{FileID: "file2"}}
+	coverages := [][]int{{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}}
+	unlisted := [][]int{{9, 10, 11, 12}, {50, 51, 52, 53}}
+
+	unique, shared := service.calculateUniqueShingles(100, matches, coverages, unlisted)
+
+	if unique != 83 || shared != 1 {
+		t.Errorf("calculateUniqueShingles() = %d unique, %d shared, want 83 and 1", unique, shared)
+	}
+
+	if matches[0].ExclusiveShingles != 9 || matches[0].SharedShingles != 1 {
+		t.Errorf("Listed source = %d exclusive, %d shared, want 9 and 1", matches[0].ExclusiveShingles, matches[0].SharedShingles)
 	}
 }
 
 func TestPlagiarismService_CalculateTextStatistics(t *testing.T) {
 	analysisRepo := &MockAnalysisRepository{}
 	shingleRepo := NewMockShingleRepository()
-	service := NewPlagiarismService(analysisRepo, shingleRepo)
+	service := NewPlagiarismService(analysisRepo, shingleRepo, NewMockSignatureRepository())
 
 	text := `Первый абзац с несколькими предложениями. Это второе предложение!
 
@@ -231,3 +508,35 @@ func TestPlagiarismService_CalculateTextStatistics(t *testing.T) {
 		t.Error("SentenceCount should not be 0")
 	}
 }
+
+func TestPlagiarismService_SourceFiles(t *testing.T) {
+	shingleRepo := NewMockShingleRepository()
+	signatureRepo := NewMockSignatureRepository()
+	service := NewPlagiarismService(&MockAnalysisRepository{}, shingleRepo, signatureRepo)
+	ctx := context.Background()
+
+	text := "Лабораторная работа номер три посвящена сортировке массивов методом слияния"
+	algorithm, _ := service.Algorithm("")
+	var matches []repository.ShingleMatch
+	for _, fingerprint := range algorithm.Fingerprints(NewTextProcessor().Tokenize(text)) {
+		matches = append(matches, repository.ShingleMatch{FileID: "last-year", ShingleHash: fingerprint.Hash, ShingleText: fingerprint.Text})
+	}
+	shingleRepo.SetMatches(matches)
+	signatureRepo.SetCandidates([]repository.LSHCandidate{{FileID: "last-year", SharedBands: 10}})
+
+	report, err := service.AnalyzePlagiarism(ctx, text, "file1", "", ExclusionOptions{}, []string{"last-year"})
+	if err != nil {
+		t.Fatalf("AnalyzePlagiarism() error = %v", err)
+	}
+	if report.UniquenessPercentage == 100 {
+		t.Errorf("Within the scope uniqueness = %.2f, want the source to match", report.UniquenessPercentage)
+	}
+
+	report, err = service.AnalyzePlagiarism(ctx, text, "file1", "", ExclusionOptions{}, []string{})
+	if err != nil {
+		t.Fatalf("AnalyzePlagiarism() error = %v", err)
+	}
+	if report.UniquenessPercentage != 100 || len(report.Matches) != 0 {
+		t.Errorf("Outside of the scope uniqueness = %.2f with %d matches, want 100 without matches", report.UniquenessPercentage, len(report.Matches))
+	}
+}
@@ -0,0 +1,104 @@
+package plagiarism
+
+import (
+	"context"
+	"fmt"
+)
+
+// RegisterTemplate stores the fingerprints of a template of the assignment, such as the task text or instructor
+// provided boilerplate, made by every registered algorithm. Text matching a template is not scored in analyses
+// of the assignment submissions. A template in a programming language is fingerprinted as source code
+// and is only excluded from analyses of code in that language.
+func (ps *Service) RegisterTemplate(ctx context.Context, assignmentID string, fileID string, text string, language string) error {
+	if language != "" {
+		return ps.registerCodeTemplate(ctx, assignmentID, fileID, text, language)
+	}
+
+	tokens := ps.textProcessor.Tokenize(text)
+
+	for _, algorithm := range ps.algorithms {
+		err := ps.shingleRepository.StoreTemplateShingles(ctx, assignmentID, fileID, algorithm.Key(), toShingleData(algorithm.Fingerprints(tokens)))
+		if err != nil {
+			return fmt.Errorf("failed to store template fingerprints: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// registerCodeTemplate stores the fingerprints of a source code template made by every registered algorithm
+func (ps *Service) registerCodeTemplate(ctx context.Context, assignmentID string, fileID string, source string, language string) error {
+	tokens, err := TokenizeCode(language, source)
+	if err != nil {
+		return err
+	}
+
+	for name := range ps.algorithms {
+		algorithm, err := ps.CodeAlgorithm(name, language)
+		if err != nil {
+			return err
+		}
+
+		err = ps.shingleRepository.StoreTemplateShingles(ctx, assignmentID, fileID, algorithm.Key(), toShingleData(algorithm.Fingerprints(tokens)))
+		if err != nil {
+			return fmt.Errorf("failed to store template fingerprints: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RemoveTemplate removes a template of the assignment
+func (ps *Service) RemoveTemplate(ctx context.Context, assignmentID string, fileID string) error {
+	return ps.shingleRepository.DeleteTemplateShingles(ctx, assignmentID, fileID)
+}
+
+// Templates returns the IDs of the template files of the assignment
+func (ps *Service) Templates(ctx context.Context, assignmentID string) ([]string, error) {
+	fileIDs, err := ps.shingleRepository.FindTemplateFileIDs(ctx, assignmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find templates: %w", err)
+	}
+
+	return fileIDs, nil
+}
+
+// excludeTemplates removes the fingerprints found in the templates of the assignment and returns the remaining ones
+// along with the ranges covered by the removed ones
+func (ps *Service) excludeTemplates(ctx context.Context, algorithmKey string, assignmentID string, fingerprints []Fingerprint) ([]Fingerprint, []ExcludedRange, error) {
+	if assignmentID == "" {
+		return fingerprints, nil, nil
+	}
+
+	hashes, err := ps.shingleRepository.FindTemplateHashes(ctx, assignmentID, algorithmKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find template fingerprints: %w", err)
+	}
+
+	if len(hashes) == 0 {
+		return fingerprints, nil, nil
+	}
+
+	templateHashes := wordSet(hashes...)
+
+	scored := make([]Fingerprint, 0, len(fingerprints))
+	var ranges []ExcludedRange
+	for _, fingerprint := range fingerprints {
+		if !templateHashes[fingerprint.Hash] {
+			scored = append(scored, fingerprint)
+			continue
+		}
+
+		ranges = append(ranges, ExcludedRange{
+			Kind:      ExclusionTemplate,
+			StartPos:  fingerprint.StartPos,
+			EndPos:    fingerprint.EndPos,
+			StartRune: fingerprint.StartRune,
+			EndRune:   fingerprint.EndRune,
+			StartLine: fingerprint.StartLine,
+			EndLine:   fingerprint.EndLine,
+		})
+	}
+
+	return scored, mergeRanges(ranges), nil
+}
@@ -0,0 +1,87 @@
+package plagiarism
+
+import (
+	"context"
+	"testing"
+
+	"fileanalysisservice/internal/interfaces/repository"
+)
+
+func TestPlagiarismService_Templates(t *testing.T) {
+	shingleRepo := NewMockShingleRepository()
+	signatureRepo := NewMockSignatureRepository()
+	service := NewPlagiarismService(&MockAnalysisRepository{}, shingleRepo, signatureRepo)
+	service.RegisterAlgorithm(NewWinnowing(10, 5))
+	ctx := context.Background()
+
+	template := "Лабораторная работа номер три посвящена сортировке массивов методом слияния"
+	err := service.RegisterTemplate(ctx, "hw3", "template1", template, "")
+	if err != nil {
+		t.Fatalf("RegisterTemplate() error = %v", err)
+	}
+
+	// Every algorithm gets the template fingerprints
+	if len(shingleRepo.templateShingles["hw3"]) != 2 {
+		t.Errorf("Template stored for %d algorithms, want 2", len(shingleRepo.templateShingles["hw3"]))
+	}
+
+	templates, err := service.Templates(ctx, "hw3")
+	if err != nil || len(templates) != 1 || templates[0] != "template1" {
+		t.Errorf("Templates() = %v, %v, want [template1]", templates, err)
+	}
+
+	// Another submission of the assignment contains the same task text
+	algorithm, _ := service.Algorithm("")
+	var matches []repository.ShingleMatch
+	for _, fingerprint := range algorithm.Fingerprints(NewTextProcessor().Tokenize(template)) {
+		matches = append(matches, repository.ShingleMatch{FileID: "other", ShingleHash: fingerprint.Hash, ShingleText: fingerprint.Text})
+	}
+	shingleRepo.SetMatches(matches)
+	signatureRepo.SetCandidates([]repository.LSHCandidate{{FileID: "other", SharedBands: 10}})
+
+	submission := template + "\n\nСтудент реализовал рекурсивный вариант алгоритма и сравнил его производительность"
+
+	report, err := service.AnalyzePlagiarism(ctx, submission, "file1", "", ExclusionOptions{}, nil)
+	if err != nil {
+		t.Fatalf("AnalyzePlagiarism() error = %v", err)
+	}
+	if report.UniquenessPercentage >= 100 || report.TemplateShingles != 0 {
+		t.Fatalf("Without the assignment uniqueness = %.2f, template shingles = %d, want the task text to match", report.UniquenessPercentage, report.TemplateShingles)
+	}
+
+	report, err = service.AnalyzePlagiarism(ctx, submission, "file1", "", ExclusionOptions{Assignment: "hw3"}, nil)
+	if err != nil {
+		t.Fatalf("AnalyzePlagiarism() error = %v", err)
+	}
+	if report.UniquenessPercentage != 100 || len(report.Matches) != 0 {
+		t.Errorf("With the assignment uniqueness = %.2f with %d matches, want 100 without matches", report.UniquenessPercentage, len(report.Matches))
+	}
+	if report.TemplateShingles == 0 || len(report.ExcludedRanges) != 1 || report.ExcludedRanges[0].Kind != ExclusionTemplate {
+		t.Fatalf("Template shingles = %d, ranges = %+v, want the task text", report.TemplateShingles, report.ExcludedRanges)
+	}
+	span := report.ExcludedRanges[0].Span
+	if got := submission[span.StartPos:span.EndPos]; got != template {
+		t.Errorf("Template range = %q, want %q", got, template)
+	}
+	if got := string([]rune(submission)[span.StartRune:span.EndRune]); got != template {
+		t.Errorf("Template rune range = %q, want %q", got, template)
+	}
+
+	// Templates of other assignments don't matter
+	report, err = service.AnalyzePlagiarism(ctx, submission, "file1", "", ExclusionOptions{Assignment: "hw4"}, nil)
+	if err != nil {
+		t.Fatalf("AnalyzePlagiarism() error = %v", err)
+	}
+	if report.TemplateShingles != 0 {
+		t.Errorf("Template shingles = %d for another assignment, want 0", report.TemplateShingles)
+	}
+
+	err = service.RemoveTemplate(ctx, "hw3", "template1")
+	if err != nil {
+		t.Fatalf("RemoveTemplate() error = %v", err)
+	}
+	templates, _ = service.Templates(ctx, "hw3")
+	if len(templates) != 0 {
+		t.Errorf("Templates() = %v after removal, want none", templates)
+	}
+}
@@ -6,73 +6,86 @@ import (
 	"regexp"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // TextProcessor handles text preprocessing for plagiarism detection
 type TextProcessor struct {
-	stopWords map[string]bool
+	languages []*LanguageProfile // Первый профиль используется, если язык определить не удалось
 }
 
-// NewTextProcessor creates a new text processor
+// NewTextProcessor creates a new text processor with Russian (the default), English, French and Spanish profiles
 func NewTextProcessor() *TextProcessor {
-	stopWords := map[string]bool{
-		"и": true, "в": true, "на": true, "с": true, "по": true, "для": true,
-		"от": true, "до": true, "из": true, "к": true, "о": true, "об": true,
-		"что": true, "как": true, "так": true, "но": true, "а": true, "или": true,
-		"же": true, "бы": true, "ли": true, "не": true, "ни": true, "то": true,
-		"это": true, "этот": true, "эта": true, "эти": true, "тот": true, "та": true,
-		"те": true, "он": true, "она": true, "оно": true, "они": true, "мы": true,
-		"вы": true, "я": true, "ты": true, "его": true, "её": true, "их": true,
-		"наш": true, "ваш": true, "мой": true, "твой": true, "свой": true,
-		"который": true, "которая": true, "которое": true, "которые": true,
-		"где": true, "когда": true, "почему": true, "зачем": true, "куда": true,
-		"откуда": true, "сколько": true, "чем": true, "чего": true, "кого": true,
-		"кому": true, "кем": true, "чему": true, "чём": true, "при": true,
-		"под": true, "над": true, "за": true, "перед": true, "между": true,
-		"через": true, "без": true, "против": true, "вместо": true, "кроме": true,
-		"после": true, "во": true, "со": true, "ко": true,
+	return &TextProcessor{
+		languages: []*LanguageProfile{RussianProfile(), EnglishProfile(), FrenchProfile(), SpanishProfile()},
 	}
+}
 
-	return &TextProcessor{
-		stopWords: stopWords,
+// RegisterLanguage adds a language profile, a profile with the same code is replaced
+func (tp *TextProcessor) RegisterLanguage(profile *LanguageProfile) {
+	for i, language := range tp.languages {
+		if language.Code == profile.Code {
+			tp.languages[i] = profile
+			return
+		}
 	}
+	tp.languages = append(tp.languages, profile)
 }
 
-// CleanText removes punctuation, HTML tags, and normalizes text
-func (tp *TextProcessor) CleanText(text string) string {
-	// Удаляем HTML теги
-	htmlRegex := regexp.MustCompile(`<[^>]*>`)
-	text = htmlRegex.ReplaceAllString(text, " ")
-
-	// Удаляем лишние пробелы и переводы строк
-	spaceRegex := regexp.MustCompile(`\s+`)
-	text = spaceRegex.ReplaceAllString(text, " ")
-
-	// Приводим к нижнему регистру
-	text = strings.ToLower(text)
-
-	// Удаляем пунктуацию, оставляем только буквы и пробелы
-	var result strings.Builder
-	for _, r := range text {
-		if unicode.IsLetter(r) || unicode.IsSpace(r) {
-			result.WriteRune(r)
-		} else {
-			result.WriteRune(' ')
+// DetectLanguage returns the profile of the language the text is written in
+func (tp *TextProcessor) DetectLanguage(text string) *LanguageProfile {
+	words, _ := tp.splitWords(text)
+	return tp.detect(wordTexts(words), nil)
+}
+
+// detect returns the profile scoring best on the words. The current profile is kept unless another one scores higher.
+func (tp *TextProcessor) detect(words []string, current *LanguageProfile) *LanguageProfile {
+	best, bestScore := tp.languages[0], languageScore{}
+	if current != nil {
+		best, bestScore = current, current.score(words)
+	}
+
+	for _, language := range tp.languages {
+		if score := language.score(words); score.greater(bestScore) {
+			best, bestScore = language, score
 		}
 	}
 
-	// Убираем лишние пробелы
-	text = spaceRegex.ReplaceAllString(result.String(), " ")
-	return strings.TrimSpace(text)
+	return best
+}
+
+// wordProfile returns the profile processing a word: the paragraph one or, for a word in another script,
+// the first profile of that script
+func (tp *TextProcessor) wordProfile(word string, paragraph *LanguageProfile) *LanguageProfile {
+	r, _ := utf8.DecodeRuneInString(word)
+	if unicode.Is(paragraph.Script, r) {
+		return paragraph
+	}
+
+	for _, language := range tp.languages {
+		if unicode.Is(language.Script, r) {
+			return language
+		}
+	}
+
+	return paragraph
+}
+
+// CleanText removes punctuation, HTML tags, and normalizes text: lower case, NFKC, no invisible characters
+// and no look-alike letters of another script inside words
+func (tp *TextProcessor) CleanText(text string) string {
+	words, _ := tp.splitWords(text)
+	return strings.Join(wordTexts(words), " ")
 }
 
-// RemoveStopWords removes stop words from text
+// RemoveStopWords removes stop words and short words of the text's language
 func (tp *TextProcessor) RemoveStopWords(text string) string {
 	words := strings.Fields(text)
+	language := tp.detect(words, nil)
 	var filteredWords []string
 
 	for _, word := range words {
-		if !tp.stopWords[word] && len(word) > 2 { // Также убираем слова короче 3 символов
+		if tp.wordProfile(word, language).keeps(word) {
 			filteredWords = append(filteredWords, word)
 		}
 	}
@@ -80,45 +93,200 @@ func (tp *TextProcessor) RemoveStopWords(text string) string {
 	return strings.Join(filteredWords, " ")
 }
 
-// SimpleStem performs basic stemming for Russian words
-func (tp *TextProcessor) SimpleStem(word string) string {
-	// Простой стемминг для русского языка
-	// Удаляем распространенные окончания
-	suffixes := []string{
-		"ость", "ение", "ание", "ение", "ние", "ие", "ые", "ий", "ая", "ое",
-		"ем", "ам", "ах", "ми", "ов", "ев", "ей", "ой", "ый", "ая", "ее",
-		"ет", "ит", "ут", "ют", "ал", "ил", "ел", "ла", "ло", "ли",
+// StemText applies the stemmer of the text's language to all words in text
+func (tp *TextProcessor) StemText(text string) string {
+	words := strings.Fields(text)
+	language := tp.detect(words, nil)
+	var stemmedWords []string
+
+	for _, word := range words {
+		stemmedWords = append(stemmedWords, tp.wordProfile(word, language).Stem(word))
+	}
+
+	return strings.Join(stemmedWords, " ")
+}
+
+// Token is a word of the processed text together with its position in the original text
+type Token struct {
+	Text      string // Обработанное слово: нижний регистр, стемминг
+	StartPos  int    // Смещения слова в исходном тексте в байтах
+	EndPos    int
+	StartRune int // Смещения слова в исходном тексте в символах (рунах)
+	EndRune   int
+	StartLine int // Строки исходного текста, начиная с 1, только для исходного кода
+	EndLine   int
+}
+
+// rawWord is a word of the original text before stop word removal and stemming
+type rawWord struct {
+	text      string // В нижнем регистре
+	start     int
+	end       int
+	startRune int
+	endRune   int
+	paragraph int // Номер абзаца, абзацы разделяются пустой строкой
+}
+
+func wordTexts(words []rawWord) []string {
+	texts := make([]string, len(words))
+	for i, word := range words {
+		texts[i] = word.text
 	}
+	return texts
+}
+
+// Tokenize performs full text preprocessing keeping the position of every processed word in the original text.
+// The language is detected for the whole document and then for every paragraph, so a paragraph quoted
+// in another language is processed with its own stop words and stemmer. For a text in one language it
+// produces the same words as CleanText, RemoveStopWords and StemText applied one after another.
+func (tp *TextProcessor) Tokenize(text string) []Token {
+	tokens, _ := tp.TokenizeWithObfuscation(text)
+	return tokens
+}
 
-	for _, suffix := range suffixes {
-		if strings.HasSuffix(word, suffix) && len(word) > len(suffix)+2 {
-			return word[:len(word)-len(suffix)]
+// TokenizeWithObfuscation tokenizes the text as Tokenize does and counts the obfuscated characters
+// that were normalized: look-alike letters of another script, invisible and compatibility characters
+func (tp *TextProcessor) TokenizeWithObfuscation(text string) ([]Token, Obfuscation) {
+	words, obfuscation := tp.splitWords(text)
+	document := tp.detect(wordTexts(words), nil)
+
+	var tokens []Token
+	for start := 0; start < len(words); {
+		end := start
+		for end < len(words) && words[end].paragraph == words[start].paragraph {
+			end++
 		}
+
+		paragraph := tp.detect(wordTexts(words[start:end]), document)
+		for _, word := range words[start:end] {
+			language := tp.wordProfile(word.text, paragraph)
+			if !language.keeps(word.text) {
+				continue
+			}
+
+			tokens = append(tokens, Token{
+				Text:      language.Stem(word.text),
+				StartPos:  word.start,
+				EndPos:    word.end,
+				StartRune: word.startRune,
+				EndRune:   word.endRune,
+			})
+		}
+
+		start = end
 	}
 
-	return word
+	return tokens, obfuscation
 }
 
-// StemText applies stemming to all words in text
-func (tp *TextProcessor) StemText(text string) string {
-	words := strings.Fields(text)
-	var stemmedWords []string
+// DetectLanguages returns the language of the document and the amount of paragraphs written in every language
+func (tp *TextProcessor) DetectLanguages(text string) (string, map[string]int) {
+	words, _ := tp.splitWords(text)
+	document := tp.detect(wordTexts(words), nil)
 
-	for _, word := range words {
-		stemmed := tp.SimpleStem(word)
-		stemmedWords = append(stemmedWords, stemmed)
+	paragraphs := make(map[string]int)
+	for start := 0; start < len(words); {
+		end := start
+		for end < len(words) && words[end].paragraph == words[start].paragraph {
+			end++
+		}
+
+		paragraphs[tp.detect(wordTexts(words[start:end]), document).Code]++
+		start = end
 	}
 
-	return strings.Join(stemmedWords, " ")
+	return document.Code, paragraphs
+}
+
+// splitWords splits the original text into normalized lower case words of letters skipping HTML tags,
+// as CleanText does. Invisible characters and combining marks inside a word don't break it.
+func (tp *TextProcessor) splitWords(text string) ([]rawWord, Obfuscation) {
+	var words []rawWord
+	var obfuscation Obfuscation
+	wordStart, wordStartRune := -1, 0
+	wordEnd, wordEndRune := 0, 0
+	paragraph, paragraphBreak := 0, false
+
+	flush := func() {
+		if wordStart < 0 {
+			return
+		}
+
+		normalized, wordObfuscation := normalizeWord(text[wordStart:wordEnd])
+		obfuscation.add(wordObfuscation)
+
+		words = append(words, rawWord{
+			text:      strings.ToLower(normalized),
+			start:     wordStart,
+			end:       wordEnd,
+			startRune: wordStartRune,
+			endRune:   wordEndRune,
+			paragraph: paragraph,
+		})
+		wordStart = -1
+	}
+
+	runeIndex := 0
+	previous := rune(0)
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+
+		// HTML теги заменяются пробелом, как в CleanText
+		if r == '<' {
+			if closing := strings.IndexByte(text[i+1:], '>'); closing >= 0 {
+				flush()
+				tagEnd := i + 1 + closing + 1
+				runeIndex += utf8.RuneCountInString(text[i:tagEnd])
+				i = tagEnd
+				previous = '>'
+				continue
+			}
+		}
+
+		if r == '\n' && previous == '\n' {
+			paragraphBreak = true
+		}
+
+		switch {
+		case unicode.IsLetter(r):
+			if wordStart < 0 {
+				if paragraphBreak && len(words) > 0 {
+					paragraph++
+				}
+				paragraphBreak = false
+				wordStart, wordStartRune = i, runeIndex
+			}
+			wordEnd, wordEndRune = i+size, runeIndex+1
+		case wordStart >= 0 && unicode.Is(unicode.M, r):
+			wordEnd, wordEndRune = i+size, runeIndex+1
+		case wordStart >= 0 && isInvisible(r):
+			// Невидимый символ внутри слова не разрывает его, но и не входит в его границы, если слово на нем кончается
+		default:
+			flush()
+		}
+
+		previous = r
+		i += size
+		runeIndex++
+	}
+	flush()
+
+	return words, obfuscation
+}
+
+// JoinTokens builds the processed text from tokens
+func JoinTokens(tokens []Token) string {
+	words := make([]string, len(tokens))
+	for i, token := range tokens {
+		words[i] = token.Text
+	}
+
+	return strings.Join(words, " ")
 }
 
 // ProcessText performs full text preprocessing
 func (tp *TextProcessor) ProcessText(text string) string {
-	cleaned := tp.CleanText(text)
-	withoutStopWords := tp.RemoveStopWords(cleaned)
-	stemmed := tp.StemText(withoutStopWords)
-
-	return stemmed
+	return JoinTokens(tp.Tokenize(text))
 }
 
 // GenerateShingles creates n-grams from processed text
@@ -169,18 +337,24 @@ func (tp *TextProcessor) CalculateTextStatistics(originalText string) *TextStati
 		sentenceCount = 0
 	}
 
+	language, paragraphLanguages := tp.DetectLanguages(originalText)
+
 	return &TextStatistics{
-		ParagraphCount: paragraphCount,
-		WordCount:      wordCount,
-		CharacterCount: characterCount,
-		SentenceCount:  sentenceCount,
+		ParagraphCount:     paragraphCount,
+		WordCount:          wordCount,
+		CharacterCount:     characterCount,
+		SentenceCount:      sentenceCount,
+		Language:           language,
+		ParagraphLanguages: paragraphLanguages,
 	}
 }
 
 // TextStatistics represents text analysis statistics
 type TextStatistics struct {
-	ParagraphCount int `json:"paragraph_count"`
-	WordCount      int `json:"word_count"`
-	CharacterCount int `json:"character_count"`
-	SentenceCount  int `json:"sentence_count"`
+	ParagraphCount     int            `json:"paragraph_count"`
+	WordCount          int            `json:"word_count"`
+	CharacterCount     int            `json:"character_count"`
+	SentenceCount      int            `json:"sentence_count"`
+	Language           string         `json:"language"`
+	ParagraphLanguages map[string]int `json:"paragraph_languages"`
 }
@@ -175,6 +175,30 @@ func TestTextProcessor_ProcessText(t *testing.T) {
 	}
 }
 
+func TestTextProcessor_Tokenize(t *testing.T) {
+	processor := NewTextProcessor()
+
+	input := "<p>Это <b>быстрая</b> коричневая лиса, которая прыгает через забор!</p>\nQuick brown fox"
+	tokens := processor.Tokenize(input)
+
+	// The words are the same as produced by the step by step preprocessing
+	expected := processor.StemText(processor.RemoveStopWords(processor.CleanText(input)))
+	if JoinTokens(tokens) != expected {
+		t.Errorf("JoinTokens(Tokenize()) = %q, want %q", JoinTokens(tokens), expected)
+	}
+
+	runes := []rune(input)
+	for _, token := range tokens {
+		original := input[token.StartPos:token.EndPos]
+		if processor.StemText(toLower(original)) != token.Text {
+			t.Errorf("Token %q points to %q", token.Text, original)
+		}
+		if string(runes[token.StartRune:token.EndRune]) != original {
+			t.Errorf("Rune offsets of %q point to %q", original, string(runes[token.StartRune:token.EndRune]))
+		}
+	}
+}
+
 func contains(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
 		if s[i:i+len(substr)] == substr {
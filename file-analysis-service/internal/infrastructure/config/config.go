@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -13,8 +14,33 @@ type Config struct {
 	ServerPort string
 
 	// External apis
-	FileStoringServiceBaseURL string
-	WordCloudBaseURL          string
+	FileStoringServiceGRPCAddr string
+	WordCloudBaseURL           string
+
+	// File storing service client config
+	FileStoringTimeout          time.Duration
+	FileStoringRetries          int
+	FileStoringMaxResponseMB    int
+	FileStoringBreakerThreshold int
+	FileStoringBreakerCooldown  time.Duration
+
+	// Word cloud config
+	WordCloudRenderer string
+	WordCloudFont     string
+	WordCloudPalette  string
+	WordCloudWidth    int
+	WordCloudHeight   int
+	WordCloudMaxWords int
+
+	// Analysis jobs config
+	AnalysisWorkers int
+
+	// Winnowing config
+	WinnowingKGramSize  int
+	WinnowingWindowSize int
+
+	// Similarity clusters config
+	ClusterThreshold float64
 
 	// Database config
 	DBHost     string
@@ -46,8 +72,33 @@ func Load() (*Config, error) {
 		ServerPort: getEnv("SERVER_PORT", "8001"),
 
 		// External apis
-		FileStoringServiceBaseURL: getEnv("FILE_STORING_SERVICE_API_URL", "http://file-storing-service:8000"),
-		WordCloudBaseURL:          getEnv("WORD_CLOUD_API_URL", "https://quickchart.io/wordcloud"),
+		FileStoringServiceGRPCAddr: getEnv("FILE_STORING_SERVICE_GRPC_ADDR", "file-storing-service:9000"),
+		WordCloudBaseURL:           getEnv("WORD_CLOUD_API_URL", "https://quickchart.io/wordcloud"),
+
+		// File storing service client config
+		FileStoringTimeout:          getDurationEnv("FILE_STORING_SERVICE_TIMEOUT", 30*time.Second),
+		FileStoringRetries:          getIntEnv("FILE_STORING_SERVICE_RETRIES", 3),
+		FileStoringMaxResponseMB:    getIntEnv("FILE_STORING_SERVICE_MAX_RESPONSE_MB", 32),
+		FileStoringBreakerThreshold: getIntEnv("FILE_STORING_SERVICE_BREAKER_THRESHOLD", 5),
+		FileStoringBreakerCooldown:  getDurationEnv("FILE_STORING_SERVICE_BREAKER_COOLDOWN", 30*time.Second),
+
+		// Word cloud config
+		WordCloudRenderer: getEnv("WORD_CLOUD_RENDERER", "local"),
+		WordCloudFont:     getEnv("WORD_CLOUD_FONT", ""),
+		WordCloudPalette:  getEnv("WORD_CLOUD_PALETTE", ""),
+		WordCloudWidth:    getIntEnv("WORD_CLOUD_WIDTH", 800),
+		WordCloudHeight:   getIntEnv("WORD_CLOUD_HEIGHT", 600),
+		WordCloudMaxWords: getIntEnv("WORD_CLOUD_MAX_WORDS", 100),
+
+		// Analysis jobs config
+		AnalysisWorkers: getIntEnv("ANALYSIS_WORKERS", 4),
+
+		// Winnowing config
+		WinnowingKGramSize:  getIntEnv("WINNOWING_KGRAM_SIZE", 25),
+		WinnowingWindowSize: getIntEnv("WINNOWING_WINDOW_SIZE", 20),
+
+		// Similarity clusters config
+		ClusterThreshold: getFloatEnv("CLUSTER_THRESHOLD", 50),
 
 		// Database config
 		DBHost:     getEnv("DB_HOST", "localhost"),
@@ -87,3 +138,36 @@ func getBoolEnv(key string, fallback bool) bool {
 	}
 	return fallback
 }
+
+// Helper function to get integer environment variable with a fallback value
+func getIntEnv(key string, fallback int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		intValue, err := strconv.Atoi(value)
+		if err == nil {
+			return intValue
+		}
+	}
+	return fallback
+}
+
+// Helper function to get float environment variable with a fallback value
+func getFloatEnv(key string, fallback float64) float64 {
+	if value, exists := os.LookupEnv(key); exists {
+		floatValue, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			return floatValue
+		}
+	}
+	return fallback
+}
+
+// Helper function to get duration environment variable (e.g. 30s) with a fallback value
+func getDurationEnv(key string, fallback time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		durationValue, err := time.ParseDuration(value)
+		if err == nil {
+			return durationValue
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,72 @@
+package filestoringservice
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitBreaker stops calling file-storing-service after a number of consecutive failures.
+// Once the cooldown passes a single trial request is let through: its success closes the breaker,
+// its failure opens it for another cooldown.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int // Количество ошибок подряд, после которого запросы не отправляются, 0 — без ограничения
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	trial     bool // Пробный запрос после паузы уже отправлен
+	now       func() time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// allow reports whether a request may be sent
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.threshold < 1 || b.failures < b.threshold {
+		return true
+	}
+	if b.now().Before(b.openUntil) || b.trial {
+		return false
+	}
+
+	b.trial = true
+	return true
+}
+
+// success records a request that reached a healthy service
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.trial = false
+}
+
+// failure records a request that failed because of the service or the network
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trial = false
+	if b.threshold > 0 && b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+// cancel records a request abandoned by the caller, it says nothing about the service
+func (b *circuitBreaker) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+}
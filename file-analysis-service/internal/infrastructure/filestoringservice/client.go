@@ -0,0 +1,161 @@
+package filestoringservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"fileanalysisservice/internal/infrastructure/config"
+	pb "fileanalysisservice/internal/infrastructure/filestoringservice/filestorage/v1"
+)
+
+var (
+	// ErrFileNotFound is returned when file-storing-service doesn't know the file
+	ErrFileNotFound = errors.New("file not found")
+	// ErrFileTooLarge is returned when the response exceeds the configured size
+	ErrFileTooLarge = errors.New("file is too large")
+	// ErrUnavailable is returned when file-storing-service doesn't respond or keeps failing
+	ErrUnavailable = errors.New("file storing service is unavailable")
+)
+
+const (
+	// retryBaseBackoff is the delay before the first retry, it doubles with every further attempt
+	retryBaseBackoff = 200 * time.Millisecond
+	// retryMaxBackoff caps the delay between retries
+	retryMaxBackoff = 5 * time.Second
+)
+
+// FileStoringService is the gRPC client of file-storing-service. Every attempt has a deadline,
+// transient failures are retried with jittered backoff and a circuit breaker stops calling a failing service.
+type FileStoringService struct {
+	client          pb.FileStorageClient
+	timeout         time.Duration
+	retries         int
+	backoff         time.Duration
+	maxResponseSize int64
+	breaker         *circuitBreaker
+}
+
+// NewFileStoringService creates the client, the connection is established on the first call
+func NewFileStoringService(cfg *config.Config) (*FileStoringService, error) {
+	conn, err := grpc.NewClient(cfg.FileStoringServiceGRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file storing service client: %w", err)
+	}
+
+	return newFileStoringService(pb.NewFileStorageClient(conn), cfg), nil
+}
+
+func newFileStoringService(client pb.FileStorageClient, cfg *config.Config) *FileStoringService {
+	return &FileStoringService{
+		client:          client,
+		timeout:         cfg.FileStoringTimeout,
+		retries:         max(cfg.FileStoringRetries, 0),
+		backoff:         retryBaseBackoff,
+		maxResponseSize: int64(cfg.FileStoringMaxResponseMB) << 20,
+		breaker:         newCircuitBreaker(cfg.FileStoringBreakerThreshold, cfg.FileStoringBreakerCooldown),
+	}
+}
+
+// call runs the request with a deadline per attempt. NOT_FOUND and FAILED_PRECONDITION map to ErrFileNotFound
+// and ErrNotSubmitted, UNAVAILABLE, DEADLINE_EXCEEDED, RESOURCE_EXHAUSTED and ABORTED are retried
+// and end with ErrUnavailable, other errors are returned as is.
+func (fileStoringService *FileStoringService) call(ctx context.Context, request func(ctx context.Context) error) error {
+	var lastErr error
+
+	for attempt := 0; attempt <= fileStoringService.retries; attempt++ {
+		if attempt > 0 {
+			err := sleep(ctx, jitteredBackoff(fileStoringService.backoff, attempt))
+			if err != nil {
+				return err
+			}
+		}
+
+		if !fileStoringService.breaker.allow() {
+			return fmt.Errorf("%w: circuit breaker is open", ErrUnavailable)
+		}
+
+		err := fileStoringService.attempt(ctx, request)
+		switch {
+		case err == nil:
+			fileStoringService.breaker.success()
+			return nil
+		case ctx.Err() != nil:
+			fileStoringService.breaker.cancel()
+			return ctx.Err()
+		case !isTransient(err):
+			// The service answered, it is healthy even if the file isn't there
+			fileStoringService.breaker.success()
+			return mapStatus(err)
+		}
+
+		fileStoringService.breaker.failure()
+		lastErr = err
+	}
+
+	return fmt.Errorf("%w: %v", ErrUnavailable, lastErr)
+}
+
+func (fileStoringService *FileStoringService) attempt(ctx context.Context, request func(ctx context.Context) error) error {
+	if fileStoringService.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, fileStoringService.timeout)
+		defer cancel()
+	}
+
+	return request(ctx)
+}
+
+// isTransient reports whether a failed request may succeed when repeated
+func isTransient(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// mapStatus turns the status codes the callers handle into the errors of the package
+func mapStatus(err error) error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return fmt.Errorf("%w: %s", ErrFileNotFound, status.Convert(err).Message())
+	case codes.FailedPrecondition:
+		return fmt.Errorf("%w: %s", ErrNotSubmitted, status.Convert(err).Message())
+	default:
+		return err
+	}
+}
+
+// jitteredBackoff returns the delay before the retry following the given attempt,
+// a random half of it spreads the retries of concurrent workers
+func jitteredBackoff(base time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < retryMaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, retryMaxBackoff)
+
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// sleep waits for the delay or until the context is done
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,341 @@
+package filestoringservice
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"fileanalysisservice/internal/infrastructure/config"
+	pb "fileanalysisservice/internal/infrastructure/filestoringservice/filestorage/v1"
+)
+
+// fakeFileStorage is an in-process file-storing-service, unset methods are unimplemented
+type fakeFileStorage struct {
+	pb.UnimplementedFileStorageServer
+
+	calls       atomic.Int32
+	download    func(req *pb.DownloadRequest, stream grpc.ServerStreamingServer[pb.DownloadResponse]) error
+	getMetadata func(req *pb.GetMetadataRequest) (*pb.FileMetadata, error)
+	getScope    func(req *pb.GetScopeRequest) (*pb.Scope, error)
+	watch       func(stream grpc.ServerStreamingServer[pb.FileChange]) error
+}
+
+func (f *fakeFileStorage) Download(req *pb.DownloadRequest, stream grpc.ServerStreamingServer[pb.DownloadResponse]) error {
+	f.calls.Add(1)
+	return f.download(req, stream)
+}
+
+func (f *fakeFileStorage) GetMetadata(_ context.Context, req *pb.GetMetadataRequest) (*pb.FileMetadata, error) {
+	f.calls.Add(1)
+	return f.getMetadata(req)
+}
+
+func (f *fakeFileStorage) GetScope(_ context.Context, req *pb.GetScopeRequest) (*pb.Scope, error) {
+	f.calls.Add(1)
+	return f.getScope(req)
+}
+
+func (f *fakeFileStorage) WatchChanges(_ *pb.WatchChangesRequest, stream grpc.ServerStreamingServer[pb.FileChange]) error {
+	f.calls.Add(1)
+	return f.watch(stream)
+}
+
+func newTestClient(t *testing.T, server *fakeFileStorage) *FileStoringService {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	pb.RegisterFileStorageServer(grpcServer, server)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	client := newFileStoringService(pb.NewFileStorageClient(conn), &config.Config{
+		FileStoringTimeout:          time.Second,
+		FileStoringRetries:          2,
+		FileStoringMaxResponseMB:    1,
+		FileStoringBreakerThreshold: 3,
+		FileStoringBreakerCooldown:  time.Minute,
+	})
+	client.backoff = time.Millisecond
+
+	return client
+}
+
+// sendContent streams the metadata and the content in chunks like file-storing-service
+func sendContent(stream grpc.ServerStreamingServer[pb.DownloadResponse], id string, content []byte) error {
+	err := stream.Send(&pb.DownloadResponse{Payload: &pb.DownloadResponse_Metadata{Metadata: &pb.FileMetadata{Id: id, Size: int64(len(content))}}})
+	if err != nil {
+		return err
+	}
+
+	for chunk := range slices.Chunk(content, 64<<10) {
+		if err := stream.Send(&pb.DownloadResponse{Payload: &pb.DownloadResponse_Chunk{Chunk: chunk}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestFileStoringService_Download(t *testing.T) {
+	server := &fakeFileStorage{
+		download: func(req *pb.DownloadRequest, stream grpc.ServerStreamingServer[pb.DownloadResponse]) error {
+			switch {
+			case req.GetId() != "file1":
+				return status.Error(codes.NotFound, "file not found")
+			case req.GetRendition() == pb.Rendition_RENDITION_TEXT:
+				return sendContent(stream, req.GetId(), []byte("текст документа"))
+			default:
+				return sendContent(stream, req.GetId(), []byte("func main() {\n\tprintln()\n}\n"))
+			}
+		},
+	}
+	client := newTestClient(t, server)
+	ctx := context.Background()
+
+	content, err := client.GetFileContent(ctx, "file1")
+	if err != nil || content != "текст документа" {
+		t.Errorf("GetFileContent() = %q, %v", content, err)
+	}
+
+	source, err := client.GetFileSource(ctx, "file1")
+	if err != nil || source != "func main() {\n\tprintln()\n}\n" {
+		t.Errorf("GetFileSource() = %q, %v, want the original content", source, err)
+	}
+
+	_, err = client.GetFileContent(ctx, "missing")
+	if !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("GetFileContent() error = %v, want ErrFileNotFound", err)
+	}
+	if server.calls.Load() != 3 {
+		t.Errorf("Calls = %d, want 3 without retries of NOT_FOUND", server.calls.Load())
+	}
+}
+
+func TestFileStoringService_Retries(t *testing.T) {
+	var failures atomic.Int32
+	failures.Store(2)
+	server := &fakeFileStorage{
+		download: func(req *pb.DownloadRequest, stream grpc.ServerStreamingServer[pb.DownloadResponse]) error {
+			if failures.Add(-1) >= 0 {
+				return status.Error(codes.Unavailable, "upstream failure")
+			}
+			return sendContent(stream, req.GetId(), []byte("текст"))
+		},
+	}
+	client := newTestClient(t, server)
+
+	content, err := client.GetFileContent(context.Background(), "file1")
+	if err != nil || content != "текст" {
+		t.Fatalf("GetFileContent() = %q, %v, want the third attempt to succeed", content, err)
+	}
+	if server.calls.Load() != 3 {
+		t.Errorf("Calls = %d, want 3", server.calls.Load())
+	}
+
+	failures.Store(10)
+	_, err = client.GetFileContent(context.Background(), "file1")
+	if !errors.Is(err, ErrUnavailable) {
+		t.Errorf("GetFileContent() error = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestFileStoringService_CircuitBreaker(t *testing.T) {
+	server := &fakeFileStorage{
+		download: func(*pb.DownloadRequest, grpc.ServerStreamingServer[pb.DownloadResponse]) error {
+			return status.Error(codes.Unavailable, "unavailable")
+		},
+	}
+	client := newTestClient(t, server)
+	now := time.Now()
+	client.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := client.GetFileContent(ctx, "file1")
+	if !errors.Is(err, ErrUnavailable) || server.calls.Load() != 3 {
+		t.Fatalf("GetFileContent() error = %v after %d calls, want ErrUnavailable after 3", err, server.calls.Load())
+	}
+
+	// Three failures in a row open the breaker, the service is not called anymore
+	_, err = client.GetFileContent(ctx, "file1")
+	if !errors.Is(err, ErrUnavailable) || server.calls.Load() != 3 {
+		t.Errorf("GetFileContent() error = %v after %d calls, want the breaker to reject the request", err, server.calls.Load())
+	}
+
+	// After the cooldown a single trial request is sent, its failure opens the breaker again
+	now = now.Add(2 * time.Minute)
+	_, _ = client.GetFileContent(ctx, "file1")
+	if server.calls.Load() != 4 {
+		t.Errorf("Calls = %d after the cooldown, want one trial request", server.calls.Load())
+	}
+}
+
+func TestFileStoringService_ResponseSize(t *testing.T) {
+	server := &fakeFileStorage{
+		download: func(req *pb.DownloadRequest, stream grpc.ServerStreamingServer[pb.DownloadResponse]) error {
+			return sendContent(stream, req.GetId(), []byte(strings.Repeat("а", 1<<20)))
+		},
+	}
+	client := newTestClient(t, server)
+
+	_, err := client.GetFileContent(context.Background(), "file1")
+	if !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("GetFileContent() error = %v, want ErrFileTooLarge", err)
+	}
+	if server.calls.Load() != 1 {
+		t.Errorf("Calls = %d, want a too large file not to be retried", server.calls.Load())
+	}
+}
+
+func TestFileStoringService_Context(t *testing.T) {
+	client := newTestClient(t, &fakeFileStorage{
+		download: func(_ *pb.DownloadRequest, stream grpc.ServerStreamingServer[pb.DownloadResponse]) error {
+			<-stream.Context().Done()
+			return stream.Context().Err()
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetFileContent(ctx, "file1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetFileContent() error = %v, want the context deadline", err)
+	}
+	if !client.breaker.allow() {
+		t.Error("Cancelled requests must not open the breaker")
+	}
+}
+
+func TestFileStoringService_GetScope(t *testing.T) {
+	client := newTestClient(t, &fakeFileStorage{
+		getScope: func(req *pb.GetScopeRequest) (*pb.Scope, error) {
+			switch req.GetScope() {
+			case "all":
+				return &pb.Scope{AssignmentId: "hw1", CourseId: "c1", AllFiles: true}, nil
+			case "assignment":
+				return &pb.Scope{AssignmentId: "hw1", CourseId: "c1"}, nil
+			default:
+				return nil, status.Error(codes.FailedPrecondition, "file is not submitted to an assignment")
+			}
+		},
+	})
+	ctx := context.Background()
+
+	scope, err := client.GetScope(ctx, "file1", "all")
+	if err != nil || scope.AssignmentID != "hw1" || scope.FileIDs != nil {
+		t.Errorf("GetScope() = %+v, %v", scope, err)
+	}
+
+	// No other submissions is an empty scope, not all files
+	scope, err = client.GetScope(ctx, "file1", "assignment")
+	if err != nil || scope.FileIDs == nil || len(scope.FileIDs) != 0 {
+		t.Errorf("GetScope() = %+v, %v, want an empty scope", scope, err)
+	}
+
+	_, err = client.GetScope(ctx, "file1", "course")
+	if !errors.Is(err, ErrNotSubmitted) {
+		t.Errorf("GetScope() error = %v, want ErrNotSubmitted", err)
+	}
+}
+
+func TestFileStoringService_GetMetadata(t *testing.T) {
+	uploadedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	client := newTestClient(t, &fakeFileStorage{
+		getMetadata: func(req *pb.GetMetadataRequest) (*pb.FileMetadata, error) {
+			if req.GetId() != "file1" {
+				return nil, status.Error(codes.NotFound, "file not found")
+			}
+			return &pb.FileMetadata{
+				Id:           "file1",
+				Name:         "essay.docx",
+				ContentType:  "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+				Size:         1024,
+				Hash:         "hash1",
+				Uploader:     "student1",
+				AssignmentId: "hw1",
+				UploadedAt:   timestamppb.New(uploadedAt),
+			}, nil
+		},
+	})
+	ctx := context.Background()
+
+	metadata, err := client.GetMetadata(ctx, "file1")
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+
+	want := FileMetadata{
+		ID:           "file1",
+		Name:         "essay.docx",
+		ContentType:  "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		Size:         1024,
+		Hash:         "hash1",
+		Uploader:     "student1",
+		AssignmentID: "hw1",
+		UploadedAt:   uploadedAt,
+	}
+	if *metadata != want {
+		t.Errorf("GetMetadata() = %+v, want %+v", *metadata, want)
+	}
+
+	_, err = client.GetMetadata(ctx, "missing")
+	if !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("GetMetadata() error = %v, want ErrFileNotFound", err)
+	}
+}
+
+func TestFileStoringService_WatchChanges(t *testing.T) {
+	occurredAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	client := newTestClient(t, &fakeFileStorage{
+		watch: func(stream grpc.ServerStreamingServer[pb.FileChange]) error {
+			for _, change := range []*pb.FileChange{
+				{Kind: pb.FileChange_KIND_CREATED, File: &pb.FileMetadata{Id: "file1", AssignmentId: "hw1"}, OccurredAt: timestamppb.New(occurredAt)},
+				{Kind: pb.FileChange_KIND_UNSPECIFIED, File: &pb.FileMetadata{Id: "file2"}},
+				{Kind: pb.FileChange_KIND_DELETED, File: &pb.FileMetadata{Id: "file1"}, OccurredAt: timestamppb.New(occurredAt)},
+			} {
+				if err := stream.Send(change); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	var changes []FileChange
+	err := client.WatchChanges(context.Background(), func(_ context.Context, change FileChange) error {
+		changes = append(changes, change)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WatchChanges() error = %v", err)
+	}
+
+	want := []FileChange{
+		{Kind: ChangeCreated, FileID: "file1", AssignmentID: "hw1", OccurredAt: occurredAt},
+		{Kind: ChangeDeleted, FileID: "file1", OccurredAt: occurredAt},
+	}
+	if !slices.Equal(changes, want) {
+		t.Errorf("Changes = %+v, want %+v", changes, want)
+	}
+}
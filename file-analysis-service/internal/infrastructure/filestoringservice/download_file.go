@@ -1,39 +1,53 @@
 package filestoringservice
 
 import (
-	"fileanalysisservice/internal/infrastructure/config"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
+	"strings"
+
+	pb "fileanalysisservice/internal/infrastructure/filestoringservice/filestorage/v1"
 )
 
-type FileStoringService struct {
-	basePath string
+// GetFileContent returns the text rendition of the file
+func (fileStoringService *FileStoringService) GetFileContent(ctx context.Context, id string) (string, error) {
+	return fileStoringService.download(ctx, id, pb.Rendition_RENDITION_TEXT)
 }
 
-func NewFileStoringService(cfg *config.Config) *FileStoringService {
-	return &FileStoringService{
-		basePath: cfg.FileStoringServiceBaseURL,
-	}
-}
+// download reads the streamed rendition of the file, a stream longer than the response size limit is cut
+// with ErrFileTooLarge without reading the rest
+func (fileStoringService *FileStoringService) download(ctx context.Context, id string, rendition pb.Rendition) (string, error) {
+	var content strings.Builder
 
-func (fileStoringService *FileStoringService) GetFileContent(id string) (string, error) {
-	res, err := http.Get(fileStoringService.basePath + "/files/" + id + "/download")
-	if err != nil {
-		return "", err
-	}
+	err := fileStoringService.call(ctx, func(ctx context.Context) error {
+		content.Reset()
 
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
+		stream, err := fileStoringService.client.Download(ctx, &pb.DownloadRequest{Id: id, Rendition: rendition})
 		if err != nil {
-			fmt.Println("Error closing body")
+			return err
 		}
-	}(res.Body)
 
-	body, err := io.ReadAll(res.Body)
+		for {
+			res, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			chunk := res.GetChunk()
+			limit := fileStoringService.maxResponseSize
+			if limit > 0 && int64(content.Len()+len(chunk)) > limit {
+				return fmt.Errorf("%w: %s is more than %d bytes", ErrFileTooLarge, id, limit)
+			}
+			content.Write(chunk)
+		}
+	})
 	if err != nil {
 		return "", err
 	}
 
-	return string(body), nil
+	return content.String(), nil
 }
@@ -0,0 +1,5 @@
+// Package filestoragev1 is the generated client of the file-storing-service gRPC API,
+// the definitions are owned by file-storing-service in api/proto/filestorage/v1
+package filestoragev1
+
+//go:generate protoc -I ../../../../../../file-storing-service/api/proto --go_out=../.. --go_opt=paths=source_relative,Mfilestorage/v1/file_storage.proto=fileanalysisservice/internal/infrastructure/filestoringservice/filestorage/v1;filestoragev1 --go-grpc_out=../.. --go-grpc_opt=paths=source_relative,Mfilestorage/v1/file_storage.proto=fileanalysisservice/internal/infrastructure/filestoringservice/filestorage/v1;filestoragev1 filestorage/v1/file_storage.proto
@@ -0,0 +1,45 @@
+package filestoringservice
+
+import (
+	"context"
+	"time"
+
+	pb "fileanalysisservice/internal/infrastructure/filestoringservice/filestorage/v1"
+)
+
+// FileMetadata describes a file stored in file-storing-service
+type FileMetadata struct {
+	ID           string
+	Name         string
+	ContentType  string
+	Size         int64
+	Hash         string
+	Uploader     string
+	AssignmentID string // Пусто, если файл не сдан в задание
+	UploadedAt   time.Time
+}
+
+// GetMetadata retrieves the metadata of a file without its content
+func (fileStoringService *FileStoringService) GetMetadata(ctx context.Context, id string) (*FileMetadata, error) {
+	var res *pb.FileMetadata
+
+	err := fileStoringService.call(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = fileStoringService.client.GetMetadata(ctx, &pb.GetMetadataRequest{Id: id})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileMetadata{
+		ID:           res.GetId(),
+		Name:         res.GetName(),
+		ContentType:  res.GetContentType(),
+		Size:         res.GetSize(),
+		Hash:         res.GetHash(),
+		Uploader:     res.GetUploader(),
+		AssignmentID: res.GetAssignmentId(),
+		UploadedAt:   res.GetUploadedAt().AsTime(),
+	}, nil
+}
@@ -0,0 +1,39 @@
+package filestoringservice
+
+import (
+	"context"
+	"errors"
+
+	pb "fileanalysisservice/internal/infrastructure/filestoringservice/filestorage/v1"
+)
+
+// ErrNotSubmitted is returned when a scope narrower than all files is requested for a file outside of courses
+var ErrNotSubmitted = errors.New("file is not submitted to an assignment")
+
+// Scope is the set of files a file is compared with
+type Scope struct {
+	AssignmentID string // Пусто, если файл не сдан в задание
+	CourseID     string
+	FileIDs      []string // nil — все файлы
+}
+
+func (fileStoringService *FileStoringService) GetScope(ctx context.Context, id string, scope string) (*Scope, error) {
+	var res *pb.Scope
+
+	err := fileStoringService.call(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = fileStoringService.client.GetScope(ctx, &pb.GetScopeRequest{Id: id, Scope: scope})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Scope{AssignmentID: res.GetAssignmentId(), CourseID: res.GetCourseId()}
+	if !res.GetAllFiles() {
+		// An empty scope is not the same as all files
+		result.FileIDs = append([]string{}, res.GetFileIds()...)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,13 @@
+package filestoringservice
+
+import (
+	"context"
+
+	pb "fileanalysisservice/internal/infrastructure/filestoringservice/filestorage/v1"
+)
+
+// GetFileSource returns the original content of the file. Unlike GetFileContent it keeps the indentation
+// and line breaks, source code is analysed as it was uploaded.
+func (fileStoringService *FileStoringService) GetFileSource(ctx context.Context, id string) (string, error) {
+	return fileStoringService.download(ctx, id, pb.Rendition_RENDITION_ORIGINAL)
+}
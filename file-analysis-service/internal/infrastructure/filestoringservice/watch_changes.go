@@ -0,0 +1,68 @@
+package filestoringservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	pb "fileanalysisservice/internal/infrastructure/filestoringservice/filestorage/v1"
+)
+
+// ChangeKind is what happened to a file
+type ChangeKind string
+
+const (
+	ChangeCreated ChangeKind = "created"
+	ChangeDeleted ChangeKind = "deleted"
+)
+
+// FileChange is an upload or a deletion of a file in file-storing-service
+type FileChange struct {
+	Kind         ChangeKind
+	FileID       string
+	AssignmentID string // Пусто, если файл не сдан в задание
+	OccurredAt   time.Time
+}
+
+// WatchChanges calls handle for every file uploaded or deleted after the call, until the context is done,
+// the stream breaks or handle fails. Changes made while the stream is down are not replayed.
+func (fileStoringService *FileStoringService) WatchChanges(ctx context.Context, handle func(ctx context.Context, change FileChange) error) error {
+	stream, err := fileStoringService.client.WatchChanges(ctx, &pb.WatchChangesRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to watch changes: %w", err)
+	}
+
+	for {
+		res, err := stream.Recv()
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to receive change: %w", err)
+		}
+
+		var kind ChangeKind
+		switch res.GetKind() {
+		case pb.FileChange_KIND_CREATED:
+			kind = ChangeCreated
+		case pb.FileChange_KIND_DELETED:
+			kind = ChangeDeleted
+		default:
+			continue
+		}
+
+		err = handle(ctx, FileChange{
+			Kind:         kind,
+			FileID:       res.GetFile().GetId(),
+			AssignmentID: res.GetFile().GetAssignmentId(),
+			OccurredAt:   res.GetOccurredAt().AsTime(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
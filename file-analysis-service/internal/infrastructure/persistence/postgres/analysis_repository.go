@@ -24,62 +24,88 @@ func NewAnalysisRepository(db *sql.DB) *AnalysisRepository {
 	}
 }
 
-// Store saves a file to the database
-func (r *AnalysisRepository) Store(ctx context.Context, analysis *analysis.Analysis) error {
+// Store saves an analysis to the database. A new analysis becomes the next version of the analyses
+// of its file, the assigned version is set on the analysis. Versions of a file are assigned under a lock,
+// so concurrent analyses of the same file get consecutive versions.
+// A file has one analysis per algorithm and options besides reruns, false is returned if it is already stored.
+func (r *AnalysisRepository) Store(ctx context.Context, analysis *analysis.Analysis) (bool, error) {
 	query := `
-		INSERT INTO analysis (id, file_id, image_location, plagiarism_report, statistics, updated_at, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		ON CONFLICT (id) DO UPDATE SET
-			file_id = EXCLUDED.file_id,
-			image_location = EXCLUDED.image_location,
-			plagiarism_report = EXCLUDED.plagiarism_report,
-			statistics = EXCLUDED.statistics,
-			updated_at = EXCLUDED.updated_at
+		INSERT INTO analysis (id, file_id, version, algorithm, options, rerun, image_key, image_location, plagiarism_report, statistics, updated_at, created_at)
+		SELECT $1, $2, COALESCE(MAX(version), 0) + 1, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		FROM analysis
+		WHERE file_id = $2
+		ON CONFLICT DO NOTHING
+		RETURNING version
 	`
 
 	// Convert reports to JSON strings
 	var plagiarismReportJSON, statisticsJSON *string
 
 	if reportJSON, err := analysis.GetPlagiarismReportJSON(); err != nil {
-		return fmt.Errorf("failed to marshal plagiarism report: %w", err)
+		return false, fmt.Errorf("failed to marshal plagiarism report: %w", err)
 	} else if reportJSON != "" {
 		plagiarismReportJSON = &reportJSON
 	}
 
 	if statsJSON, err := analysis.GetStatisticsJSON(); err != nil {
-		return fmt.Errorf("failed to marshal statistics: %w", err)
+		return false, fmt.Errorf("failed to marshal statistics: %w", err)
 	} else if statsJSON != "" {
 		statisticsJSON = &statsJSON
 	}
 
-	_, err := r.db.ExecContext(
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// The lock is held until the transaction ends, the next version of the file is computed by one analysis at a time
+	_, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, analysis.FileID)
+	if err != nil {
+		return false, fmt.Errorf("failed to lock analysis versions: %w", err)
+	}
+
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		analysis.ID,
 		analysis.FileID,
+		analysis.Algorithm,
+		analysis.Options,
+		analysis.Rerun,
+		analysis.ImageKey,
 		analysis.ImageLocation,
 		plagiarismReportJSON,
 		statisticsJSON,
 		analysis.UpdatedAt,
 		analysis.CreatedAt,
-	)
+	).Scan(&analysis.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to store analysis: %w", err)
+		return false, fmt.Errorf("failed to store analysis: %w", err)
 	}
 
-	return nil
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit analysis: %w", err)
+	}
+
+	return true, nil
 }
 
-// findBy implements universal find logic.
-func (r *AnalysisRepository) findBy(ctx context.Context, key string, value any) (*analysis.Analysis, error) {
-	query := fmt.Sprintf(`
-		SELECT id, file_id, image_location, plagiarism_report, statistics, updated_at, created_at
-		FROM analysis
-		WHERE %s = $1
-	`, key)
+// analysisColumns lists the columns read by every analysis query
+const analysisColumns = `id, file_id, version, algorithm, options, rerun, image_key, image_location, plagiarism_report, statistics, updated_at, created_at`
 
-	row := r.db.QueryRowContext(ctx, query, value)
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
 
+// scanAnalysis reads an analysis selected with analysisColumns
+func scanAnalysis(row rowScanner) (*analysis.Analysis, error) {
 	var f analysis.Analysis
 	var updatedAt, createdAt time.Time
 	var plagiarismReportJSON, statisticsJSON sql.NullString
@@ -87,6 +113,11 @@ func (r *AnalysisRepository) findBy(ctx context.Context, key string, value any)
 	err := row.Scan(
 		&f.ID,
 		&f.FileID,
+		&f.Version,
+		&f.Algorithm,
+		&f.Options,
+		&f.Rerun,
+		&f.ImageKey,
 		&f.ImageLocation,
 		&plagiarismReportJSON,
 		&statisticsJSON,
@@ -94,10 +125,7 @@ func (r *AnalysisRepository) findBy(ctx context.Context, key string, value any)
 		&createdAt,
 	)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to find analysis: %w", err)
+		return nil, err
 	}
 
 	f.UpdatedAt = updatedAt
@@ -118,6 +146,91 @@ func (r *AnalysisRepository) findBy(ctx context.Context, key string, value any)
 	return &f, nil
 }
 
+// findBy implements universal find logic.
+func (r *AnalysisRepository) findBy(ctx context.Context, key string, value any) (*analysis.Analysis, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM analysis
+		WHERE %s = $1
+	`, analysisColumns, key)
+
+	f, err := scanAnalysis(r.db.QueryRowContext(ctx, query, value))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find analysis: %w", err)
+	}
+
+	return f, nil
+}
+
 func (r *AnalysisRepository) FindByID(ctx context.Context, id string) (*analysis.Analysis, error) {
 	return r.findBy(ctx, "id", id)
 }
+
+// FindByFileID retrieves the latest analysis of the file made with the algorithm and options,
+// an empty algorithm or options match any
+func (r *AnalysisRepository) FindByFileID(ctx context.Context, fileID string, algorithm string, options string) (*analysis.Analysis, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM analysis
+		WHERE file_id = $1 AND ($2 = '' OR algorithm = $2) AND ($3 = '' OR options = $3)
+		ORDER BY version DESC
+		LIMIT 1
+	`, analysisColumns)
+
+	f, err := scanAnalysis(r.db.QueryRowContext(ctx, query, fileID, algorithm, options))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find analysis: %w", err)
+	}
+
+	return f, nil
+}
+
+// FindAllByFileID retrieves every analysis made for the file, oldest version first
+func (r *AnalysisRepository) FindAllByFileID(ctx context.Context, fileID string) ([]*analysis.Analysis, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM analysis
+		WHERE file_id = $1
+		ORDER BY version
+	`, analysisColumns)
+
+	rows, err := r.db.QueryContext(ctx, query, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query analyses: %w", err)
+	}
+	defer rows.Close()
+
+	var analyses []*analysis.Analysis
+	for rows.Next() {
+		f, err := scanAnalysis(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan analysis: %w", err)
+		}
+
+		analyses = append(analyses, f)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over analyses: %w", err)
+	}
+
+	return analyses, nil
+}
+
+// DeleteByFileID removes every analysis made for the file
+func (r *AnalysisRepository) DeleteByFileID(ctx context.Context, fileID string) error {
+	query := `DELETE FROM analysis WHERE file_id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to delete analyses: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fileanalysisservice/internal/domain/analysis"
+)
+
+func TestAnalysisRepository_Versions(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := []string{
+		`CREATE TABLE analysis (
+			id TEXT PRIMARY KEY,
+			file_id TEXT NOT NULL,
+			version INTEGER NOT NULL DEFAULT 0,
+			algorithm TEXT NOT NULL DEFAULT '',
+			options TEXT NOT NULL DEFAULT '',
+			rerun BOOLEAN NOT NULL DEFAULT FALSE,
+			image_key TEXT NOT NULL DEFAULT '',
+			image_location TEXT NOT NULL,
+			plagiarism_report TEXT,
+			statistics TEXT,
+			updated_at DATETIME NOT NULL,
+			created_at DATETIME NOT NULL,
+			UNIQUE (file_id, version)
+		)`,
+		`CREATE UNIQUE INDEX idx_analysis_file_request ON analysis(file_id, algorithm, options) WHERE NOT rerun`,
+	}
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatalf("Failed to create test table: %v", err)
+		}
+	}
+
+	repo := NewAnalysisRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	for i, id := range []string{"analysis1", "analysis2"} {
+		a := &analysis.Analysis{ID: id, FileID: "file1", Algorithm: "shingles:4", Rerun: i > 0, ImageKey: "image" + id, UpdatedAt: now, CreatedAt: now}
+		if stored, err := repo.Store(ctx, a); err != nil || !stored {
+			t.Fatalf("Store() = %v, %v, want the analysis stored", stored, err)
+		}
+		if a.Version != i+1 {
+			t.Errorf("Version = %d, want %d", a.Version, i+1)
+		}
+	}
+
+	// Другой файл нумерует свои версии с начала
+	other := &analysis.Analysis{ID: "analysis3", FileID: "file2", Algorithm: "shingles:4", UpdatedAt: now, CreatedAt: now}
+	if stored, err := repo.Store(ctx, other); err != nil || !stored || other.Version != 1 {
+		t.Errorf("Store() version = %d, %v, %v, want 1", other.Version, stored, err)
+	}
+
+	// Второй анализ с теми же параметрами не сохраняется, если это не повторный анализ
+	duplicate := &analysis.Analysis{ID: "analysis4", FileID: "file1", Algorithm: "shingles:4", UpdatedAt: now, CreatedAt: now}
+	if stored, err := repo.Store(ctx, duplicate); err != nil || stored {
+		t.Errorf("Store() = %v, %v, want the duplicate skipped", stored, err)
+	}
+
+	// С другими параметрами анализ сохраняется следующей версией
+	scoped := &analysis.Analysis{ID: "analysis5", FileID: "file1", Algorithm: "shingles:4", Options: "scope=assignment", UpdatedAt: now, CreatedAt: now}
+	if stored, err := repo.Store(ctx, scoped); err != nil || !stored || scoped.Version != 3 {
+		t.Errorf("Store() version = %d, %v, %v, want 3", scoped.Version, stored, err)
+	}
+
+	history, err := repo.FindAllByFileID(ctx, "file1")
+	if err != nil {
+		t.Fatalf("FindAllByFileID() error = %v", err)
+	}
+	if len(history) != 3 || history[0].Version != 1 || history[1].Version != 2 || !history[1].Rerun || history[2].ID != "analysis5" {
+		t.Errorf("FindAllByFileID() = %+v, want versions 1 to 3", history)
+	}
+}
+
+func TestAnalysisRepository_FindByFileID(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec(`CREATE TABLE analysis (
+		id TEXT PRIMARY KEY,
+		file_id TEXT NOT NULL,
+		version INTEGER NOT NULL DEFAULT 0,
+		algorithm TEXT NOT NULL DEFAULT '',
+		options TEXT NOT NULL DEFAULT '',
+		rerun BOOLEAN NOT NULL DEFAULT FALSE,
+		image_key TEXT NOT NULL DEFAULT '',
+		image_location TEXT NOT NULL,
+		plagiarism_report TEXT,
+		statistics TEXT,
+		updated_at DATETIME NOT NULL,
+		created_at DATETIME NOT NULL
+	)`)
+	if err != nil {
+		t.Fatalf("Failed to create test table: %v", err)
+	}
+
+	repo := NewAnalysisRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	found, err := repo.FindByFileID(ctx, "file1", "shingles:4", "")
+	if err != nil || found != nil {
+		t.Fatalf("FindByFileID() = %+v, %v before the file is analysed", found, err)
+	}
+
+	// Идентификатор анализа не совпадает ни с файлом, ни с ключом облака слов
+	for _, a := range []*analysis.Analysis{
+		{ID: "analysis1", FileID: "file1", Algorithm: "shingles:4", Options: "scope=all", ImageKey: "image1"},
+		{ID: "analysis2", FileID: "file1", Algorithm: "winnowing:5:4", Options: "scope=all", ImageKey: "image2"},
+		{ID: "analysis3", FileID: "file1", Algorithm: "shingles:4", Options: "scope=all", Rerun: true, ImageKey: "image3"},
+		{ID: "analysis4", FileID: "file1", Algorithm: "shingles:4", Options: "scope=assignment", ImageKey: "image4"},
+	} {
+		a.UpdatedAt, a.CreatedAt = now, now
+		if _, err := repo.Store(ctx, a); err != nil {
+			t.Fatalf("Store() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		algorithm string
+		options   string
+		want      string
+	}{
+		{algorithm: "shingles:4", options: "scope=all", want: "analysis3"},
+		{algorithm: "shingles:4", options: "scope=assignment", want: "analysis4"},
+		{algorithm: "winnowing:5:4", options: "scope=all", want: "analysis2"},
+		{algorithm: "shingles:4", options: "", want: "analysis4"},
+		{algorithm: "", options: "", want: "analysis4"},
+	}
+
+	for _, tt := range tests {
+		found, err := repo.FindByFileID(ctx, "file1", tt.algorithm, tt.options)
+		if err != nil || found == nil || found.ID != tt.want {
+			t.Errorf("FindByFileID(%q, %q) = %+v, %v, want %s", tt.algorithm, tt.options, found, err, tt.want)
+		}
+	}
+
+	found, err = repo.FindByFileID(ctx, "file1", "code:shingles:10:go", "")
+	if err != nil || found != nil {
+		t.Errorf("FindByFileID() = %+v, %v, want no analysis of another algorithm", found, err)
+	}
+
+	found, err = repo.FindByFileID(ctx, "file1", "winnowing:5:4", "scope=course")
+	if err != nil || found != nil {
+		t.Errorf("FindByFileID() = %+v, %v, want no analysis with other options", found, err)
+	}
+}
@@ -67,10 +67,245 @@ func createTables(db *sql.DB) error {
 		return fmt.Errorf("failed to create shingles table: %w", err)
 	}
 
+	// Алгоритм, которым получены отпечатки (шинглы или winnowing), старые записи — полные шинглы по 4 слова
+	_, err = db.Exec(`ALTER TABLE shingles ADD COLUMN IF NOT EXISTS algorithm VARCHAR(64) NOT NULL DEFAULT 'shingles:4'`)
+	if err != nil {
+		return fmt.Errorf("failed to add shingles algorithm column: %w", err)
+	}
+
+	// Позиции в исходном тексте в символах, position_start и position_end — в байтах
+	shingleRuneQueries := []string{
+		`ALTER TABLE shingles ADD COLUMN IF NOT EXISTS rune_start INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE shingles ADD COLUMN IF NOT EXISTS rune_end INTEGER NOT NULL DEFAULT 0`,
+	}
+
+	for _, shingleRuneQuery := range shingleRuneQueries {
+		_, err = db.Exec(shingleRuneQuery)
+		if err != nil {
+			return fmt.Errorf("failed to add shingles rune offset columns: %w", err)
+		}
+	}
+
+	// Строки исходного кода, которые покрывает отпечаток, 0 для текста
+	shingleLineQueries := []string{
+		`ALTER TABLE shingles ADD COLUMN IF NOT EXISTS line_start INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE shingles ADD COLUMN IF NOT EXISTS line_end INTEGER NOT NULL DEFAULT 0`,
+	}
+
+	for _, shingleLineQuery := range shingleLineQueries {
+		_, err = db.Exec(shingleLineQuery)
+		if err != nil {
+			return fmt.Errorf("failed to add shingles line columns: %w", err)
+		}
+	}
+
+	// Пространство имен шинглов: '' — сданные работы, 'template:<задание>' — шаблоны задания
+	_, err = db.Exec(`ALTER TABLE shingles ADD COLUMN IF NOT EXISTS namespace VARCHAR(255) NOT NULL DEFAULT ''`)
+	if err != nil {
+		return fmt.Errorf("failed to add shingles namespace column: %w", err)
+	}
+
+	jobsQuery := `
+		CREATE TABLE IF NOT EXISTS analysis_jobs (
+			id VARCHAR(255) PRIMARY KEY,
+			file_id VARCHAR(255) NOT NULL,
+			status VARCHAR(16) NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			max_attempts INTEGER NOT NULL,
+			last_error TEXT NOT NULL DEFAULT '',
+			analysis_id VARCHAR(255) NOT NULL DEFAULT '',
+			run_at TIMESTAMP NOT NULL,
+			lease_until TIMESTAMP NULL,
+			finished_at TIMESTAMP NULL,
+			updated_at TIMESTAMP NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)
+	`
+
+	_, err = db.Exec(jobsQuery)
+	if err != nil {
+		return fmt.Errorf("failed to create analysis jobs table: %w", err)
+	}
+
+	_, err = db.Exec(`ALTER TABLE analysis_jobs ADD COLUMN IF NOT EXISTS algorithm VARCHAR(32) NOT NULL DEFAULT ''`)
+	if err != nil {
+		return fmt.Errorf("failed to add analysis jobs algorithm column: %w", err)
+	}
+
+	// Исключение цитат и списка литературы из расчета уникальности
+	_, err = db.Exec(`ALTER TABLE analysis_jobs ADD COLUMN IF NOT EXISTS exclude_quotes BOOLEAN NOT NULL DEFAULT FALSE`)
+	if err != nil {
+		return fmt.Errorf("failed to add analysis jobs exclude quotes column: %w", err)
+	}
+
+	_, err = db.Exec(`ALTER TABLE analysis_jobs ADD COLUMN IF NOT EXISTS exclude_bibliography BOOLEAN NOT NULL DEFAULT FALSE`)
+	if err != nil {
+		return fmt.Errorf("failed to add analysis jobs exclude bibliography column: %w", err)
+	}
+
+	_, err = db.Exec(`ALTER TABLE analysis_jobs ADD COLUMN IF NOT EXISTS assignment_id VARCHAR(255) NOT NULL DEFAULT ''`)
+	if err != nil {
+		return fmt.Errorf("failed to add analysis jobs assignment column: %w", err)
+	}
+
+	_, err = db.Exec(`ALTER TABLE analysis_jobs ADD COLUMN IF NOT EXISTS scope VARCHAR(32) NOT NULL DEFAULT 'all'`)
+	if err != nil {
+		return fmt.Errorf("failed to add analysis jobs scope column: %w", err)
+	}
+
+	// Язык программирования в режиме исходного кода, пусто для текста
+	_, err = db.Exec(`ALTER TABLE analysis_jobs ADD COLUMN IF NOT EXISTS code_language VARCHAR(16) NOT NULL DEFAULT ''`)
+	if err != nil {
+		return fmt.Errorf("failed to add analysis jobs code language column: %w", err)
+	}
+
+	// Повторный анализ создает новую версию, даже если файл уже проанализирован
+	_, err = db.Exec(`ALTER TABLE analysis_jobs ADD COLUMN IF NOT EXISTS rerun BOOLEAN NOT NULL DEFAULT FALSE`)
+	if err != nil {
+		return fmt.Errorf("failed to add analysis jobs rerun column: %w", err)
+	}
+
+	// Версии анализов файла: существующие анализы нумеруются по времени создания
+	analysisVersionQueries := []string{
+		`ALTER TABLE analysis ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 0`,
+		`
+		UPDATE analysis SET version = numbered.version
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (PARTITION BY file_id ORDER BY created_at, id) AS version
+			FROM analysis
+		) AS numbered
+		WHERE analysis.id = numbered.id AND analysis.version = 0
+		`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_analysis_file_version ON analysis(file_id, version)`,
+	}
+
+	for _, analysisVersionQuery := range analysisVersionQueries {
+		_, err = db.Exec(analysisVersionQuery)
+		if err != nil {
+			return fmt.Errorf("failed to add analysis versions: %w", err)
+		}
+	}
+
+	// Идентификатор анализа отделен от ключа облака слов в хранилище, у старых анализов это один и тот же UUID.
+	// Алгоритм старых анализов восстанавливается по отчету, отчеты без описания алгоритма сделаны шинглами по 4 слова.
+	analysisIdentityQueries := []string{
+		`ALTER TABLE analysis ADD COLUMN IF NOT EXISTS image_key TEXT NOT NULL DEFAULT ''`,
+		`UPDATE analysis SET image_key = id WHERE image_key = ''`,
+		`ALTER TABLE analysis ADD COLUMN IF NOT EXISTS algorithm TEXT NOT NULL DEFAULT ''`,
+		`
+		UPDATE analysis SET algorithm =
+			CASE WHEN plagiarism_report->'algorithm'->>'code_language' IS NOT NULL THEN 'code:' ELSE '' END ||
+			COALESCE(plagiarism_report->'algorithm'->>'name', 'shingles') || ':' ||
+			COALESCE(plagiarism_report->'algorithm'->>'kgram_size', '4') ||
+			CASE WHEN plagiarism_report->'algorithm'->>'name' = 'winnowing'
+				THEN ':' || COALESCE(plagiarism_report->'algorithm'->>'window_size', '0') ELSE '' END ||
+			COALESCE(':' || (plagiarism_report->'algorithm'->>'code_language'), '')
+		WHERE algorithm = '' AND plagiarism_report IS NOT NULL
+		`,
+		// Версии уникальны в пределах файла (idx_analysis_file_version), отдельный индекс по алгоритму не нужен
+		`DROP INDEX IF EXISTS idx_analysis_file_algorithm_version`,
+		// Параметры старых анализов восстанавливаются по первой задаче, которая их создала.
+		// Анализы без задачи остаются без параметров и не переиспользуются новыми задачами.
+		`ALTER TABLE analysis ADD COLUMN IF NOT EXISTS options TEXT NOT NULL DEFAULT ''`,
+		`
+		UPDATE analysis SET options = requested.options
+		FROM (
+			SELECT DISTINCT ON (analysis_id) analysis_id,
+				'scope=' || scope ||
+				',quotes=' || (exclude_quotes AND code_language = '')::text ||
+				',bibliography=' || (exclude_bibliography AND code_language = '')::text ||
+				',assignment=' || assignment_id AS options
+			FROM analysis_jobs
+			WHERE analysis_id <> ''
+			ORDER BY analysis_id, created_at
+		) AS requested
+		WHERE analysis.id = requested.analysis_id AND analysis.options = ''
+		`,
+		// Файл анализируется с одними параметрами один раз, остальные анализы с ними — повторные.
+		// Из уже сохраненных повторов первым остается анализ с наименьшей версией.
+		`ALTER TABLE analysis ADD COLUMN IF NOT EXISTS rerun BOOLEAN NOT NULL DEFAULT FALSE`,
+		`
+		UPDATE analysis SET rerun = TRUE
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (PARTITION BY file_id, algorithm, options ORDER BY version) AS position
+			FROM analysis
+			WHERE NOT rerun
+		) AS numbered
+		WHERE analysis.id = numbered.id AND numbered.position > 1
+		`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_analysis_file_request ON analysis(file_id, algorithm, options) WHERE NOT rerun`,
+	}
+
+	for _, analysisIdentityQuery := range analysisIdentityQueries {
+		_, err = db.Exec(analysisIdentityQuery)
+		if err != nil {
+			return fmt.Errorf("failed to separate analysis identity: %w", err)
+		}
+	}
+
+	// MinHash сигнатуры документов и LSH бакеты для поиска кандидатов
+	signatureQueries := []string{
+		`
+		CREATE TABLE IF NOT EXISTS minhash_signatures (
+			file_id VARCHAR(255) PRIMARY KEY,
+			signature BYTEA NOT NULL
+		)
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS lsh_bands (
+			band_key VARCHAR(32) NOT NULL,
+			file_id VARCHAR(255) NOT NULL,
+			PRIMARY KEY (band_key, file_id)
+		)
+		`,
+	}
+
+	for _, signatureQuery := range signatureQueries {
+		_, err = db.Exec(signatureQuery)
+		if err != nil {
+			return fmt.Errorf("failed to create signature tables: %w", err)
+		}
+	}
+
+	// Обработанные события других сервисов, повторная доставка события игнорируется
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS processed_events (
+			id VARCHAR(255) PRIMARY KEY,
+			type VARCHAR(255) NOT NULL,
+			processed_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create processed events table: %w", err)
+	}
+
+	// Файлы, удаленные в file-storing-service: их задачи отменяются, а новые анализы не сохраняются
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS deleted_files (
+			file_id VARCHAR(255) PRIMARY KEY,
+			deleted_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create deleted files table: %w", err)
+	}
+
 	// Создание индексов для таблицы shingles
 	indexQueries := []string{
 		`CREATE INDEX IF NOT EXISTS idx_shingle_hash ON shingles(shingle_hash)`,
 		`CREATE INDEX IF NOT EXISTS idx_file_id ON shingles(file_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_shingles_namespace ON shingles(namespace, algorithm)`,
+		`CREATE INDEX IF NOT EXISTS idx_lsh_bands_file_id ON lsh_bands(file_id)`,
+		// Очередь задач анализа: выборка готовых задач и не более одной активной задачи на файл с одними параметрами.
+		// Задачи файла с другими параметрами ждут в очереди, пока выполняется текущая.
+		`CREATE INDEX IF NOT EXISTS idx_analysis_jobs_due ON analysis_jobs(status, run_at)`,
+		`DROP INDEX IF EXISTS idx_analysis_jobs_active_file`,
+		`
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_analysis_jobs_active_request
+		ON analysis_jobs(file_id, algorithm, exclude_quotes, exclude_bibliography, assignment_id, scope, code_language, rerun)
+		WHERE status IN ('queued', 'running')
+		`,
+		`CREATE INDEX IF NOT EXISTS idx_analysis_jobs_file_status ON analysis_jobs(file_id, status)`,
 	}
 
 	for _, indexQuery := range indexQueries {
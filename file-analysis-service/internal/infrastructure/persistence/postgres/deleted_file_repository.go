@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DeletedFileRepository implements the repository.DeletedFileRepository interface with PostgreSQL
+type DeletedFileRepository struct {
+	db *sql.DB
+}
+
+// NewDeletedFileRepository creates a new PostgreSQL deleted file repository
+func NewDeletedFileRepository(db *sql.DB) *DeletedFileRepository {
+	return &DeletedFileRepository{
+		db: db,
+	}
+}
+
+// MarkDeleted records a deleted file, marking it again is not an error
+func (r *DeletedFileRepository) MarkDeleted(ctx context.Context, fileID string, deletedAt time.Time) error {
+	query := `
+		INSERT INTO deleted_files (file_id, deleted_at)
+		VALUES ($1, $2)
+		ON CONFLICT (file_id) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, fileID, deletedAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark file as deleted: %w", err)
+	}
+
+	return nil
+}
+
+// IsDeleted reports whether the file has been deleted
+func (r *DeletedFileRepository) IsDeleted(ctx context.Context, fileID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM deleted_files WHERE file_id = $1)`, fileID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check deleted file: %w", err)
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDeletedFileRepository_MarkDeleted(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec(`CREATE TABLE deleted_files (
+		file_id TEXT PRIMARY KEY,
+		deleted_at DATETIME NOT NULL
+	)`)
+	if err != nil {
+		t.Fatalf("Failed to create test table: %v", err)
+	}
+
+	repo := NewDeletedFileRepository(db)
+	ctx := context.Background()
+
+	deleted, err := repo.IsDeleted(ctx, "file1")
+	if err != nil || deleted {
+		t.Fatalf("IsDeleted() = %v, %v before the file is deleted", deleted, err)
+	}
+
+	// Повторная отметка не является ошибкой
+	for i := 0; i < 2; i++ {
+		if err := repo.MarkDeleted(ctx, "file1", time.Now()); err != nil {
+			t.Fatalf("MarkDeleted() error = %v", err)
+		}
+	}
+
+	deleted, err = repo.IsDeleted(ctx, "file1")
+	if err != nil || !deleted {
+		t.Errorf("IsDeleted() = %v, %v, want the file deleted", deleted, err)
+	}
+
+	deleted, err = repo.IsDeleted(ctx, "file2")
+	if err != nil || deleted {
+		t.Errorf("IsDeleted() = %v, %v, want another file not deleted", deleted, err)
+	}
+}
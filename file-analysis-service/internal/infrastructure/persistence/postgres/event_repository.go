@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// EventRepository implements the repository.EventRepository interface with PostgreSQL
+type EventRepository struct {
+	db *sql.DB
+}
+
+// NewEventRepository creates a new PostgreSQL processed event repository
+func NewEventRepository(db *sql.DB) *EventRepository {
+	return &EventRepository{
+		db: db,
+	}
+}
+
+// IsProcessed reports whether the event has already been handled
+func (r *EventRepository) IsProcessed(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM processed_events WHERE id = $1)`, id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check event: %w", err)
+	}
+
+	return exists, nil
+}
+
+// MarkProcessed records a handled event, marking it again is not an error
+func (r *EventRepository) MarkProcessed(ctx context.Context, id string, eventType string, processedAt time.Time) error {
+	query := `
+		INSERT INTO processed_events (id, type, processed_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, eventType, processedAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark event as processed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEventRepository_MarkProcessed(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec(`CREATE TABLE processed_events (
+		id TEXT PRIMARY KEY,
+		type TEXT NOT NULL,
+		processed_at DATETIME NOT NULL
+	)`)
+	if err != nil {
+		t.Fatalf("Failed to create test table: %v", err)
+	}
+
+	repo := NewEventRepository(db)
+	ctx := context.Background()
+
+	processed, err := repo.IsProcessed(ctx, "event1")
+	if err != nil || processed {
+		t.Fatalf("IsProcessed() = %v, %v before the event is handled", processed, err)
+	}
+
+	// Повторная отметка не является ошибкой
+	for i := 0; i < 2; i++ {
+		if err := repo.MarkProcessed(ctx, "event1", "file.uploaded", time.Now()); err != nil {
+			t.Fatalf("MarkProcessed() error = %v", err)
+		}
+	}
+
+	processed, err = repo.IsProcessed(ctx, "event1")
+	if err != nil || !processed {
+		t.Errorf("IsProcessed() = %v, %v, want the event processed", processed, err)
+	}
+}
@@ -0,0 +1,270 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"fileanalysisservice/internal/domain/job"
+)
+
+// jobColumns lists the columns read by every job query
+const jobColumns = `id, file_id, algorithm, exclude_quotes, exclude_bibliography, assignment_id, scope, code_language, rerun, status, attempts, max_attempts, last_error, analysis_id, run_at, lease_until, finished_at, updated_at, created_at`
+
+// JobRepository implements the repository.JobRepository interface with PostgreSQL
+type JobRepository struct {
+	db *sql.DB
+}
+
+// NewJobRepository creates a new PostgreSQL job repository
+func NewJobRepository(db *sql.DB) *JobRepository {
+	return &JobRepository{
+		db: db,
+	}
+}
+
+// Store saves a new job. It returns false without storing anything if the file already has an active job
+// with the same options.
+func (r *JobRepository) Store(ctx context.Context, job *job.Job) (bool, error) {
+	query := `
+		INSERT INTO analysis_jobs (id, file_id, algorithm, exclude_quotes, exclude_bibliography, assignment_id, scope, code_language, rerun, status, attempts, max_attempts, last_error, analysis_id, run_at, updated_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		ON CONFLICT (file_id, algorithm, exclude_quotes, exclude_bibliography, assignment_id, scope, code_language, rerun)
+		WHERE status IN ('queued', 'running') DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		job.ID,
+		job.FileID,
+		job.Algorithm,
+		job.ExcludeQuotes,
+		job.ExcludeBibliography,
+		job.AssignmentID,
+		job.Scope,
+		job.CodeLanguage,
+		job.Rerun,
+		job.Status,
+		job.Attempts,
+		job.MaxAttempts,
+		job.LastError,
+		job.AnalysisID,
+		job.RunAt,
+		job.UpdatedAt,
+		job.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to store job: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to store job: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// Update saves the mutable state of a job, a cancelled job is left as is
+func (r *JobRepository) Update(ctx context.Context, job *job.Job) error {
+	query := `
+		UPDATE analysis_jobs
+		SET status = $2, attempts = $3, last_error = $4, analysis_id = $5, run_at = $6, lease_until = $7, finished_at = $8, updated_at = $9
+		WHERE id = $1 AND status <> 'cancelled'
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		job.ID,
+		job.Status,
+		job.Attempts,
+		job.LastError,
+		job.AnalysisID,
+		job.RunAt,
+		job.LeaseUntil,
+		job.FinishedAt,
+		job.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+
+	return nil
+}
+
+// scanJob reads a job selected with jobColumns
+func scanJob(row rowScanner) (*job.Job, error) {
+	var j job.Job
+	var runAt, updatedAt, createdAt time.Time
+	var leaseUntil, finishedAt sql.NullTime
+
+	err := row.Scan(
+		&j.ID,
+		&j.FileID,
+		&j.Algorithm,
+		&j.ExcludeQuotes,
+		&j.ExcludeBibliography,
+		&j.AssignmentID,
+		&j.Scope,
+		&j.CodeLanguage,
+		&j.Rerun,
+		&j.Status,
+		&j.Attempts,
+		&j.MaxAttempts,
+		&j.LastError,
+		&j.AnalysisID,
+		&runAt,
+		&leaseUntil,
+		&finishedAt,
+		&updatedAt,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	j.RunAt = runAt
+	j.UpdatedAt = updatedAt
+	j.CreatedAt = createdAt
+	if leaseUntil.Valid {
+		j.LeaseUntil = &leaseUntil.Time
+	}
+	if finishedAt.Valid {
+		j.FinishedAt = &finishedAt.Time
+	}
+
+	return &j, nil
+}
+
+// findBy implements universal find logic.
+func (r *JobRepository) findBy(ctx context.Context, condition string, value any) (*job.Job, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM analysis_jobs
+		WHERE %s
+	`, jobColumns, condition)
+
+	j, err := scanJob(r.db.QueryRowContext(ctx, query, value))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find job: %w", err)
+	}
+
+	return j, nil
+}
+
+func (r *JobRepository) FindByID(ctx context.Context, id string) (*job.Job, error) {
+	return r.findBy(ctx, "id = $1", id)
+}
+
+// FindActive retrieves the queued or running job of the file with the same options as the given job
+func (r *JobRepository) FindActive(ctx context.Context, j *job.Job) (*job.Job, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM analysis_jobs
+		WHERE file_id = $1 AND algorithm = $2 AND exclude_quotes = $3 AND exclude_bibliography = $4
+			AND assignment_id = $5 AND scope = $6 AND code_language = $7 AND rerun = $8
+			AND status IN ('queued', 'running')
+	`, jobColumns)
+
+	active, err := scanJob(r.db.QueryRowContext(ctx, query, j.FileID, j.Algorithm, j.ExcludeQuotes, j.ExcludeBibliography, j.AssignmentID, j.Scope, j.CodeLanguage, j.Rerun))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find job: %w", err)
+	}
+
+	return active, nil
+}
+
+// ClaimNext marks the next due job as running and returns it, nil if there is nothing to do.
+// Running jobs whose lease has expired were abandoned by a stopped worker and are claimed again.
+// Concurrent workers never claim the same job. A file is analysed by one job at a time,
+// its queued jobs wait until the running one finishes or its lease expires: claims of the jobs of a file
+// are serialized by an advisory lock held until the claim is committed.
+func (r *JobRepository) ClaimNext(ctx context.Context, now time.Time) (*job.Job, error) {
+	query := fmt.Sprintf(`
+		UPDATE analysis_jobs
+		SET status = 'running', attempts = attempts + 1, lease_until = $2, updated_at = $1
+		WHERE id = (
+			SELECT id
+			FROM analysis_jobs
+			WHERE ((
+				status = 'queued' AND run_at <= $1 AND NOT EXISTS (
+					SELECT 1
+					FROM analysis_jobs AS running
+					WHERE running.file_id = analysis_jobs.file_id AND running.status = 'running' AND running.lease_until >= $1
+				)
+			) OR (status = 'running' AND lease_until < $1))
+			AND pg_try_advisory_xact_lock(hashtext('analysis_jobs:' || file_id))
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s
+	`, jobColumns)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	j, err := scanJob(tx.QueryRowContext(ctx, query, now, now.Add(job.Lease)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim job: %w", err)
+	}
+
+	// The candidates were read before the lock was taken, a job of the file claimed and committed
+	// in between is only visible to a new statement. The claim is rolled back then.
+	var busy bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM analysis_jobs
+			WHERE file_id = $1 AND id <> $2 AND status = 'running' AND lease_until >= $3
+		)
+	`, j.FileID, j.ID, now).Scan(&busy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check running jobs: %w", err)
+	}
+
+	if busy {
+		return nil, nil
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit job claim: %w", err)
+	}
+
+	return j, nil
+}
+
+// CancelByFileID cancels the queued and running jobs of the file. A running job is not interrupted,
+// but its outcome is no longer saved.
+func (r *JobRepository) CancelByFileID(ctx context.Context, fileID string, reason string, now time.Time) error {
+	query := `
+		UPDATE analysis_jobs
+		SET status = 'cancelled', last_error = $1, lease_until = NULL, finished_at = $2, updated_at = $2
+		WHERE file_id = $3 AND status IN ('queued', 'running')
+	`
+
+	_, err := r.db.ExecContext(ctx, query, reason, now, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to cancel jobs: %w", err)
+	}
+
+	return nil
+}
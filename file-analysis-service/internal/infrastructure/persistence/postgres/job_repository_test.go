@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fileanalysisservice/internal/domain/job"
+)
+
+func TestJobRepository_StoreDeduplicatesSameOptions(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := []string{
+		`CREATE TABLE analysis_jobs (
+			id TEXT PRIMARY KEY,
+			file_id TEXT NOT NULL,
+			algorithm TEXT NOT NULL DEFAULT '',
+			exclude_quotes BOOLEAN NOT NULL DEFAULT FALSE,
+			exclude_bibliography BOOLEAN NOT NULL DEFAULT FALSE,
+			assignment_id TEXT NOT NULL DEFAULT '',
+			scope TEXT NOT NULL DEFAULT 'all',
+			code_language TEXT NOT NULL DEFAULT '',
+			rerun BOOLEAN NOT NULL DEFAULT FALSE,
+			status TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			max_attempts INTEGER NOT NULL,
+			last_error TEXT NOT NULL DEFAULT '',
+			analysis_id TEXT NOT NULL DEFAULT '',
+			run_at DATETIME NOT NULL,
+			lease_until DATETIME,
+			finished_at DATETIME,
+			updated_at DATETIME NOT NULL,
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE UNIQUE INDEX idx_analysis_jobs_active_request
+		ON analysis_jobs(file_id, algorithm, exclude_quotes, exclude_bibliography, assignment_id, scope, code_language, rerun)
+		WHERE status IN ('queued', 'running')`,
+	}
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatalf("Failed to create test table: %v", err)
+		}
+	}
+
+	repo := NewJobRepository(db)
+	ctx := context.Background()
+
+	newJob := func(id string, rerun bool) *job.Job {
+		j, err := job.NewJob("file1", "")
+		if err != nil {
+			t.Fatalf("NewJob() error = %v", err)
+		}
+		j.ID = id
+		j.Rerun = rerun
+		return j
+	}
+
+	stored, err := repo.Store(ctx, newJob("job1", false))
+	if err != nil || !stored {
+		t.Fatalf("Store() = %v, %v, want the first job stored", stored, err)
+	}
+
+	// Задача с теми же параметрами не создается, возвращается активная
+	duplicate := newJob("job2", false)
+	stored, err = repo.Store(ctx, duplicate)
+	if err != nil || stored {
+		t.Fatalf("Store() = %v, %v, want the duplicate skipped", stored, err)
+	}
+
+	active, err := repo.FindActive(ctx, duplicate)
+	if err != nil || active == nil || active.ID != "job1" {
+		t.Errorf("FindActive() = %+v, %v, want job1", active, err)
+	}
+
+	// Повторный анализ того же файла ставится в очередь за активной задачей
+	rerun := newJob("job3", true)
+	stored, err = repo.Store(ctx, rerun)
+	if err != nil || !stored {
+		t.Fatalf("Store() = %v, %v, want the rerun queued", stored, err)
+	}
+
+	active, err = repo.FindActive(ctx, rerun)
+	if err != nil || active == nil || active.ID != "job3" || !active.Rerun {
+		t.Errorf("FindActive() = %+v, %v, want job3", active, err)
+	}
+}
+
+func TestJobRepository_CancelByFileID(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	queries := []string{
+		`CREATE TABLE analysis_jobs (
+			id TEXT PRIMARY KEY,
+			file_id TEXT NOT NULL,
+			algorithm TEXT NOT NULL DEFAULT '',
+			exclude_quotes BOOLEAN NOT NULL DEFAULT FALSE,
+			exclude_bibliography BOOLEAN NOT NULL DEFAULT FALSE,
+			assignment_id TEXT NOT NULL DEFAULT '',
+			scope TEXT NOT NULL DEFAULT 'all',
+			code_language TEXT NOT NULL DEFAULT '',
+			rerun BOOLEAN NOT NULL DEFAULT FALSE,
+			status TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			max_attempts INTEGER NOT NULL,
+			last_error TEXT NOT NULL DEFAULT '',
+			analysis_id TEXT NOT NULL DEFAULT '',
+			run_at DATETIME NOT NULL,
+			lease_until DATETIME,
+			finished_at DATETIME,
+			updated_at DATETIME NOT NULL,
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE UNIQUE INDEX idx_analysis_jobs_active_request
+		ON analysis_jobs(file_id, algorithm, exclude_quotes, exclude_bibliography, assignment_id, scope, code_language, rerun)
+		WHERE status IN ('queued', 'running')`,
+	}
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatalf("Failed to create test table: %v", err)
+		}
+	}
+
+	repo := NewJobRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, id := range []string{"queued", "running", "done", "other"} {
+		fileID := "file1"
+		if id == "other" {
+			fileID = "file2"
+		}
+		j, err := job.NewJob(fileID, "")
+		if err != nil {
+			t.Fatalf("NewJob() error = %v", err)
+		}
+		j.ID = id
+		j.AssignmentID = id
+		if _, err := repo.Store(ctx, j); err != nil {
+			t.Fatalf("Store() error = %v", err)
+		}
+	}
+
+	// Состояния задач выставляются напрямую: SQLite нумерует параметры запроса Update по порядку появления
+	for id, status := range map[string]job.Status{"running": job.StatusRunning, "done": job.StatusDone} {
+		if _, err := db.Exec(`UPDATE analysis_jobs SET status = ? WHERE id = ?`, status, id); err != nil {
+			t.Fatalf("Failed to set job status: %v", err)
+		}
+	}
+
+	if err := repo.CancelByFileID(ctx, "file1", "file has been deleted", now); err != nil {
+		t.Fatalf("CancelByFileID() error = %v", err)
+	}
+
+	want := map[string]job.Status{
+		"queued":  job.StatusCancelled,
+		"running": job.StatusCancelled,
+		"done":    job.StatusDone,
+		"other":   job.StatusQueued,
+	}
+	for id, status := range want {
+		j, err := repo.FindByID(ctx, id)
+		if err != nil || j == nil || j.Status != status {
+			t.Errorf("FindByID(%s) = %+v, %v, want status %s", id, j, err, status)
+		}
+		if status == job.StatusCancelled && (j.LastError != "file has been deleted" || j.FinishedAt == nil) {
+			t.Errorf("FindByID(%s) = %+v, want the cancellation reason and time", id, j)
+		}
+	}
+
+}
@@ -9,6 +9,17 @@ import (
 	"fileanalysisservice/internal/interfaces/repository"
 )
 
+// shingleInsertBatchSize keeps the amount of parameters of an insert far below the PostgreSQL limit of 65535
+const shingleInsertBatchSize = 1000
+
+// submissionNamespace is the namespace of the fingerprints of analysed files
+const submissionNamespace = ""
+
+// templateNamespace returns the namespace of the fingerprints of the assignment templates
+func templateNamespace(assignmentID string) string {
+	return "template:" + assignmentID
+}
+
 // ShingleRepository implements the repository.ShingleRepository interface with PostgreSQL
 type ShingleRepository struct {
 	db *sql.DB
@@ -21,65 +32,97 @@ func NewShingleRepository(db *sql.DB) *ShingleRepository {
 	}
 }
 
-// StoreShingles stores shingles for a file
-func (r *ShingleRepository) StoreShingles(ctx context.Context, fileID string, shingles []repository.ShingleData) error {
+// StoreShingles stores the fingerprints made by the algorithm for a file, replacing the previous ones
+// made by the same algorithm. Fingerprints of other algorithms are kept.
+func (r *ShingleRepository) StoreShingles(ctx context.Context, fileID string, algorithm string, shingles []repository.ShingleData) error {
 	if len(shingles) == 0 {
 		return nil
 	}
 
-	err := r.DeleteShingles(ctx, fileID)
+	return r.replaceNamespace(ctx, submissionNamespace, fileID, algorithm, shingles)
+}
+
+// replaceNamespace replaces the fingerprints made by the algorithm for a file in the namespace.
+// The deletion and the inserts share a transaction, so a failure keeps the previous fingerprints.
+func (r *ShingleRepository) replaceNamespace(ctx context.Context, namespace string, fileID string, algorithm string, shingles []repository.ShingleData) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := `DELETE FROM shingles WHERE file_id = $1 AND namespace = $2 AND algorithm = $3`
+	_, err = tx.ExecContext(ctx, query, fileID, namespace, algorithm)
 	if err != nil {
 		return fmt.Errorf("failed to delete existing shingles: %w", err)
 	}
 
+	for start := 0; start < len(shingles); start += shingleInsertBatchSize {
+		end := min(start+shingleInsertBatchSize, len(shingles))
+
+		err = insertShingles(ctx, tx, namespace, fileID, algorithm, shingles[start:end])
+		if err != nil {
+			return fmt.Errorf("failed to store shingles: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit shingles: %w", err)
+	}
+
+	return nil
+}
+
+// insertShingles inserts a batch of shingles with a single statement
+func insertShingles(ctx context.Context, tx *sql.Tx, namespace string, fileID string, algorithm string, shingles []repository.ShingleData) error {
 	valueStrings := make([]string, 0, len(shingles))
-	valueArgs := make([]interface{}, 0, len(shingles)*5)
+	valueArgs := make([]interface{}, 0, len(shingles)*11)
 
 	for i, shingle := range shingles {
-		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d)",
-			i*5+1, i*5+2, i*5+3, i*5+4, i*5+5))
-		valueArgs = append(valueArgs, fileID, shingle.Hash, shingle.Text, shingle.StartPos, shingle.EndPos)
+		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			i*11+1, i*11+2, i*11+3, i*11+4, i*11+5, i*11+6, i*11+7, i*11+8, i*11+9, i*11+10, i*11+11))
+		valueArgs = append(valueArgs, namespace, fileID, algorithm, shingle.Hash, shingle.Text, shingle.StartPos, shingle.EndPos, shingle.StartRune, shingle.EndRune, shingle.StartLine, shingle.EndLine)
 	}
 
 	query := fmt.Sprintf(`
-		INSERT INTO shingles (file_id, shingle_hash, shingle_text, position_start, position_end)
+		INSERT INTO shingles (namespace, file_id, algorithm, shingle_hash, shingle_text, position_start, position_end, rune_start, rune_end, line_start, line_end)
 		VALUES %s
 	`, strings.Join(valueStrings, ","))
 
-	_, err = r.db.ExecContext(ctx, query, valueArgs...)
-	if err != nil {
-		return fmt.Errorf("failed to store shingles: %w", err)
-	}
-
-	return nil
+	_, err := tx.ExecContext(ctx, query, valueArgs...)
+	return err
 }
 
-// FindMatchingShingles finds shingles that match the given hashes
-func (r *ShingleRepository) FindMatchingShingles(ctx context.Context, hashes []string, excludeFileID string) ([]repository.ShingleMatch, error) {
-	if len(hashes) == 0 {
+// FindShinglesByFileIDs retrieves the fingerprints made by the algorithm of the given files.
+// It is called with LSH candidates only, so the amount of files is small and bounded.
+func (r *ShingleRepository) FindShinglesByFileIDs(ctx context.Context, algorithm string, fileIDs []string) ([]repository.ShingleMatch, error) {
+	if len(fileIDs) == 0 {
 		return []repository.ShingleMatch{}, nil
 	}
 
 	// Create placeholders for the IN clause
-	placeholders := make([]string, len(hashes))
-	args := make([]interface{}, len(hashes)+1)
+	placeholders := make([]string, len(fileIDs))
+	args := make([]interface{}, len(fileIDs)+2)
 
-	for i, hash := range hashes {
+	for i, fileID := range fileIDs {
 		placeholders[i] = fmt.Sprintf("$%d", i+1)
-		args[i] = hash
+		args[i] = fileID
 	}
-	args[len(hashes)] = excludeFileID
+	args[len(fileIDs)] = algorithm
+	args[len(fileIDs)+1] = submissionNamespace
 
 	query := fmt.Sprintf(`
-		SELECT file_id, shingle_hash, shingle_text, position_start, position_end
+		SELECT file_id, shingle_hash, shingle_text, position_start, position_end, rune_start, rune_end, line_start, line_end
 		FROM shingles
-		WHERE shingle_hash IN (%s) AND file_id != $%d
+		WHERE file_id IN (%s) AND algorithm = $%d AND namespace = $%d
 		ORDER BY file_id, position_start
-	`, strings.Join(placeholders, ","), len(hashes)+1)
+	`, strings.Join(placeholders, ","), len(fileIDs)+1, len(fileIDs)+2)
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query matching shingles: %w", err)
+		return nil, fmt.Errorf("failed to query shingles: %w", err)
 	}
 	defer rows.Close()
 
@@ -92,21 +135,25 @@ func (r *ShingleRepository) FindMatchingShingles(ctx context.Context, hashes []s
 			&match.ShingleText,
 			&match.StartPos,
 			&match.EndPos,
+			&match.StartRune,
+			&match.EndRune,
+			&match.StartLine,
+			&match.EndLine,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan shingle match: %w", err)
+			return nil, fmt.Errorf("failed to scan shingle: %w", err)
 		}
 		matches = append(matches, match)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating shingle matches: %w", err)
+		return nil, fmt.Errorf("error iterating shingles: %w", err)
 	}
 
 	return matches, nil
 }
 
-// DeleteShingles removes all shingles for a file
+// DeleteShingles removes all shingles for a file, including the ones stored for it as a template
 func (r *ShingleRepository) DeleteShingles(ctx context.Context, fileID string) error {
 	query := `DELETE FROM shingles WHERE file_id = $1`
 
@@ -117,3 +164,68 @@ func (r *ShingleRepository) DeleteShingles(ctx context.Context, fileID string) e
 
 	return nil
 }
+
+// StoreTemplateShingles stores the fingerprints made by the algorithm for a template file of the assignment,
+// replacing the previous ones made by the same algorithm
+func (r *ShingleRepository) StoreTemplateShingles(ctx context.Context, assignmentID string, fileID string, algorithm string, shingles []repository.ShingleData) error {
+	return r.replaceNamespace(ctx, templateNamespace(assignmentID), fileID, algorithm, shingles)
+}
+
+// FindTemplateHashes retrieves the distinct fingerprint hashes made by the algorithm of all templates of the assignment
+func (r *ShingleRepository) FindTemplateHashes(ctx context.Context, assignmentID string, algorithm string) ([]string, error) {
+	query := `
+		SELECT DISTINCT shingle_hash
+		FROM shingles
+		WHERE namespace = $1 AND algorithm = $2
+	`
+
+	return r.queryStrings(ctx, query, templateNamespace(assignmentID), algorithm)
+}
+
+// FindTemplateFileIDs retrieves the files registered as templates of the assignment
+func (r *ShingleRepository) FindTemplateFileIDs(ctx context.Context, assignmentID string) ([]string, error) {
+	query := `
+		SELECT DISTINCT file_id
+		FROM shingles
+		WHERE namespace = $1
+		ORDER BY file_id
+	`
+
+	return r.queryStrings(ctx, query, templateNamespace(assignmentID))
+}
+
+// DeleteTemplateShingles removes the fingerprints of a template file of the assignment
+func (r *ShingleRepository) DeleteTemplateShingles(ctx context.Context, assignmentID string, fileID string) error {
+	query := `DELETE FROM shingles WHERE file_id = $1 AND namespace = $2`
+
+	_, err := r.db.ExecContext(ctx, query, fileID, templateNamespace(assignmentID))
+	if err != nil {
+		return fmt.Errorf("failed to delete template shingles: %w", err)
+	}
+
+	return nil
+}
+
+// queryStrings runs a query selecting a single text column
+func (r *ShingleRepository) queryStrings(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query template shingles: %w", err)
+	}
+	defer rows.Close()
+
+	values := []string{}
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to scan template shingle: %w", err)
+		}
+		values = append(values, value)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating template shingles: %w", err)
+	}
+
+	return values, nil
+}
@@ -7,11 +7,26 @@ import (
 
 	"fileanalysisservice/internal/interfaces/repository"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
+// testDriver is SQLite with no-op stand-ins for the PostgreSQL functions used by the repositories
+const testDriver = "sqlite3_postgres"
+
+func init() {
+	sql.Register(testDriver, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			err := conn.RegisterFunc("hashtext", func(string) int64 { return 0 }, true)
+			if err != nil {
+				return err
+			}
+			return conn.RegisterFunc("pg_advisory_xact_lock", func(int64) int64 { return 0 }, false)
+		},
+	})
+}
+
 func setupTestDB(t *testing.T) *sql.DB {
-	db, err := sql.Open("sqlite3", ":memory:")
+	db, err := sql.Open(testDriver, ":memory:")
 	if err != nil {
 		t.Fatalf("Failed to open test database: %v", err)
 	}
@@ -20,10 +35,16 @@ func setupTestDB(t *testing.T) *sql.DB {
 		CREATE TABLE shingles (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			file_id TEXT NOT NULL,
+			algorithm TEXT NOT NULL DEFAULT 'shingles:4',
 			shingle_hash TEXT NOT NULL,
 			shingle_text TEXT NOT NULL,
 			position_start INTEGER NOT NULL,
 			position_end INTEGER NOT NULL,
+			rune_start INTEGER NOT NULL DEFAULT 0,
+			rune_end INTEGER NOT NULL DEFAULT 0,
+			line_start INTEGER NOT NULL DEFAULT 0,
+			line_end INTEGER NOT NULL DEFAULT 0,
+			namespace TEXT NOT NULL DEFAULT '',
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)
 	`
@@ -62,14 +83,16 @@ func TestShingleRepository_StoreShingles(t *testing.T) {
 			EndPos:   20,
 		},
 		{
-			Hash:     "hash2",
-			Text:     "второй тестовый шингл",
-			StartPos: 15,
-			EndPos:   35,
+			Hash:      "hash2",
+			Text:      "второй тестовый шингл",
+			StartPos:  15,
+			EndPos:    35,
+			StartLine: 2,
+			EndLine:   4,
 		},
 	}
 
-	err := repo.StoreShingles(ctx, "file1", shingles)
+	err := repo.StoreShingles(ctx, "file1", "shingles:4", shingles)
 	if err != nil {
 		t.Errorf("StoreShingles() error = %v", err)
 	}
@@ -83,9 +106,17 @@ func TestShingleRepository_StoreShingles(t *testing.T) {
 	if count != 2 {
 		t.Errorf("Expected 2 shingles, got %d", count)
 	}
+
+	matches, err := repo.FindShinglesByFileIDs(ctx, "shingles:4", []string{"file1"})
+	if err != nil {
+		t.Fatalf("FindShinglesByFileIDs() error = %v", err)
+	}
+	if len(matches) != 2 || matches[1].StartLine != 2 || matches[1].EndLine != 4 {
+		t.Errorf("Stored shingles = %+v, want the second one on lines 2-4", matches)
+	}
 }
 
-func TestShingleRepository_FindMatchingShingles(t *testing.T) {
+func TestShingleRepository_FindShinglesByFileIDs(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
 
@@ -101,6 +132,7 @@ func TestShingleRepository_FindMatchingShingles(t *testing.T) {
 		{"file1", "hash2", "второй шингл"},
 		{"file2", "hash1", "совпавший шингл"},
 		{"file2", "hash3", "уникальный шингл"},
+		{"file3", "hash1", "шингл исключенного файла"},
 	}
 
 	for _, data := range testData {
@@ -113,21 +145,31 @@ func TestShingleRepository_FindMatchingShingles(t *testing.T) {
 		}
 	}
 
-	hashes := []string{"hash1", "hash2", "hash4"}
-	matches, err := repo.FindMatchingShingles(ctx, hashes, "file3")
+	_, err := db.Exec(
+		"INSERT INTO shingles (file_id, algorithm, shingle_hash, shingle_text, position_start, position_end) VALUES (?, ?, ?, ?, ?, ?)",
+		"file1", "winnowing:25:20", "hash5", "отпечаток другого алгоритма", 0, 25,
+	)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	matches, err := repo.FindShinglesByFileIDs(ctx, "shingles:4", []string{"file1", "file2", "file4"})
 
 	if err != nil {
-		t.Errorf("FindMatchingShingles() error = %v", err)
+		t.Errorf("FindShinglesByFileIDs() error = %v", err)
 	}
 
-	expectedMatches := 3
+	expectedMatches := 4
 	if len(matches) != expectedMatches {
-		t.Errorf("Expected %d matches, got %d", expectedMatches, len(matches))
+		t.Errorf("Expected %d shingles, got %d", expectedMatches, len(matches))
 	}
 
 	for _, match := range matches {
 		if match.FileID == "file3" {
-			t.Error("Found match from excluded file")
+			t.Error("Found shingle from a file that wasn't requested")
+		}
+		if match.ShingleHash == "hash5" {
+			t.Error("Found fingerprint of another algorithm")
 		}
 	}
 }
@@ -173,7 +215,7 @@ func TestShingleRepository_StoreShingles_ReplaceExisting(t *testing.T) {
 	shingles1 := []repository.ShingleData{
 		{Hash: "hash1", Text: "первый шингл", StartPos: 0, EndPos: 15},
 	}
-	err := repo.StoreShingles(ctx, "file1", shingles1)
+	err := repo.StoreShingles(ctx, "file1", "shingles:4", shingles1)
 	if err != nil {
 		t.Errorf("First StoreShingles() error = %v", err)
 	}
@@ -182,7 +224,7 @@ func TestShingleRepository_StoreShingles_ReplaceExisting(t *testing.T) {
 		{Hash: "hash2", Text: "новый шингл", StartPos: 0, EndPos: 12},
 		{Hash: "hash3", Text: "еще один шингл", StartPos: 10, EndPos: 25},
 	}
-	err = repo.StoreShingles(ctx, "file1", shingles2)
+	err = repo.StoreShingles(ctx, "file1", "shingles:4", shingles2)
 	if err != nil {
 		t.Errorf("Second StoreShingles() error = %v", err)
 	}
@@ -208,25 +250,51 @@ func TestShingleRepository_StoreShingles_ReplaceExisting(t *testing.T) {
 	}
 }
 
+func TestShingleRepository_StoreShingles_OtherAlgorithms(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewShingleRepository(db)
+	ctx := context.Background()
+
+	err := repo.StoreShingles(ctx, "file1", "shingles:4", []repository.ShingleData{{Hash: "hash1", Text: "шингл"}})
+	if err != nil {
+		t.Fatalf("StoreShingles() error = %v", err)
+	}
+
+	// Отпечатки другого алгоритма не заменяют шинглы, файл остается кандидатом для обоих алгоритмов
+	err = repo.StoreShingles(ctx, "file1", "winnowing:5:4", []repository.ShingleData{{Hash: "hash2", Text: "грамма"}})
+	if err != nil {
+		t.Fatalf("StoreShingles() error = %v", err)
+	}
+
+	for _, algorithm := range []string{"shingles:4", "winnowing:5:4"} {
+		matches, err := repo.FindShinglesByFileIDs(ctx, algorithm, []string{"file1"})
+		if err != nil || len(matches) != 1 {
+			t.Errorf("FindShinglesByFileIDs(%q) = %d matches, %v, want 1", algorithm, len(matches), err)
+		}
+	}
+}
+
 func TestShingleRepository_StoreShingles_EmptySlice(t *testing.T) {
 	// Тест проверяет, что пустой слайс шинглов не вызывает ошибок
 	repo := &ShingleRepository{db: nil} // db не используется для пустого слайса
 	ctx := context.Background()
 
-	err := repo.StoreShingles(ctx, "file1", []repository.ShingleData{})
+	err := repo.StoreShingles(ctx, "file1", "shingles:4", []repository.ShingleData{})
 	if err != nil {
 		t.Errorf("StoreShingles() with empty slice should not error, got: %v", err)
 	}
 }
 
-func TestShingleRepository_FindMatchingShingles_EmptyHashes(t *testing.T) {
-	// Тест проверяет, что пустой слайс хешей возвращает пустой результат
+func TestShingleRepository_FindShinglesByFileIDs_EmptyFileIDs(t *testing.T) {
+	// Тест проверяет, что пустой слайс файлов возвращает пустой результат
 	repo := &ShingleRepository{db: nil} // db не используется для пустого слайса
 	ctx := context.Background()
 
-	matches, err := repo.FindMatchingShingles(ctx, []string{}, "file1")
+	matches, err := repo.FindShinglesByFileIDs(ctx, "shingles:4", []string{})
 	if err != nil {
-		t.Errorf("FindMatchingShingles() with empty hashes should not error, got: %v", err)
+		t.Errorf("FindShinglesByFileIDs() with empty file IDs should not error, got: %v", err)
 	}
 
 	if len(matches) != 0 {
@@ -234,6 +302,35 @@ func TestShingleRepository_FindMatchingShingles_EmptyHashes(t *testing.T) {
 	}
 }
 
+func TestShingleRepository_StoreShingles_Batches(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewShingleRepository(db)
+	ctx := context.Background()
+
+	// Больше одного пакета вставки
+	shingles := make([]repository.ShingleData, shingleInsertBatchSize*2+10)
+	for i := range shingles {
+		shingles[i] = repository.ShingleData{Hash: "hash", Text: "шингл", StartPos: i, EndPos: i + 5}
+	}
+
+	err := repo.StoreShingles(ctx, "file1", "shingles:4", shingles)
+	if err != nil {
+		t.Fatalf("StoreShingles() error = %v", err)
+	}
+
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM shingles WHERE file_id = ?", "file1").Scan(&count)
+	if err != nil {
+		t.Errorf("Failed to count shingles: %v", err)
+	}
+
+	if count != len(shingles) {
+		t.Errorf("Expected %d shingles, got %d", len(shingles), count)
+	}
+}
+
 func TestShingleRepository_ValidateShingleData(t *testing.T) {
 	// Тест проверяет валидацию данных шинглов
 	testCases := []struct {
@@ -294,3 +391,82 @@ func validateShingleData(shingles []repository.ShingleData) bool {
 	}
 	return true
 }
+
+func TestShingleRepository_TemplateShingles(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewShingleRepository(db)
+	ctx := context.Background()
+
+	shingles := []repository.ShingleData{
+		{Hash: "hash1", Text: "текст задания", StartPos: 0, EndPos: 20},
+		{Hash: "hash2", Text: "еще текст задания", StartPos: 10, EndPos: 30},
+	}
+
+	// The same file is both a template and an analysed submission
+	err := repo.StoreShingles(ctx, "file1", "shingles:4", shingles[:1])
+	if err != nil {
+		t.Fatalf("StoreShingles() error = %v", err)
+	}
+
+	for _, assignmentID := range []string{"hw1", "hw2"} {
+		err = repo.StoreTemplateShingles(ctx, assignmentID, "file1", "shingles:4", shingles)
+		if err != nil {
+			t.Fatalf("StoreTemplateShingles() error = %v", err)
+		}
+	}
+	err = repo.StoreTemplateShingles(ctx, "hw1", "file2", "winnowing:25:20", shingles[1:])
+	if err != nil {
+		t.Fatalf("StoreTemplateShingles() error = %v", err)
+	}
+
+	// Template fingerprints are not fingerprints of the analysed file
+	matches, err := repo.FindShinglesByFileIDs(ctx, "shingles:4", []string{"file1"})
+	if err != nil {
+		t.Fatalf("FindShinglesByFileIDs() error = %v", err)
+	}
+	if len(matches) != 1 {
+		t.Errorf("Expected 1 submission shingle, got %d", len(matches))
+	}
+
+	hashes, err := repo.FindTemplateHashes(ctx, "hw1", "shingles:4")
+	if err != nil {
+		t.Fatalf("FindTemplateHashes() error = %v", err)
+	}
+	if len(hashes) != 2 {
+		t.Errorf("Expected 2 template hashes, got %v", hashes)
+	}
+
+	fileIDs, err := repo.FindTemplateFileIDs(ctx, "hw1")
+	if err != nil {
+		t.Fatalf("FindTemplateFileIDs() error = %v", err)
+	}
+	if len(fileIDs) != 2 || fileIDs[0] != "file1" || fileIDs[1] != "file2" {
+		t.Errorf("Expected templates [file1 file2], got %v", fileIDs)
+	}
+
+	// Analysing the file again keeps its template fingerprints
+	err = repo.StoreShingles(ctx, "file1", "shingles:4", shingles)
+	if err != nil {
+		t.Fatalf("StoreShingles() error = %v", err)
+	}
+
+	err = repo.DeleteTemplateShingles(ctx, "hw1", "file1")
+	if err != nil {
+		t.Fatalf("DeleteTemplateShingles() error = %v", err)
+	}
+
+	hashes, _ = repo.FindTemplateHashes(ctx, "hw1", "shingles:4")
+	if len(hashes) != 0 {
+		t.Errorf("Expected no template hashes after deletion, got %v", hashes)
+	}
+	hashes, _ = repo.FindTemplateHashes(ctx, "hw2", "shingles:4")
+	if len(hashes) != 2 {
+		t.Errorf("Expected templates of another assignment to stay, got %v", hashes)
+	}
+	matches, _ = repo.FindShinglesByFileIDs(ctx, "shingles:4", []string{"file1"})
+	if len(matches) != 2 {
+		t.Errorf("Expected submission shingles to stay, got %d", len(matches))
+	}
+}
@@ -0,0 +1,240 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"fileanalysisservice/internal/interfaces/repository"
+)
+
+// SignatureRepository implements the repository.SignatureRepository interface with PostgreSQL
+type SignatureRepository struct {
+	db *sql.DB
+}
+
+// NewSignatureRepository creates a new PostgreSQL MinHash signature repository
+func NewSignatureRepository(db *sql.DB) *SignatureRepository {
+	return &SignatureRepository{
+		db: db,
+	}
+}
+
+// StoreSignature stores the MinHash signature of a file and its LSH bands, replacing previous ones
+func (r *SignatureRepository) StoreSignature(ctx context.Context, fileID string, signature []uint64, bands []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM lsh_bands WHERE file_id = $1`, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to delete existing bands: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO minhash_signatures (file_id, signature)
+		VALUES ($1, $2)
+		ON CONFLICT (file_id) DO UPDATE SET signature = EXCLUDED.signature
+	`, fileID, encodeSignature(signature))
+	if err != nil {
+		return fmt.Errorf("failed to store signature: %w", err)
+	}
+
+	if len(bands) > 0 {
+		valueStrings := make([]string, 0, len(bands))
+		valueArgs := make([]interface{}, 0, len(bands)*2)
+
+		for i, band := range bands {
+			valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d)", i*2+1, i*2+2))
+			valueArgs = append(valueArgs, band, fileID)
+		}
+
+		query := fmt.Sprintf(`
+			INSERT INTO lsh_bands (band_key, file_id)
+			VALUES %s
+			ON CONFLICT DO NOTHING
+		`, strings.Join(valueStrings, ","))
+
+		_, err = tx.ExecContext(ctx, query, valueArgs...)
+		if err != nil {
+			return fmt.Errorf("failed to store bands: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit signature: %w", err)
+	}
+
+	return nil
+}
+
+// FindCandidates finds files sharing LSH bands with the given ones, the most similar first.
+// The amount of bands per signature is fixed, so the query size doesn't depend on the document length.
+// Non-nil sourceFileIDs restrict the search to those files.
+func (r *SignatureRepository) FindCandidates(ctx context.Context, bands []string, excludeFileID string, limit int, sourceFileIDs []string) ([]repository.LSHCandidate, error) {
+	if len(bands) == 0 || (sourceFileIDs != nil && len(sourceFileIDs) == 0) {
+		return []repository.LSHCandidate{}, nil
+	}
+
+	placeholders := make([]string, len(bands))
+	args := make([]interface{}, 0, len(bands)+len(sourceFileIDs)+2)
+
+	for i, band := range bands {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args = append(args, band)
+	}
+	args = append(args, excludeFileID)
+
+	sourceFilter := ""
+	if sourceFileIDs != nil {
+		sourcePlaceholders := make([]string, len(sourceFileIDs))
+		for i, fileID := range sourceFileIDs {
+			sourcePlaceholders[i] = fmt.Sprintf("$%d", len(args)+1)
+			args = append(args, fileID)
+		}
+		sourceFilter = fmt.Sprintf(" AND file_id IN (%s)", strings.Join(sourcePlaceholders, ","))
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		SELECT file_id, COUNT(*) AS shared_bands
+		FROM lsh_bands
+		WHERE band_key IN (%s) AND file_id != $%d%s
+		GROUP BY file_id
+		ORDER BY shared_bands DESC, file_id
+		LIMIT $%d
+	`, strings.Join(placeholders, ","), len(bands)+1, sourceFilter, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query candidates: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := []repository.LSHCandidate{}
+	for rows.Next() {
+		var candidate repository.LSHCandidate
+		if err := rows.Scan(&candidate.FileID, &candidate.SharedBands); err != nil {
+			return nil, fmt.Errorf("failed to scan candidate: %w", err)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating candidates: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// FindSignatures retrieves the signatures of the given files
+func (r *SignatureRepository) FindSignatures(ctx context.Context, fileIDs []string) (map[string][]uint64, error) {
+	signatures := make(map[string][]uint64, len(fileIDs))
+	if len(fileIDs) == 0 {
+		return signatures, nil
+	}
+
+	placeholders := make([]string, len(fileIDs))
+	args := make([]interface{}, len(fileIDs))
+
+	for i, fileID := range fileIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = fileID
+	}
+
+	query := fmt.Sprintf(`
+		SELECT file_id, signature
+		FROM minhash_signatures
+		WHERE file_id IN (%s)
+	`, strings.Join(placeholders, ","))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query signatures: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var fileID string
+		var data []byte
+		if err := rows.Scan(&fileID, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan signature: %w", err)
+		}
+		signatures[fileID] = decodeSignature(data)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating signatures: %w", err)
+	}
+
+	return signatures, nil
+}
+
+// FindUnsignedFileIDs finds analysed files that have shingles but no signature yet (analysed before signatures existed)
+func (r *SignatureRepository) FindUnsignedFileIDs(ctx context.Context, limit int) ([]string, error) {
+	query := `
+		SELECT DISTINCT s.file_id
+		FROM shingles s
+		WHERE s.namespace = '' AND NOT EXISTS (SELECT 1 FROM minhash_signatures m WHERE m.file_id = s.file_id)
+		LIMIT $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unsigned files: %w", err)
+	}
+	defer rows.Close()
+
+	var fileIDs []string
+	for rows.Next() {
+		var fileID string
+		if err := rows.Scan(&fileID); err != nil {
+			return nil, fmt.Errorf("failed to scan file ID: %w", err)
+		}
+		fileIDs = append(fileIDs, fileID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating unsigned files: %w", err)
+	}
+
+	return fileIDs, nil
+}
+
+// DeleteSignature removes the signature and LSH bands of a file
+func (r *SignatureRepository) DeleteSignature(ctx context.Context, fileID string) error {
+	for _, query := range []string{
+		`DELETE FROM lsh_bands WHERE file_id = $1`,
+		`DELETE FROM minhash_signatures WHERE file_id = $1`,
+	} {
+		if _, err := r.db.ExecContext(ctx, query, fileID); err != nil {
+			return fmt.Errorf("failed to delete signature: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// encodeSignature packs signature values into bytes
+func encodeSignature(signature []uint64) []byte {
+	data := make([]byte, len(signature)*8)
+	for i, value := range signature {
+		binary.BigEndian.PutUint64(data[i*8:], value)
+	}
+	return data
+}
+
+// decodeSignature unpacks signature values from bytes
+func decodeSignature(data []byte) []uint64 {
+	signature := make([]uint64, len(data)/8)
+	for i := range signature {
+		signature[i] = binary.BigEndian.Uint64(data[i*8:])
+	}
+	return signature
+}
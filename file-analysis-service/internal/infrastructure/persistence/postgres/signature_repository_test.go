@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func setupSignatureTestDB(t *testing.T) *sql.DB {
+	db := setupTestDB(t)
+
+	queries := []string{
+		`CREATE TABLE minhash_signatures (
+			file_id TEXT PRIMARY KEY,
+			signature BLOB NOT NULL
+		)`,
+		`CREATE TABLE lsh_bands (
+			band_key TEXT NOT NULL,
+			file_id TEXT NOT NULL,
+			PRIMARY KEY (band_key, file_id)
+		)`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatalf("Failed to create test table: %v", err)
+		}
+	}
+
+	return db
+}
+
+func TestSignatureRepository_StoreAndFindSignatures(t *testing.T) {
+	db := setupSignatureTestDB(t)
+	defer db.Close()
+
+	repo := NewSignatureRepository(db)
+	ctx := context.Background()
+
+	err := repo.StoreSignature(ctx, "file1", []uint64{1, 2, 1 << 63}, []string{"00:a", "01:b"})
+	if err != nil {
+		t.Fatalf("StoreSignature() error = %v", err)
+	}
+
+	// Повторное сохранение заменяет сигнатуру и полосы
+	err = repo.StoreSignature(ctx, "file1", []uint64{3, 4, 5}, []string{"00:c"})
+	if err != nil {
+		t.Fatalf("StoreSignature() error = %v", err)
+	}
+
+	signatures, err := repo.FindSignatures(ctx, []string{"file1", "file2"})
+	if err != nil {
+		t.Fatalf("FindSignatures() error = %v", err)
+	}
+
+	signature := signatures["file1"]
+	if len(signature) != 3 || signature[0] != 3 || signature[2] != 5 {
+		t.Errorf("FindSignatures() = %v, want [3 4 5]", signature)
+	}
+
+	if _, ok := signatures["file2"]; ok {
+		t.Error("Found signature of a file that wasn't stored")
+	}
+
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM lsh_bands WHERE file_id = ?", "file1").Scan(&count)
+	if err != nil {
+		t.Errorf("Failed to count bands: %v", err)
+	}
+
+	if count != 1 {
+		t.Errorf("Expected 1 band after replacement, got %d", count)
+	}
+}
+
+func TestSignatureRepository_FindCandidates(t *testing.T) {
+	db := setupSignatureTestDB(t)
+	defer db.Close()
+
+	repo := NewSignatureRepository(db)
+	ctx := context.Background()
+
+	stored := map[string][]string{
+		"file1": {"00:a", "01:b", "02:c"},
+		"file2": {"00:a", "01:x", "02:c"},
+		"file3": {"00:a", "01:y", "02:z"},
+		"file4": {"00:q", "01:w", "02:e"},
+	}
+	for fileID, bands := range stored {
+		if err := repo.StoreSignature(ctx, fileID, []uint64{1}, bands); err != nil {
+			t.Fatalf("StoreSignature() error = %v", err)
+		}
+	}
+
+	candidates, err := repo.FindCandidates(ctx, stored["file1"], "file1", 10, nil)
+	if err != nil {
+		t.Fatalf("FindCandidates() error = %v", err)
+	}
+
+	if len(candidates) != 2 {
+		t.Fatalf("Expected 2 candidates, got %d", len(candidates))
+	}
+
+	if candidates[0].FileID != "file2" || candidates[0].SharedBands != 2 {
+		t.Errorf("First candidate = %+v, want file2 with 2 shared bands", candidates[0])
+	}
+
+	if candidates[1].FileID != "file3" || candidates[1].SharedBands != 1 {
+		t.Errorf("Second candidate = %+v, want file3 with 1 shared band", candidates[1])
+	}
+
+	candidates, err = repo.FindCandidates(ctx, stored["file1"], "file1", 10, []string{"file3", "file4"})
+	if err != nil {
+		t.Fatalf("FindCandidates() error = %v", err)
+	}
+
+	if len(candidates) != 1 || candidates[0].FileID != "file3" {
+		t.Errorf("Candidates within sources = %+v, want only file3", candidates)
+	}
+
+	candidates, err = repo.FindCandidates(ctx, stored["file1"], "file1", 10, []string{})
+	if err != nil {
+		t.Fatalf("FindCandidates() error = %v", err)
+	}
+
+	if len(candidates) != 0 {
+		t.Errorf("Candidates without sources = %+v, want none", candidates)
+	}
+}
+
+func TestSignatureRepository_FindUnsignedFileIDs(t *testing.T) {
+	db := setupSignatureTestDB(t)
+	defer db.Close()
+
+	repo := NewSignatureRepository(db)
+	ctx := context.Background()
+
+	for _, fileID := range []string{"file1", "file2"} {
+		_, err := db.Exec(
+			"INSERT INTO shingles (file_id, shingle_hash, shingle_text, position_start, position_end) VALUES (?, ?, ?, ?, ?)",
+			fileID, "hash1", "тестовый шингл", 0, 20,
+		)
+		if err != nil {
+			t.Fatalf("Failed to insert test data: %v", err)
+		}
+	}
+
+	if err := repo.StoreSignature(ctx, "file1", []uint64{1}, []string{"00:a"}); err != nil {
+		t.Fatalf("StoreSignature() error = %v", err)
+	}
+
+	fileIDs, err := repo.FindUnsignedFileIDs(ctx, 10)
+	if err != nil {
+		t.Fatalf("FindUnsignedFileIDs() error = %v", err)
+	}
+
+	if len(fileIDs) != 1 || fileIDs[0] != "file2" {
+		t.Errorf("FindUnsignedFileIDs() = %v, want [file2]", fileIDs)
+	}
+
+	if err := repo.DeleteSignature(ctx, "file1"); err != nil {
+		t.Fatalf("DeleteSignature() error = %v", err)
+	}
+
+	fileIDs, err = repo.FindUnsignedFileIDs(ctx, 10)
+	if err != nil {
+		t.Fatalf("FindUnsignedFileIDs() error = %v", err)
+	}
+
+	if len(fileIDs) != 2 {
+		t.Errorf("FindUnsignedFileIDs() = %v, want both files after deletion", fileIDs)
+	}
+}
@@ -1,15 +1,16 @@
 package quickchart
 
 import (
+	"bytes"
+	"encoding/json"
 	"fileanalysisservice/internal/infrastructure/config"
+	"fileanalysisservice/internal/interfaces/renderer"
 	"fmt"
-	"github.com/google/uuid"
 	"io"
 	"net/http"
-	"net/url"
-	"os"
 )
 
+// QuickChart renders word clouds with the quickchart.io API, the whole document is sent to it
 type QuickChart struct {
 	basePath string
 }
@@ -20,13 +21,20 @@ func NewQuickChart(cfg *config.Config) *QuickChart {
 	}
 }
 
-func (qc *QuickChart) WordCloud(content string) (string, error) {
-	encodedContent := url.QueryEscape(content)
-	apiURL := qc.basePath + fmt.Sprintf("?text=%s&format=png", encodedContent)
+// WordCloud renders a PNG word cloud, QuickChart doesn't produce SVG.
+// The text is sent in a POST body, so long documents don't hit URL length limits.
+func (qc *QuickChart) WordCloud(content string) (*renderer.WordCloud, error) {
+	body, err := json.Marshal(map[string]string{
+		"text":   content,
+		"format": "png",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode word cloud request: %w", err)
+	}
 
-	resp, err := http.Get(apiURL)
+	resp, err := http.Post(qc.basePath, "application/json", bytes.NewReader(body))
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch word cloud: %w", err)
+		return nil, fmt.Errorf("failed to fetch word cloud: %w", err)
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -36,32 +44,13 @@ func (qc *QuickChart) WordCloud(content string) (string, error) {
 	}(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to fetch word cloud: status code %d", resp.StatusCode)
-	}
-
-	path := fmt.Sprintf("wordcloud_%s.png", uuid.NewString())
-
-	file, err := os.Create(path)
-	if err != nil {
-		return "", fmt.Errorf("failed to create file: %w", err)
-	}
-
-	defer func(file *os.File) {
-		err := file.Close()
-		if err != nil {
-			return
-		}
-	}(file)
-
-	_, err = io.Copy(file, resp.Body)
-	if err != nil {
-		return path, fmt.Errorf("failed to save word cloud image: %w", err)
+		return nil, fmt.Errorf("failed to fetch word cloud: status code %d", resp.StatusCode)
 	}
 
-	_, err = file.Seek(0, 0)
+	image, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to seek file: %w", err)
+		return nil, fmt.Errorf("failed to read word cloud image: %w", err)
 	}
 
-	return path, nil
+	return &renderer.WordCloud{PNG: image}, nil
 }
@@ -2,18 +2,21 @@ package s3
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/google/uuid"
 	"io"
-	"os"
 
 	"fileanalysisservice/internal/infrastructure/config"
 )
 
+var ErrNotFound = errors.New("file not found in storage")
+
 // FileStorage handles file operations with S3.
 type FileStorage struct {
 	client   *s3.S3
@@ -49,10 +52,13 @@ type UploadedFileInfo struct {
 	Location string
 }
 
-// Upload uploads a file to S3 and returns the uploaded file information.
-func (s *FileStorage) Upload(_ context.Context, fileData *os.File) (*UploadedFileInfo, error) {
-	fileKey := uuid.New().String()
+// Upload uploads a file to S3 under a new key and returns the uploaded file information.
+func (s *FileStorage) Upload(ctx context.Context, fileData io.ReadSeeker) (*UploadedFileInfo, error) {
+	return s.UploadWithKey(ctx, uuid.New().String(), fileData)
+}
 
+// UploadWithKey uploads a file to S3 under the given key, an existing file is replaced.
+func (s *FileStorage) UploadWithKey(_ context.Context, fileKey string, fileData io.ReadSeeker) (*UploadedFileInfo, error) {
 	_, err := s.client.PutObject(&s3.PutObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(fileKey),
@@ -76,8 +82,25 @@ func (s *FileStorage) Download(ctx context.Context, fileKey string) (io.ReadClos
 		Key:    aws.String(fileKey),
 	})
 	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, ErrNotFound
+		}
 		return nil, fmt.Errorf("failed to download file from S3: %w", err)
 	}
 
 	return result.Body, nil
 }
+
+// Delete removes a file from S3, deleting a missing file is not an error.
+func (s *FileStorage) Delete(ctx context.Context, fileKey string) error {
+	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(fileKey),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete file from S3: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package wordcloud
+
+import (
+	"fmt"
+
+	"fileanalysisservice/internal/infrastructure/config"
+	"fileanalysisservice/internal/infrastructure/quickchart"
+	"fileanalysisservice/internal/interfaces/renderer"
+)
+
+const (
+	BackendLocal      = "local"
+	BackendQuickChart = "quickchart"
+)
+
+// NewBackend creates the word cloud renderer selected by WORD_CLOUD_RENDERER.
+// QuickChart sends the whole document to a third party and is only kept as an option.
+func NewBackend(cfg *config.Config) (renderer.WordCloudRenderer, error) {
+	switch cfg.WordCloudRenderer {
+	case BackendLocal, "":
+		return NewRenderer(cfg)
+	case BackendQuickChart:
+		return quickchart.NewQuickChart(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown word cloud renderer %q", cfg.WordCloudRenderer)
+	}
+}
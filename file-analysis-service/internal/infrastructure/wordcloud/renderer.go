@@ -0,0 +1,297 @@
+package wordcloud
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+
+	"fileanalysisservice/internal/domain/plagiarism"
+	"fileanalysisservice/internal/infrastructure/config"
+	"fileanalysisservice/internal/interfaces/renderer"
+)
+
+const (
+	minFontSize = 12
+	maxFontSize = 72
+	// spiralStep is the angle step of the spiral searching a free place for a word, in radians
+	spiralStep = 0.1
+)
+
+// defaultPalette is used when WORD_CLOUD_PALETTE is empty
+var defaultPalette = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#17becf"}
+
+// Renderer draws word clouds in process, the text never leaves the service
+type Renderer struct {
+	textProcessor *plagiarism.TextProcessor
+	font          *sfnt.Font
+	fontFamily    string
+	palette       []color.RGBA
+	width         int
+	height        int
+	maxWords      int
+}
+
+// NewRenderer creates a word cloud renderer with the configured font, palette, image size and maximum word count.
+// The built-in Go font is used when no font file is configured.
+func NewRenderer(cfg *config.Config) (*Renderer, error) {
+	fontData := goregular.TTF
+	if cfg.WordCloudFont != "" {
+		data, err := os.ReadFile(cfg.WordCloudFont)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read word cloud font: %w", err)
+		}
+		fontData = data
+	}
+
+	f, err := opentype.Parse(fontData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse word cloud font: %w", err)
+	}
+
+	family, err := f.Name(nil, sfnt.NameIDFamily)
+	if err != nil {
+		family = "sans-serif"
+	}
+
+	palette, err := parsePalette(cfg.WordCloudPalette)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.WordCloudWidth < 1 || cfg.WordCloudHeight < 1 || cfg.WordCloudMaxWords < 1 {
+		return nil, errors.New("word cloud size and maximum word count must be positive")
+	}
+
+	return &Renderer{
+		textProcessor: plagiarism.NewTextProcessor(),
+		font:          f,
+		fontFamily:    family,
+		palette:       palette,
+		width:         cfg.WordCloudWidth,
+		height:        cfg.WordCloudHeight,
+		maxWords:      cfg.WordCloudMaxWords,
+	}, nil
+}
+
+// parsePalette parses comma separated #rrggbb colors
+func parsePalette(value string) ([]color.RGBA, error) {
+	values := defaultPalette
+	if strings.TrimSpace(value) != "" {
+		values = strings.Split(value, ",")
+	}
+
+	palette := make([]color.RGBA, 0, len(values))
+	for _, v := range values {
+		c := color.RGBA{A: 255}
+		_, err := fmt.Sscanf(strings.TrimSpace(v), "#%02x%02x%02x", &c.R, &c.G, &c.B)
+		if err != nil {
+			return nil, fmt.Errorf("invalid word cloud palette color %q: %w", v, err)
+		}
+		palette = append(palette, c)
+	}
+
+	return palette, nil
+}
+
+// wordCount is a word of the text with the number of its occurrences
+type wordCount struct {
+	word  string
+	count int
+}
+
+// placedWord is a word laid out on the image, x and y are the baseline origin
+type placedWord struct {
+	word   string
+	size   float64
+	color  color.RGBA
+	x, y   int
+	bounds image.Rectangle
+}
+
+// WordCloud renders the most frequent words of the text as PNG and SVG images, a text without words gives an empty image
+func (r *Renderer) WordCloud(content string) (*renderer.WordCloud, error) {
+	words := r.countWords(content)
+	placed, err := r.layout(words)
+	if err != nil {
+		return nil, err
+	}
+
+	pngData, err := r.renderPNG(placed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &renderer.WordCloud{
+		PNG: pngData,
+		SVG: r.renderSVG(placed),
+	}, nil
+}
+
+// countWords counts words of the text without stop words, the most frequent first.
+// Words are shown as written (in lower case), stemming is used only to compare documents.
+func (r *Renderer) countWords(content string) []wordCount {
+	counts := make(map[string]int)
+	for _, token := range r.textProcessor.Tokenize(content) {
+		counts[strings.ToLower(content[token.StartPos:token.EndPos])]++
+	}
+
+	words := make([]wordCount, 0, len(counts))
+	for word, count := range counts {
+		words = append(words, wordCount{word: word, count: count})
+	}
+
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].count != words[j].count {
+			return words[i].count > words[j].count
+		}
+		return words[i].word < words[j].word
+	})
+
+	if len(words) > r.maxWords {
+		words = words[:r.maxWords]
+	}
+
+	return words
+}
+
+// layout places words from the center outwards along a spiral, a word that doesn't fit anywhere is skipped
+func (r *Renderer) layout(words []wordCount) ([]placedWord, error) {
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	maxCount, minCount := words[0].count, words[len(words)-1].count
+	center := image.Pt(r.width/2, r.height/2)
+	maxRadius := math.Hypot(float64(r.width), float64(r.height)) / 2
+
+	var placed []placedWord
+	for i, w := range words {
+		size := fontSize(w.count, minCount, maxCount, r.height)
+		face, err := r.face(size)
+		if err != nil {
+			return nil, err
+		}
+
+		advance := font.MeasureString(face, w.word).Ceil()
+		metrics := face.Metrics()
+		ascent, descent := metrics.Ascent.Ceil(), metrics.Descent.Ceil()
+		_ = face.Close()
+
+		for angle := 0.0; ; angle += spiralStep {
+			radius := angle * 2
+			if radius > maxRadius {
+				break
+			}
+
+			x := center.X + int(radius*math.Cos(angle)) - advance/2
+			y := center.Y + int(radius*math.Sin(angle)) + (ascent-descent)/2
+			bounds := image.Rect(x, y-ascent, x+advance, y+descent)
+			if !bounds.In(image.Rect(0, 0, r.width, r.height)) || overlaps(bounds, placed) {
+				continue
+			}
+
+			placed = append(placed, placedWord{
+				word:   w.word,
+				size:   size,
+				color:  r.palette[i%len(r.palette)],
+				x:      x,
+				y:      y,
+				bounds: bounds,
+			})
+			break
+		}
+	}
+
+	return placed, nil
+}
+
+// fontSize scales the font size with the square root of the word frequency
+func fontSize(count, minCount, maxCount, height int) float64 {
+	largest := math.Min(maxFontSize, float64(height)/4)
+	smallest := math.Min(minFontSize, largest)
+	if maxCount == minCount {
+		return largest
+	}
+
+	ratio := (math.Sqrt(float64(count)) - math.Sqrt(float64(minCount))) / (math.Sqrt(float64(maxCount)) - math.Sqrt(float64(minCount)))
+	return smallest + ratio*(largest-smallest)
+}
+
+func overlaps(bounds image.Rectangle, placed []placedWord) bool {
+	for _, p := range placed {
+		if bounds.Overlaps(p.bounds) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Renderer) face(size float64) (font.Face, error) {
+	face, err := opentype.NewFace(r.font, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create font face: %w", err)
+	}
+	return face, nil
+}
+
+func (r *Renderer) renderPNG(placed []placedWord) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, r.width, r.height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	for _, p := range placed {
+		face, err := r.face(p.size)
+		if err != nil {
+			return nil, err
+		}
+
+		drawer := &font.Drawer{
+			Dst:  img,
+			Src:  image.NewUniform(p.color),
+			Face: face,
+			Dot:  fixed.P(p.x, p.y),
+		}
+		drawer.DrawString(p.word)
+		_ = face.Close()
+	}
+
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode word cloud: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (r *Renderer) renderSVG(placed []placedWord) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", r.width, r.height, r.width, r.height)
+	b.WriteString(`<rect width="100%" height="100%" fill="#ffffff"/>` + "\n")
+
+	for _, p := range placed {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-family="%s" font-size="%.1f" fill="#%02x%02x%02x">%s</text>`+"\n",
+			p.x, p.y, html.EscapeString(r.fontFamily), p.size, p.color.R, p.color.G, p.color.B, html.EscapeString(p.word))
+	}
+
+	b.WriteString("</svg>\n")
+	return b.Bytes()
+}
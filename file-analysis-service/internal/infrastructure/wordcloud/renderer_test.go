@@ -0,0 +1,80 @@
+package wordcloud
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"fileanalysisservice/internal/infrastructure/config"
+)
+
+func testConfig() *config.Config {
+	return &config.Config{
+		WordCloudPalette:  "#112233,#445566",
+		WordCloudWidth:    400,
+		WordCloudHeight:   300,
+		WordCloudMaxWords: 3,
+	}
+}
+
+func TestRenderer_WordCloud(t *testing.T) {
+	r, err := NewRenderer(testConfig())
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	content := "Плагиат плагиат плагиат и списывание, списывание. Студенты пишут работы и отчеты."
+	wordCloud, err := r.WordCloud(content)
+	if err != nil {
+		t.Fatalf("WordCloud() error = %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(wordCloud.PNG))
+	if err != nil {
+		t.Fatalf("WordCloud() PNG is invalid: %v", err)
+	}
+	if img.Bounds().Dx() != 400 || img.Bounds().Dy() != 300 {
+		t.Errorf("PNG size = %v, want 400x300", img.Bounds())
+	}
+
+	svg := string(wordCloud.SVG)
+	if !strings.HasPrefix(svg, "<svg") || strings.Count(svg, "<text") != 3 {
+		t.Errorf("SVG = %s, want 3 words", svg)
+	}
+	if !strings.Contains(svg, ">плагиат</text>") || !strings.Contains(svg, `fill="#112233"`) {
+		t.Errorf("SVG = %s, want the most frequent word in the first palette color", svg)
+	}
+	if strings.Contains(svg, ">и</text>") {
+		t.Errorf("SVG = %s, stop words must be removed", svg)
+	}
+}
+
+func TestRenderer_WordCloud_Empty(t *testing.T) {
+	r, err := NewRenderer(testConfig())
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	wordCloud, err := r.WordCloud("")
+	if err != nil {
+		t.Fatalf("WordCloud() error = %v", err)
+	}
+	if len(wordCloud.PNG) == 0 || strings.Contains(string(wordCloud.SVG), "<text") {
+		t.Errorf("WordCloud() of an empty text must be an empty image")
+	}
+}
+
+func TestNewRenderer_InvalidConfig(t *testing.T) {
+	cfg := testConfig()
+	cfg.WordCloudPalette = "red"
+	if _, err := NewRenderer(cfg); err == nil {
+		t.Error("NewRenderer() with an invalid palette must fail")
+	}
+
+	cfg = testConfig()
+	cfg.WordCloudFont = "/nonexistent/font.ttf"
+	if _, err := NewRenderer(cfg); err == nil {
+		t.Error("NewRenderer() with a missing font must fail")
+	}
+}
@@ -2,10 +2,16 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fileanalysisservice/internal/application/service"
+	"fileanalysisservice/internal/domain/analysis"
+	"fileanalysisservice/internal/domain/plagiarism"
+	"fileanalysisservice/internal/infrastructure/filestoringservice"
+	"fileanalysisservice/internal/infrastructure/storage/s3"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 )
 
 // AnalyseHandler handles HTTP requests related to files
@@ -27,14 +33,15 @@ type ErrorResponse struct {
 
 // GetAnalyse handles the analysis retrieval endpoint
 // @Summary Retrieve file analysis
-// @Description Get analysis details for a specific file by its ID
+// @Description Get the latest analysis of a file by its ID or the given analysis version, analyses are requested with POST /analysis
 // @Tags analysis
 // @Accept json
 // @Produce json
 // @Param id path string true "File ID"
+// @Param version query int false "Analysis version, the latest by default"
 // @Success 200 {object} map[string]any "Analysis details"
 // @Failure 400 {object} ErrorResponse "Bad Request - File ID is required"
-// @Failure 404 {object} ErrorResponse "Not Found - Analysis not found"
+// @Failure 404 {object} ErrorResponse "Not Found - File has not been analysed yet"
 // @Failure 500 {object} ErrorResponse "Internal Server Error - Failed to get analysis"
 // @Router /analysis/{id} [get]
 func (h *AnalyseHandler) GetAnalyse(w http.ResponseWriter, r *http.Request) {
@@ -45,7 +52,18 @@ func (h *AnalyseHandler) GetAnalyse(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	analysisModel, err := h.contentAnalyserService.Analyse(r.Context(), id)
+	version, err := versionParam(r, "version")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var analysisModel *analysis.Analysis
+	if version > 0 {
+		analysisModel, err = h.contentAnalyserService.GetAnalysisVersion(r.Context(), id, version)
+	} else {
+		analysisModel, err = h.contentAnalyserService.GetAnalysis(r.Context(), id)
+	}
 	if err != nil {
 		http.Error(w, "Failed to get analysis: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -61,6 +79,7 @@ func (h *AnalyseHandler) GetAnalyse(w http.ResponseWriter, r *http.Request) {
 	response := map[string]any{
 		"id":                analysisModel.ID,
 		"file_id":           analysisModel.FileID,
+		"version":           analysisModel.Version,
 		"image_location":    analysisModel.ImageLocation,
 		"plagiarism_report": analysisModel.PlagiarismReport,
 		"statistics":        analysisModel.Statistics,
@@ -74,12 +93,153 @@ func (h *AnalyseHandler) GetAnalyse(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// AnalysisVersionResponse summarizes an analysis version in the history of a file
+type AnalysisVersionResponse struct {
+	ID                   string  `json:"id" example:"12345678-1234-1234-1234-123456789012"`
+	Version              int     `json:"version" example:"2"`
+	Algorithm            string  `json:"algorithm,omitempty" example:"winnowing"`
+	Options              string  `json:"options,omitempty" example:"scope=assignment,quotes=true,bibliography=false,assignment="` // Область поиска и исключения
+	UniquenessPercentage float64 `json:"uniqueness_percentage,omitempty" example:"87.5"`
+	Sources              int     `json:"sources" example:"3"` // Количество найденных источников
+	CreatedAt            string  `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// GetHistory handles requests to list the analysis versions of a file
+// @Summary Analysis history
+// @Description List every analysis version of a file, oldest first. Versions are added by POST /analysis/{id}/rerun.
+// @Tags analysis
+// @Produce json
+// @Param id path string true "File ID"
+// @Success 200 {array} AnalysisVersionResponse "Analysis versions"
+// @Failure 400 {object} ErrorResponse "Bad request"
+// @Failure 404 {object} ErrorResponse "File has not been analysed yet"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /analysis/{id}/history [get]
+func (h *AnalyseHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if id == "" {
+		http.Error(w, "File ID is required", http.StatusBadRequest)
+		return
+	}
+
+	analyses, err := h.contentAnalyserService.History(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Failed to get analysis history: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(analyses) == 0 {
+		http.Error(w, "Analysis not found", http.StatusNotFound)
+		return
+	}
+
+	versions := make([]map[string]any, 0, len(analyses))
+	for _, analysisModel := range analyses {
+		version := map[string]any{
+			"id":         analysisModel.ID,
+			"version":    analysisModel.Version,
+			"sources":    0,
+			"created_at": analysisModel.CreatedAt,
+		}
+		if report := analysisModel.PlagiarismReport; report != nil {
+			version["algorithm"] = report.Algorithm.Name
+			version["uniqueness_percentage"] = report.UniquenessPercentage
+			version["sources"] = len(report.Matches)
+		}
+		if analysisModel.Options != "" {
+			version["options"] = analysisModel.Options
+		}
+		versions = append(versions, version)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(versions)
+	if err != nil {
+		return
+	}
+}
+
+// Diff handles requests to compare two analysis versions of a file
+// @Summary Compare analysis versions
+// @Description Show how the analysis of a file changed between two versions: the uniqueness change and the sources
+// @Description that appeared, disappeared or changed their similarity. By default the latest version is compared with the previous one.
+// @Tags analysis
+// @Produce json
+// @Param id path string true "File ID"
+// @Param from query int false "Older version, the version preceding to by default"
+// @Param to query int false "Newer version, the latest by default"
+// @Success 200 {object} analysis.VersionDiff "Difference of the versions"
+// @Failure 400 {object} ErrorResponse "Bad request"
+// @Failure 404 {object} ErrorResponse "Version not found"
+// @Failure 409 {object} ErrorResponse "Version has no plagiarism report"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /analysis/{id}/diff [get]
+func (h *AnalyseHandler) Diff(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if id == "" {
+		http.Error(w, "File ID is required", http.StatusBadRequest)
+		return
+	}
+
+	from, err := versionParam(r, "from")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	to, err := versionParam(r, "to")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	diff, err := h.contentAnalyserService.DiffVersions(r.Context(), id, from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrVersionNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, analysis.ErrNoPlagiarismReport):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "Failed to compare analysis versions: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(diff)
+	if err != nil {
+		return
+	}
+}
+
+// versionParam parses an optional analysis version query parameter, 0 if it is absent
+func versionParam(r *http.Request, name string) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid %s version %q, expected a positive number", name, value)
+	}
+
+	return version, nil
+}
+
 // DownloadCloud handles cloud analysis download requests
 // @Summary Download a cloud image by ID
-// @Description Download the actual analysis cloud image by its ID
+// @Description Download the actual analysis cloud image by its ID, as PNG or as SVG with format=svg
 // @Tags analysis
-// @Produce application/octet-stream
+// @Produce image/png
+// @Produce image/svg+xml
 // @Param id path string true "Analysis ID"
+// @Param format query string false "Image format" Enums(png, svg)
 // @Success 200 {analysis} binary "Analysis image"
 // @Failure 400 {object} ErrorResponse "Bad request"
 // @Failure 404 {object} ErrorResponse "Analysis not found"
@@ -93,9 +253,15 @@ func (h *AnalyseHandler) DownloadCloud(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fileReader, _, err := h.contentAnalyserService.DownloadImage(r.Context(), id)
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "png" && format != "svg" {
+		http.Error(w, "Unknown format, expected png or svg", http.StatusBadRequest)
+		return
+	}
+
+	fileReader, _, err := h.contentAnalyserService.DownloadImage(r.Context(), id, format == "svg")
 	if err != nil {
-		if err.Error() == "file not found" {
+		if err.Error() == "file not found" || errors.Is(err, s3.ErrNotFound) {
 			http.Error(w, "File not found", http.StatusNotFound)
 			return
 		}
@@ -109,7 +275,11 @@ func (h *AnalyseHandler) DownloadCloud(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	w.Header().Set("Content-Type", "image/png")
+	if format == "svg" {
+		w.Header().Set("Content-Type", "image/svg+xml")
+	} else {
+		w.Header().Set("Content-Type", "image/png")
+	}
 
 	_, err = io.Copy(w, fileReader)
 	if err != nil {
@@ -117,3 +287,142 @@ func (h *AnalyseHandler) DownloadCloud(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// DeleteFileData handles requests to remove analysis data of a deleted file
+// @Summary Delete file analysis data
+// @Description Delete shingles, analyses and word cloud images of a file, repeating the request is safe
+// @Tags analysis
+// @Param id path string true "File ID"
+// @Success 204 "Analysis data deleted"
+// @Failure 400 {object} ErrorResponse "Bad request"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /analysis/{id} [delete]
+func (h *AnalyseHandler) DeleteFileData(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if id == "" {
+		http.Error(w, "File ID is required", http.StatusBadRequest)
+		return
+	}
+
+	err := h.contentAnalyserService.DeleteFileData(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Failed to delete analysis data: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Compare handles requests to compare two files directly
+// @Summary Compare two files
+// @Description Compare two files with each other without the rest of the corpus, nothing is stored.
+// @Description Returns similarity in both directions, Jaccard and containment scores and the matched passages with positions in both files.
+// @Tags analysis
+// @Produce json
+// @Param a query string true "First file ID"
+// @Param b query string true "Second file ID"
+// @Param algorithm query string false "Plagiarism detection algorithm" Enums(shingles, winnowing)
+// @Success 200 {object} analysis.Comparison "Comparison of the files"
+// @Failure 400 {object} ErrorResponse "Bad request"
+// @Failure 404 {object} ErrorResponse "File not found"
+// @Failure 413 {object} ErrorResponse "File is too large"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "File storing service is unavailable"
+// @Router /compare [get]
+func (h *AnalyseHandler) Compare(w http.ResponseWriter, r *http.Request) {
+	fileA := r.URL.Query().Get("a")
+	fileB := r.URL.Query().Get("b")
+
+	if fileA == "" || fileB == "" {
+		http.Error(w, "Both file IDs a and b are required", http.StatusBadRequest)
+		return
+	}
+
+	comparison, err := h.contentAnalyserService.Compare(r.Context(), fileA, fileB, r.URL.Query().Get("algorithm"))
+	if err != nil {
+		switch {
+		case errors.Is(err, plagiarism.ErrUnknownAlgorithm):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, filestoringservice.ErrFileNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, filestoringservice.ErrFileTooLarge):
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		case errors.Is(err, filestoringservice.ErrUnavailable):
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		default:
+			http.Error(w, "Failed to compare files: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(comparison)
+	if err != nil {
+		return
+	}
+}
+
+// ClustersRequest represents the request body for computing similarity clusters
+type ClustersRequest struct {
+	FileIDs   []string `json:"file_ids"`
+	Threshold *float64 `json:"threshold,omitempty" example:"50"`
+	Algorithm string   `json:"algorithm,omitempty" example:"shingles" enums:"shingles,winnowing"`
+}
+
+// Clusters handles requests to find groups of similar files
+// @Summary Similarity matrix and clusters
+// @Description Compute the pairwise similarity matrix of analysed files from their stored shingles and group files
+// @Description whose similarity reaches the threshold (percent, CLUSTER_THRESHOLD by default) into clusters.
+// @Description With format=dot the similarity graph is returned in the Graphviz DOT language.
+// @Tags analysis
+// @Accept json
+// @Produce json
+// @Produce text/vnd.graphviz
+// @Param request body ClustersRequest true "Files to cluster"
+// @Param format query string false "Response format" Enums(json, dot)
+// @Success 200 {object} analysis.ClusterReport "Similarity matrix and clusters"
+// @Failure 400 {object} ErrorResponse "Bad request"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /clusters [post]
+func (h *AnalyseHandler) Clusters(w http.ResponseWriter, r *http.Request) {
+	var request ClustersRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, "Failed to decode request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "dot" {
+		http.Error(w, "Unknown format, expected json or dot", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.contentAnalyserService.SimilarityClusters(r.Context(), request.FileIDs, request.Algorithm, request.Threshold)
+	if err != nil {
+		switch {
+		case errors.Is(err, plagiarism.ErrUnknownAlgorithm),
+			errors.Is(err, plagiarism.ErrInvalidThreshold),
+			errors.Is(err, plagiarism.ErrInvalidFileSet):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Failed to compute clusters: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if format == "dot" {
+		w.Header().Set("Content-Type", "text/vnd.graphviz")
+		_, _ = io.WriteString(w, plagiarism.ClusterDOT(report))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(report)
+	if err != nil {
+		return
+	}
+}
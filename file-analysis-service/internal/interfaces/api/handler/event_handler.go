@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"fileanalysisservice/internal/application/service"
+	"fileanalysisservice/internal/domain/event"
+)
+
+// EventHandler receives the events of other services
+type EventHandler struct {
+	eventService *service.EventService
+}
+
+func NewEventHandler(eventService *service.EventService) *EventHandler {
+	return &EventHandler{
+		eventService: eventService,
+	}
+}
+
+// EventRequest represents an event delivered by another service
+type EventRequest struct {
+	ID        string          `json:"id" example:"12345678-1234-1234-1234-123456789012"`
+	Type      string          `json:"type" example:"file.uploaded"`
+	Payload   json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt time.Time       `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// ReceiveEvent handles events delivered by file-storing-service
+// @Summary Receive an event
+// @Description Receive an event of another service. Events are delivered at least once and deduplicated by ID, repeating the request is safe.
+// @Description A file.uploaded event queues the analysis of the new file, events of other types are acknowledged and skipped.
+// @Tags events
+// @Accept json
+// @Param request body EventRequest true "Event"
+// @Success 202 "Event accepted"
+// @Success 200 "Event has already been processed"
+// @Failure 400 {object} ErrorResponse "Bad request"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /events [post]
+func (h *EventHandler) ReceiveEvent(w http.ResponseWriter, r *http.Request) {
+	var request EventRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, "Failed to decode event: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	processed, err := h.eventService.Handle(r.Context(), &event.Event{
+		ID:        request.ID,
+		Type:      request.Type,
+		Payload:   request.Payload,
+		CreatedAt: request.CreatedAt,
+	})
+	if err != nil {
+		if errors.Is(err, event.ErrInvalidEvent) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to handle event: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if !processed {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fileanalysisservice/internal/application/service"
+	"fileanalysisservice/internal/domain/job"
+	"fileanalysisservice/internal/domain/plagiarism"
+	"io"
+	"net/http"
+)
+
+// JobHandler handles HTTP requests related to analysis jobs
+type JobHandler struct {
+	analysisJobService *service.AnalysisJobService
+}
+
+func NewJobHandler(analysisJobService *service.AnalysisJobService) *JobHandler {
+	return &JobHandler{
+		analysisJobService: analysisJobService,
+	}
+}
+
+// CreateJobRequest represents the request body for queueing an analysis
+type CreateJobRequest struct {
+	FileID              string `json:"file_id" example:"12345678-1234-1234-1234-123456789012"`
+	Algorithm           string `json:"algorithm,omitempty" example:"winnowing" enums:"shingles,winnowing"`
+	ExcludeQuotes       bool   `json:"exclude_quotes,omitempty" example:"true"`                                        // Не учитывать цитаты в уникальности
+	ExcludeBibliography bool   `json:"exclude_bibliography,omitempty" example:"true"`                                  // Не учитывать список литературы в уникальности
+	AssignmentID        string `json:"assignment_id,omitempty" example:"algorithms-2024-hw1"`                          // Не учитывать текст шаблонов задания
+	Scope               string `json:"scope,omitempty" example:"assignment" enums:"assignment,course,all,prior_years"` // С какими работами сравнивать
+	CodeLanguage        string `json:"code_language,omitempty" example:"python" enums:"go,python,java,c"`              // Анализировать файл как исходный код
+}
+
+// RerunRequest represents the optional request body for repeating an analysis
+type RerunRequest struct {
+	Algorithm           string `json:"algorithm,omitempty" example:"winnowing" enums:"shingles,winnowing"`
+	ExcludeQuotes       bool   `json:"exclude_quotes,omitempty" example:"true"`                                        // Не учитывать цитаты в уникальности
+	ExcludeBibliography bool   `json:"exclude_bibliography,omitempty" example:"true"`                                  // Не учитывать список литературы в уникальности
+	AssignmentID        string `json:"assignment_id,omitempty" example:"algorithms-2024-hw1"`                          // Не учитывать текст шаблонов задания
+	Scope               string `json:"scope,omitempty" example:"assignment" enums:"assignment,course,all,prior_years"` // С какими работами сравнивать
+	CodeLanguage        string `json:"code_language,omitempty" example:"python" enums:"go,python,java,c"`              // Анализировать файл как исходный код
+}
+
+// JobResponse represents the state of an analysis job
+type JobResponse struct {
+	ID                  string `json:"id" example:"12345678-1234-1234-1234-123456789012"`
+	FileID              string `json:"file_id" example:"12345678-1234-1234-1234-123456789012"`
+	Algorithm           string `json:"algorithm,omitempty" example:"winnowing"`
+	ExcludeQuotes       bool   `json:"exclude_quotes,omitempty" example:"true"`
+	ExcludeBibliography bool   `json:"exclude_bibliography,omitempty" example:"true"`
+	AssignmentID        string `json:"assignment_id,omitempty" example:"algorithms-2024-hw1"`
+	Scope               string `json:"scope" example:"assignment"`
+	CodeLanguage        string `json:"code_language,omitempty" example:"python"`
+	Rerun               bool   `json:"rerun,omitempty" example:"true"`
+	Status              string `json:"status" example:"queued"`
+	Attempts            int    `json:"attempts" example:"0"`
+	MaxAttempts         int    `json:"max_attempts" example:"5"`
+	Error               string `json:"error,omitempty" example:"failed to get file content"`
+	AnalysisID          string `json:"analysis_id,omitempty"`
+	NextRunAt           string `json:"next_run_at,omitempty" example:"2023-01-01T12:00:00Z"`
+	FinishedAt          string `json:"finished_at,omitempty" example:"2023-01-01T12:00:00Z"`
+	CreatedAt           string `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// CreateJob handles requests to queue a file analysis
+// @Summary Queue file analysis
+// @Description Queue an analysis of a file and return the job to poll. A file already queued with the same options returns its current job,
+// @Description a job with other options runs after the active job of the file.
+// @Description The plagiarism detection algorithm is full shingling by default or winnowing.
+// @Description Quotations, the trailing bibliography and the text of the assignment templates can be excluded from the uniqueness score.
+// @Description The file is compared with the submissions to the same assignment, to the same course, to the same course in earlier years or with all files (default).
+// @Description With code_language the file is analysed as source code: identifiers and literals are normalized and matches carry line numbers.
+// @Tags analysis
+// @Accept json
+// @Produce json
+// @Param request body CreateJobRequest true "File to analyse"
+// @Success 202 {object} JobResponse "Analysis queued"
+// @Failure 400 {object} ErrorResponse "Bad request"
+// @Failure 404 {object} ErrorResponse "File has been deleted"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /analysis [post]
+func (h *JobHandler) CreateJob(w http.ResponseWriter, r *http.Request) {
+	var request CreateJobRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, "Failed to decode request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if request.FileID == "" {
+		http.Error(w, "File ID is required", http.StatusBadRequest)
+		return
+	}
+
+	exclusions := plagiarism.ExclusionOptions{
+		Quotes:       request.ExcludeQuotes,
+		Bibliography: request.ExcludeBibliography,
+		Assignment:   request.AssignmentID,
+	}
+	j, err := h.analysisJobService.Enqueue(r.Context(), request.FileID, request.Algorithm, exclusions, job.Scope(request.Scope), request.CodeLanguage)
+	if err != nil {
+		if errors.Is(err, plagiarism.ErrUnknownAlgorithm) || errors.Is(err, job.ErrInvalidScope) || errors.Is(err, plagiarism.ErrUnknownCodeLanguage) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, service.ErrFileDeleted) {
+			http.Error(w, "File not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to queue analysis: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/analysis-api/jobs/"+j.ID)
+	w.WriteHeader(http.StatusAccepted)
+
+	err = json.NewEncoder(w).Encode(jobResponse(j))
+	if err != nil {
+		return
+	}
+}
+
+// RerunAnalysis handles requests to analyse a file again
+// @Summary Rerun file analysis
+// @Description Queue a new analysis of a file even if it has been analysed already, e.g. after new submissions or with another algorithm.
+// @Description The result is stored as the next analysis version, the previous versions are kept in the history.
+// @Description The body is optional and takes the same analysis options as POST /analysis. Only an active rerun with the same options is reused.
+// @Tags analysis
+// @Accept json
+// @Produce json
+// @Param id path string true "File ID"
+// @Param request body RerunRequest false "Analysis options"
+// @Success 202 {object} JobResponse "Analysis queued"
+// @Failure 400 {object} ErrorResponse "Bad request"
+// @Failure 404 {object} ErrorResponse "File has been deleted"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /analysis/{id}/rerun [post]
+func (h *JobHandler) RerunAnalysis(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if id == "" {
+		http.Error(w, "File ID is required", http.StatusBadRequest)
+		return
+	}
+
+	var request RerunRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Failed to decode request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	exclusions := plagiarism.ExclusionOptions{
+		Quotes:       request.ExcludeQuotes,
+		Bibliography: request.ExcludeBibliography,
+		Assignment:   request.AssignmentID,
+	}
+	j, err := h.analysisJobService.Rerun(r.Context(), id, request.Algorithm, exclusions, job.Scope(request.Scope), request.CodeLanguage)
+	if err != nil {
+		if errors.Is(err, plagiarism.ErrUnknownAlgorithm) || errors.Is(err, job.ErrInvalidScope) || errors.Is(err, plagiarism.ErrUnknownCodeLanguage) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, service.ErrFileDeleted) {
+			http.Error(w, "File not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to queue analysis: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/analysis-api/jobs/"+j.ID)
+	w.WriteHeader(http.StatusAccepted)
+
+	err = json.NewEncoder(w).Encode(jobResponse(j))
+	if err != nil {
+		return
+	}
+}
+
+// GetJob handles requests to retrieve the state of an analysis job
+// @Summary Get analysis job status
+// @Description Get the status of an analysis job: queued, running, done (with analysis_id), failed (with error) or cancelled (the file has been deleted)
+// @Tags analysis
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} JobResponse "Analysis job"
+// @Failure 404 {object} ErrorResponse "Job not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /jobs/{id} [get]
+func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	j, err := h.analysisJobService.GetJob(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrJobNotFound) {
+			http.Error(w, "Job not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get job: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(jobResponse(j))
+	if err != nil {
+		return
+	}
+}
+
+// jobResponse converts an analysis job to its response format
+func jobResponse(j *job.Job) map[string]any {
+	response := map[string]any{
+		"id":           j.ID,
+		"file_id":      j.FileID,
+		"scope":        j.Scope,
+		"status":       j.Status,
+		"attempts":     j.Attempts,
+		"max_attempts": j.MaxAttempts,
+		"created_at":   j.CreatedAt,
+	}
+
+	if j.Algorithm != "" {
+		response["algorithm"] = j.Algorithm
+	}
+	if j.ExcludeQuotes {
+		response["exclude_quotes"] = true
+	}
+	if j.ExcludeBibliography {
+		response["exclude_bibliography"] = true
+	}
+	if j.AssignmentID != "" {
+		response["assignment_id"] = j.AssignmentID
+	}
+	if j.CodeLanguage != "" {
+		response["code_language"] = j.CodeLanguage
+	}
+	if j.Rerun {
+		response["rerun"] = true
+	}
+	if j.LastError != "" {
+		response["error"] = j.LastError
+	}
+	if j.AnalysisID != "" {
+		response["analysis_id"] = j.AnalysisID
+	}
+	if j.Status == job.StatusQueued {
+		response["next_run_at"] = j.RunAt
+	}
+	if j.FinishedAt != nil {
+		response["finished_at"] = j.FinishedAt
+	}
+
+	return response
+}
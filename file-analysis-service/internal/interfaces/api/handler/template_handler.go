@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fileanalysisservice/internal/application/service"
+	"fileanalysisservice/internal/domain/plagiarism"
+	"fileanalysisservice/internal/infrastructure/filestoringservice"
+	"net/http"
+)
+
+// TemplateHandler handles HTTP requests related to assignment templates
+type TemplateHandler struct {
+	contentAnalyserService *service.ContentAnalyserService
+}
+
+func NewTemplateHandler(contentAnalyserService *service.ContentAnalyserService) *TemplateHandler {
+	return &TemplateHandler{
+		contentAnalyserService: contentAnalyserService,
+	}
+}
+
+// TemplatesResponse lists the template files of an assignment
+type TemplatesResponse struct {
+	AssignmentID string   `json:"assignment_id" example:"algorithms-2024-hw1"`
+	FileIDs      []string `json:"file_ids"`
+}
+
+// RegisterTemplate handles requests to register a stored file as an assignment template
+// @Summary Register assignment template
+// @Description Register a file uploaded to the file storing service as a template of the assignment (task text, instructor boilerplate).
+// @Description Text matching a template is not scored in analyses queued with the same assignment_id. Registering the file again refreshes it.
+// @Description A template with code_language is starter code, it is only excluded from source code analyses in that language.
+// @Tags templates
+// @Produce json
+// @Param assignment_id path string true "Assignment ID"
+// @Param file_id path string true "File ID"
+// @Param code_language query string false "Programming language of starter code" Enums(go, python, java, c)
+// @Success 204 "Template registered"
+// @Failure 400 {object} ErrorResponse "Unknown programming language"
+// @Failure 404 {object} ErrorResponse "File not found"
+// @Failure 413 {object} ErrorResponse "File is too large"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "File storing service is unavailable"
+// @Router /assignments/{assignment_id}/templates/{file_id} [put]
+func (h *TemplateHandler) RegisterTemplate(w http.ResponseWriter, r *http.Request) {
+	assignmentID := r.PathValue("assignment_id")
+	fileID := r.PathValue("file_id")
+
+	err := h.contentAnalyserService.RegisterTemplate(r.Context(), assignmentID, fileID, r.URL.Query().Get("code_language"))
+	if err != nil {
+		if errors.Is(err, plagiarism.ErrUnknownCodeLanguage) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, filestoringservice.ErrFileNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, filestoringservice.ErrFileTooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if errors.Is(err, filestoringservice.ErrUnavailable) {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, "Failed to register template: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetTemplates handles requests to list the templates of an assignment
+// @Summary List assignment templates
+// @Description List the files registered as templates of the assignment
+// @Tags templates
+// @Produce json
+// @Param assignment_id path string true "Assignment ID"
+// @Success 200 {object} TemplatesResponse "Assignment templates"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /assignments/{assignment_id}/templates [get]
+func (h *TemplateHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	assignmentID := r.PathValue("assignment_id")
+
+	fileIDs, err := h.contentAnalyserService.Templates(r.Context(), assignmentID)
+	if err != nil {
+		http.Error(w, "Failed to get templates: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(TemplatesResponse{AssignmentID: assignmentID, FileIDs: fileIDs})
+	if err != nil {
+		return
+	}
+}
+
+// DeleteTemplate handles requests to unregister an assignment template
+// @Summary Remove assignment template
+// @Description Stop excluding the text of the file from analyses of the assignment, repeating the request is safe
+// @Tags templates
+// @Param assignment_id path string true "Assignment ID"
+// @Param file_id path string true "File ID"
+// @Success 204 "Template removed"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /assignments/{assignment_id}/templates/{file_id} [delete]
+func (h *TemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	err := h.contentAnalyserService.RemoveTemplate(r.Context(), r.PathValue("assignment_id"), r.PathValue("file_id"))
+	if err != nil {
+		http.Error(w, "Failed to remove template: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -10,17 +10,23 @@ import (
 
 // Router handles HTTP routing
 type Router struct {
-	analyseHandler *handler.AnalyseHandler
-	infoHandler    *handler.InfoHandler
-	docsHandler    *handler.DocsHandler
+	analyseHandler  *handler.AnalyseHandler
+	jobHandler      *handler.JobHandler
+	templateHandler *handler.TemplateHandler
+	eventHandler    *handler.EventHandler
+	infoHandler     *handler.InfoHandler
+	docsHandler     *handler.DocsHandler
 }
 
 // NewRouter creates a new router
-func NewRouter(analyseHandler *handler.AnalyseHandler, infoHandler *handler.InfoHandler, docsHandler *handler.DocsHandler) *Router {
+func NewRouter(analyseHandler *handler.AnalyseHandler, jobHandler *handler.JobHandler, templateHandler *handler.TemplateHandler, eventHandler *handler.EventHandler, infoHandler *handler.InfoHandler, docsHandler *handler.DocsHandler) *Router {
 	return &Router{
-		analyseHandler: analyseHandler,
-		infoHandler:    infoHandler,
-		docsHandler:    docsHandler,
+		analyseHandler:  analyseHandler,
+		jobHandler:      jobHandler,
+		templateHandler: templateHandler,
+		eventHandler:    eventHandler,
+		infoHandler:     infoHandler,
+		docsHandler:     docsHandler,
 	}
 }
 
@@ -32,8 +38,24 @@ func (r *Router) SetupRoutes() http.Handler {
 	mux.HandleFunc("GET /analysis-api/info/health", r.infoHandler.HealthCheck)
 
 	// Analyse routes
+	mux.HandleFunc("POST /analysis-api/analysis", r.jobHandler.CreateJob)
+	mux.HandleFunc("GET /analysis-api/jobs/{id}", r.jobHandler.GetJob)
 	mux.HandleFunc("GET /analysis-api/analysis/{id}", r.analyseHandler.GetAnalyse)
 	mux.HandleFunc("GET /analysis-api/analysis/{id}/download", r.analyseHandler.DownloadCloud)
+	mux.HandleFunc("POST /analysis-api/analysis/{id}/rerun", r.jobHandler.RerunAnalysis)
+	mux.HandleFunc("GET /analysis-api/analysis/{id}/history", r.analyseHandler.GetHistory)
+	mux.HandleFunc("GET /analysis-api/analysis/{id}/diff", r.analyseHandler.Diff)
+	mux.HandleFunc("DELETE /analysis-api/analysis/{id}", r.analyseHandler.DeleteFileData)
+	mux.HandleFunc("GET /analysis-api/compare", r.analyseHandler.Compare)
+	mux.HandleFunc("POST /analysis-api/clusters", r.analyseHandler.Clusters)
+
+	// Assignment template routes
+	mux.HandleFunc("GET /analysis-api/assignments/{assignment_id}/templates", r.templateHandler.GetTemplates)
+	mux.HandleFunc("PUT /analysis-api/assignments/{assignment_id}/templates/{file_id}", r.templateHandler.RegisterTemplate)
+	mux.HandleFunc("DELETE /analysis-api/assignments/{assignment_id}/templates/{file_id}", r.templateHandler.DeleteTemplate)
+
+	// Events of other services
+	mux.HandleFunc("POST /analysis-api/events", r.eventHandler.ReceiveEvent)
 
 	// Swagger docs
 	mux.HandleFunc("GET /analysis-api/docs/", r.docsHandler.Docs)
@@ -0,0 +1,11 @@
+package renderer
+
+// WordCloud is a rendered word cloud image
+type WordCloud struct {
+	PNG []byte
+	SVG []byte // Пусто, если рендерер не умеет SVG
+}
+
+type WordCloudRenderer interface {
+	WordCloud(content string) (*WordCloud, error)
+}
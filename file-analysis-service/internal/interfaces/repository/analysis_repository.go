@@ -7,6 +7,9 @@ import (
 )
 
 type AnalysisRepository interface {
-	Store(ctx context.Context, file *analysis.Analysis) error
+	Store(ctx context.Context, file *analysis.Analysis) (bool, error)
 	FindByID(ctx context.Context, id string) (*analysis.Analysis, error)
+	FindByFileID(ctx context.Context, fileID string, algorithm string, options string) (*analysis.Analysis, error)
+	FindAllByFileID(ctx context.Context, fileID string) ([]*analysis.Analysis, error)
+	DeleteByFileID(ctx context.Context, fileID string) error
 }
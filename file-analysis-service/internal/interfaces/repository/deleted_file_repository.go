@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// DeletedFileRepository remembers the files deleted in file-storing-service, so that nothing is analysed
+// or stored for them anymore
+type DeletedFileRepository interface {
+	MarkDeleted(ctx context.Context, fileID string, deletedAt time.Time) error
+	IsDeleted(ctx context.Context, fileID string) (bool, error)
+}
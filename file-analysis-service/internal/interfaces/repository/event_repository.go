@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// EventRepository remembers the events already handled, so that redelivered events are ignored
+type EventRepository interface {
+	IsProcessed(ctx context.Context, id string) (bool, error)
+	MarkProcessed(ctx context.Context, id string, eventType string, processedAt time.Time) error
+}
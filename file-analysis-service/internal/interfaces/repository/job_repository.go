@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"fileanalysisservice/internal/domain/job"
+)
+
+type JobRepository interface {
+	Store(ctx context.Context, job *job.Job) (bool, error)
+	Update(ctx context.Context, job *job.Job) error
+	FindByID(ctx context.Context, id string) (*job.Job, error)
+	FindActive(ctx context.Context, job *job.Job) (*job.Job, error)
+	ClaimNext(ctx context.Context, now time.Time) (*job.Job, error)
+	CancelByFileID(ctx context.Context, fileID string, reason string, now time.Time) error
+}
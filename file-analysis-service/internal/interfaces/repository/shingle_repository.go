@@ -8,19 +8,33 @@ type ShingleMatch struct {
 	FileID      string
 	ShingleHash string
 	ShingleText string
-	StartPos    int
+	StartPos    int // Byte offsets in the original text
 	EndPos      int
+	StartRune   int // Rune offsets in the original text
+	EndRune     int
+	StartLine   int // Lines of the original source code, 0 for natural text
+	EndLine     int
 }
 
+// ShingleRepository stores fingerprints of analysed files and, in a separate namespace, of assignment templates.
+// Template fingerprints are never returned as fingerprints of analysed files.
 type ShingleRepository interface {
-	StoreShingles(ctx context.Context, fileID string, shingles []ShingleData) error
-	FindMatchingShingles(ctx context.Context, hashes []string, excludeFileID string) ([]ShingleMatch, error)
+	StoreShingles(ctx context.Context, fileID string, algorithm string, shingles []ShingleData) error
+	FindShinglesByFileIDs(ctx context.Context, algorithm string, fileIDs []string) ([]ShingleMatch, error)
 	DeleteShingles(ctx context.Context, fileID string) error
+	StoreTemplateShingles(ctx context.Context, assignmentID string, fileID string, algorithm string, shingles []ShingleData) error
+	FindTemplateHashes(ctx context.Context, assignmentID string, algorithm string) ([]string, error)
+	FindTemplateFileIDs(ctx context.Context, assignmentID string) ([]string, error)
+	DeleteTemplateShingles(ctx context.Context, assignmentID string, fileID string) error
 }
 
 type ShingleData struct {
-	Hash     string
-	Text     string
-	StartPos int
-	EndPos   int
+	Hash      string
+	Text      string
+	StartPos  int // Byte offsets in the original text
+	EndPos    int
+	StartRune int // Rune offsets in the original text
+	EndRune   int
+	StartLine int // Lines of the original source code, 0 for natural text
+	EndLine   int
 }
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+)
+
+// LSHCandidate is a document sharing at least one LSH band with the analysed document
+type LSHCandidate struct {
+	FileID      string
+	SharedBands int
+}
+
+type SignatureRepository interface {
+	StoreSignature(ctx context.Context, fileID string, signature []uint64, bands []string) error
+	// FindCandidates searches among sourceFileIDs only, nil means all files
+	FindCandidates(ctx context.Context, bands []string, excludeFileID string, limit int, sourceFileIDs []string) ([]LSHCandidate, error)
+	FindSignatures(ctx context.Context, fileIDs []string) (map[string][]uint64, error)
+	FindUnsignedFileIDs(ctx context.Context, limit int) ([]string, error)
+	DeleteSignature(ctx context.Context, fileID string) error
+}
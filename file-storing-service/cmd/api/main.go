@@ -23,6 +23,7 @@ import (
 	"context"
 	"errors"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -43,6 +44,13 @@ func main() {
 		Handler: app.Router.SetupRoutes(),
 	}
 
+	// Deletions whose cleanup failed are finished in the background
+	cleanupCtx, stopCleanup := context.WithCancel(context.Background())
+	go app.FileService.RunDeletionRetries(cleanupCtx)
+
+	// Events written with the uploads are delivered to file-analysis-service
+	go app.OutboxRelay.Run(cleanupCtx)
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
@@ -53,15 +61,42 @@ func main() {
 		}
 	}()
 
+	// Other services use the gRPC API, external clients the REST one
+	listener, err := net.Listen("tcp", ":"+app.Config.GRPCPort)
+	if err != nil {
+		log.Fatalf("Failed to listen on gRPC port: %v", err)
+	}
+
+	go func() {
+		log.Printf("Starting gRPC server on port %s", app.Config.GRPCPort)
+		if err := app.GRPCServer.Serve(listener); err != nil {
+			log.Fatalf("Failed to start gRPC server: %v", err)
+		}
+	}()
+
 	<-quit
 	log.Println("Shutting down server...")
+	stopCleanup()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Change streams never end on their own, they are cut once the running calls had their time
+	stopped := make(chan struct{})
+	go func() {
+		app.GRPCServer.GracefulStop()
+		close(stopped)
+	}()
+
 	if err := server.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		app.GRPCServer.Stop()
+	}
+
 	log.Println("Server exited gracefully")
 }
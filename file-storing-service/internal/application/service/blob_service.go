@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"filestoringservice/internal/domain/blob"
+	"filestoringservice/internal/domain/file"
+	"filestoringservice/internal/domain/outbox"
+	"filestoringservice/internal/infrastructure/storage/s3"
+	"filestoringservice/internal/interfaces/repository"
+)
+
+// releasedBlobBatchSize limits the amount of blobs removed from storage per purge
+const releasedBlobBatchSize = 100
+
+// BlobService keeps file content stored once per hash and removes it when the last file referencing it is gone
+type BlobService struct {
+	blobRepository repository.BlobRepository
+	fileRepository repository.FileRepository
+	fileStorage    *s3.FileStorage
+}
+
+// NewBlobService creates a new blob service
+func NewBlobService(blobRepository repository.BlobRepository, fileRepository repository.FileRepository, storage *s3.FileStorage) *BlobService {
+	return &BlobService{
+		blobRepository: blobRepository,
+		fileRepository: fileRepository,
+		fileStorage:    storage,
+	}
+}
+
+// FindByHash retrieves the stored content with the given hash, nil if it is not stored yet
+func (s *BlobService) FindByHash(ctx context.Context, hash string) (*blob.Blob, error) {
+	b, err := s.blobRepository.FindByHash(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob: %w", err)
+	}
+
+	return b, nil
+}
+
+// Register saves a blob whose content has just been uploaded to storage. If the same content was
+// stored concurrently by another upload, the new objects are removed and the existing blob is returned.
+func (s *BlobService) Register(ctx context.Context, b *blob.Blob) (*blob.Blob, error) {
+	stored, err := s.blobRepository.Store(ctx, b)
+	if err != nil {
+		s.deleteObjects(ctx, b)
+		return nil, fmt.Errorf("failed to store blob metadata: %w", err)
+	}
+
+	if stored {
+		return b, nil
+	}
+
+	s.deleteObjects(ctx, b)
+
+	existing, err := s.FindByHash(ctx, b.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, blob.ErrReleased
+	}
+
+	return existing, nil
+}
+
+// AttachFile stores a new file record referencing the blob together with its file.uploaded event
+func (s *BlobService) AttachFile(ctx context.Context, fileModel *file.File, b *blob.Blob) error {
+	if err := fileModel.SetBlob(b); err != nil {
+		return err
+	}
+
+	event, err := outbox.NewFileUploaded(fileModel)
+	if err != nil {
+		return err
+	}
+	event.ID = uuid.New().String()
+
+	if err := s.fileRepository.Store(ctx, fileModel, event); err != nil {
+		s.releaseUnreferenced(ctx, b)
+		return fmt.Errorf("failed to store file metadata: %w", err)
+	}
+
+	return nil
+}
+
+// releaseUnreferenced releases a blob no file has referenced yet, e.g. one registered for a file
+// that couldn't be stored, so that its content is removed by the next purge. Failures are only logged.
+func (s *BlobService) releaseUnreferenced(ctx context.Context, b *blob.Blob) {
+	if err := s.blobRepository.Release(context.WithoutCancel(ctx), b.ID, time.Now()); err != nil {
+		log.Printf("failed to release blob %s: %v", b.ID, err)
+	}
+}
+
+// PurgeReleased removes the content of blobs no file references anymore.
+// Every step is idempotent, a failed purge is repeated on the next call.
+func (s *BlobService) PurgeReleased(ctx context.Context) error {
+	blobs, err := s.blobRepository.FindReleased(ctx, releasedBlobBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get released blobs: %w", err)
+	}
+
+	var purgeErr error
+	for _, b := range blobs {
+		err = s.purge(ctx, b)
+		if err != nil {
+			log.Printf("failed to purge blob %s: %v", b.ID, err)
+			purgeErr = err
+		}
+	}
+
+	return purgeErr
+}
+
+// purge removes the blob content from storage, the metadata row goes last
+func (s *BlobService) purge(ctx context.Context, b *blob.Blob) error {
+	err := s.fileStorage.Delete(ctx, b.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete file from storage: %w", err)
+	}
+
+	if b.TextKey != "" {
+		err = s.fileStorage.Delete(ctx, b.TextKey)
+		if err != nil {
+			return fmt.Errorf("failed to delete text rendition from storage: %w", err)
+		}
+	}
+
+	return s.blobRepository.Delete(ctx, b.ID)
+}
+
+// deleteObjects removes objects uploaded for a blob that won't be stored
+func (s *BlobService) deleteObjects(ctx context.Context, b *blob.Blob) {
+	for _, key := range []string{b.ID, b.TextKey} {
+		if key == "" {
+			continue
+		}
+		if err := s.fileStorage.Delete(ctx, key); err != nil {
+			log.Printf("failed to delete file %s: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"filestoringservice/internal/domain/file"
+)
+
+// changeBufferSize is the amount of changes a subscriber may fall behind before it is dropped
+const changeBufferSize = 256
+
+// ChangeFeed broadcasts uploads and deletions of files to the subscribers of this instance.
+// Publishing never blocks: a subscriber that doesn't keep up is dropped and its channel is closed.
+type ChangeFeed struct {
+	mu          sync.Mutex
+	subscribers map[chan file.Change]struct{}
+}
+
+// NewChangeFeed creates a new change feed
+func NewChangeFeed() *ChangeFeed {
+	return &ChangeFeed{
+		subscribers: make(map[chan file.Change]struct{}),
+	}
+}
+
+// Subscribe returns the channel of the changes published from now on and the function ending the subscription.
+// The channel is closed when the subscription ends or the subscriber falls behind.
+func (f *ChangeFeed) Subscribe() (<-chan file.Change, func()) {
+	changes := make(chan file.Change, changeBufferSize)
+
+	f.mu.Lock()
+	f.subscribers[changes] = struct{}{}
+	f.mu.Unlock()
+
+	return changes, func() { f.unsubscribe(changes) }
+}
+
+// Publish reports a change of the file to all subscribers
+func (f *ChangeFeed) Publish(kind file.ChangeKind, fileModel *file.File) {
+	change := file.Change{Kind: kind, File: *fileModel, OccurredAt: time.Now()}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for changes := range f.subscribers {
+		select {
+		case changes <- change:
+		default:
+			delete(f.subscribers, changes)
+			close(changes)
+		}
+	}
+}
+
+func (f *ChangeFeed) unsubscribe(changes chan file.Change) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.subscribers[changes]; ok {
+		delete(f.subscribers, changes)
+		close(changes)
+	}
+}
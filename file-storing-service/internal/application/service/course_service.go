@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"filestoringservice/internal/domain/course"
+	"filestoringservice/internal/interfaces/repository"
+)
+
+// CourseService handles courses, their assignments and the submissions to them
+type CourseService struct {
+	courseRepository repository.CourseRepository
+	fileRepository   repository.FileRepository
+}
+
+// NewCourseService creates a new course service
+func NewCourseService(courseRepository repository.CourseRepository, fileRepository repository.FileRepository) *CourseService {
+	return &CourseService{
+		courseRepository: courseRepository,
+		fileRepository:   fileRepository,
+	}
+}
+
+// CreateCourse creates a course taught in the given year
+func (s *CourseService) CreateCourse(ctx context.Context, name string, year int) (*course.Course, error) {
+	c, err := course.NewCourse(name, year)
+	if err != nil {
+		return nil, err
+	}
+
+	c.ID = uuid.New().String()
+
+	if err := s.courseRepository.StoreCourse(ctx, c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// GetCourse retrieves a course by its ID
+func (s *CourseService) GetCourse(ctx context.Context, id string) (*course.Course, error) {
+	c, err := s.courseRepository.FindCourseByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, course.ErrCourseNotFound
+	}
+
+	return c, nil
+}
+
+// ListCourses retrieves all courses
+func (s *CourseService) ListCourses(ctx context.Context) ([]*course.Course, error) {
+	return s.courseRepository.ListCourses(ctx)
+}
+
+// CreateAssignment creates an assignment of an existing course
+func (s *CourseService) CreateAssignment(ctx context.Context, courseID, name string) (*course.Assignment, error) {
+	if _, err := s.GetCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	assignment, err := course.NewAssignment(courseID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment.ID = uuid.New().String()
+
+	if err := s.courseRepository.StoreAssignment(ctx, assignment); err != nil {
+		return nil, err
+	}
+
+	return assignment, nil
+}
+
+// GetAssignment retrieves an assignment by its ID
+func (s *CourseService) GetAssignment(ctx context.Context, id string) (*course.Assignment, error) {
+	assignment, err := s.courseRepository.FindAssignmentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if assignment == nil {
+		return nil, course.ErrAssignmentNotFound
+	}
+
+	return assignment, nil
+}
+
+// ListAssignments retrieves the assignments of an existing course
+func (s *CourseService) ListAssignments(ctx context.Context, courseID string) ([]*course.Assignment, error) {
+	if _, err := s.GetCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	return s.courseRepository.ListAssignments(ctx, courseID)
+}
+
+// ListSubmissions retrieves the files submitted to an existing assignment
+func (s *CourseService) ListSubmissions(ctx context.Context, assignmentID string) ([]*course.Submission, error) {
+	if _, err := s.GetAssignment(ctx, assignmentID); err != nil {
+		return nil, err
+	}
+
+	return s.courseRepository.ListSubmissions(ctx, assignmentID)
+}
+
+// CheckAssignment makes sure a file can be submitted to the assignment, the empty ID means no assignment
+func (s *CourseService) CheckAssignment(ctx context.Context, assignmentID string) error {
+	if assignmentID == "" {
+		return nil
+	}
+
+	_, err := s.GetAssignment(ctx, assignmentID)
+	return err
+}
+
+// ResolveScope lists the files a submitted file is checked against, the file itself is never included.
+// Scopes other than all require the file to be submitted to an assignment. Returns nil if the file doesn't exist.
+func (s *CourseService) ResolveScope(ctx context.Context, fileID string, scope course.Scope) (*course.ScopeFiles, error) {
+	fileModel, err := s.fileRepository.FindByID(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+	if fileModel == nil {
+		return nil, nil
+	}
+
+	result := &course.ScopeFiles{FileID: fileID, Scope: scope}
+
+	var assignment *course.Assignment
+	var c *course.Course
+	if fileModel.AssignmentID != "" {
+		if assignment, err = s.GetAssignment(ctx, fileModel.AssignmentID); err != nil {
+			return nil, err
+		}
+		if c, err = s.GetCourse(ctx, assignment.CourseID); err != nil {
+			return nil, err
+		}
+		result.AssignmentID, result.CourseID = assignment.ID, c.ID
+	}
+
+	if scope == course.ScopeAll {
+		return result, nil
+	}
+	if assignment == nil {
+		return nil, course.ErrNotSubmitted
+	}
+
+	var fileIDs []string
+	switch scope {
+	case course.ScopeAssignment:
+		fileIDs, err = s.courseRepository.FindAssignmentFileIDs(ctx, assignment.ID)
+	case course.ScopeCourse:
+		fileIDs, err = s.courseRepository.FindCourseFileIDs(ctx, c.ID)
+	case course.ScopePriorYears:
+		fileIDs, err = s.courseRepository.FindPriorYearsFileIDs(ctx, c.Name, c.Year)
+	default:
+		return nil, course.ErrInvalidScope
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.FileIDs = make([]string, 0, len(fileIDs))
+	for _, id := range fileIDs {
+		if id != fileID {
+			result.FileIDs = append(result.FileIDs, id)
+		}
+	}
+
+	return result, nil
+}
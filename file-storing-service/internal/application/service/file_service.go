@@ -2,38 +2,70 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/google/uuid"
+
+	"filestoringservice/internal/domain/blob"
 	"filestoringservice/internal/domain/file"
+	"filestoringservice/internal/infrastructure/fileanalysisservice"
 	"filestoringservice/internal/infrastructure/storage/s3"
+	"filestoringservice/internal/interfaces/extractor"
 	"filestoringservice/internal/interfaces/hash"
 	"filestoringservice/internal/interfaces/repository"
 )
 
+const (
+	// DeletionRetryInterval is how often unfinished deletions are retried
+	DeletionRetryInterval = time.Minute
+	// deletionRetryBatchSize limits the amount of files cleaned up per retry
+	deletionRetryBatchSize = 100
+)
+
+// ErrDeletionPending is returned when a file was deleted but its cleanup failed and will be retried
+var ErrDeletionPending = errors.New("file deleted, cleanup is pending")
+
 // FileService handles file-related business logic
 type FileService struct {
-	fileRepository repository.FileRepository
-	fileStorage    *s3.FileStorage
-	hasher         hash.Hasher
+	fileRepository      repository.FileRepository
+	blobService         *BlobService
+	fileStorage         *s3.FileStorage
+	hasher              hash.Hasher
+	textExtractor       extractor.TextExtractor
+	fileAnalysisService *fileanalysisservice.FileAnalysisService
+	courseService       *CourseService
+	changeFeed          *ChangeFeed
 }
 
 // NewFileService creates a new file service
-func NewFileService(repository repository.FileRepository, storage *s3.FileStorage, hasher hash.Hasher) *FileService {
+func NewFileService(repository repository.FileRepository, blobService *BlobService, storage *s3.FileStorage, hasher hash.Hasher, textExtractor extractor.TextExtractor, fileAnalysisService *fileanalysisservice.FileAnalysisService, courseService *CourseService, changeFeed *ChangeFeed) *FileService {
 	return &FileService{
-		fileRepository: repository,
-		fileStorage:    storage,
-		hasher:         hasher,
+		fileRepository:      repository,
+		blobService:         blobService,
+		fileStorage:         storage,
+		hasher:              hasher,
+		textExtractor:       textExtractor,
+		fileAnalysisService: fileAnalysisService,
+		courseService:       courseService,
+		changeFeed:          changeFeed,
 	}
 }
 
-// UploadFile handles file upload, stores metadata in DB and actual file in S3
-func (s *FileService) UploadFile(ctx context.Context, name, contentType string, size int64, fileData io.Reader) (*file.File, error) {
-	fileModel, err := file.NewFile(name, contentType, size)
-	if err != nil {
+// UploadFile handles file upload, stores metadata in DB, actual file and its plain-text rendition in S3.
+// Every upload gets its own file record, identical content is stored once and shared between them.
+// A file uploaded with an assignment ID is a submission to that assignment.
+func (s *FileService) UploadFile(ctx context.Context, name, contentType, uploader, assignmentID string, size int64, fileData io.Reader) (*file.File, error) {
+	if size > file.MaxFileSize {
+		return nil, errors.New("file size exceeds maximum allowed limit")
+	}
+
+	if err := s.courseService.CheckAssignment(ctx, assignmentID); err != nil {
 		return nil, err
 	}
 
@@ -45,22 +77,37 @@ func (s *FileService) UploadFile(ctx context.Context, name, contentType string,
 	defer func(name string) {
 		err := os.Remove(name)
 		if err != nil {
-			log.Printf("failed to remove temp file: %w", err)
+			log.Printf("failed to remove temp file: %v", err)
 		}
 	}(tempFile.Name())
 
 	defer func(tempFile *os.File) {
 		err := tempFile.Close()
 		if err != nil {
-			log.Printf("failed to close temp file: %w", err)
+			log.Printf("failed to close temp file: %v", err)
 		}
 	}(tempFile)
 
-	_, err = io.Copy(tempFile, fileData)
+	written, err := io.Copy(tempFile, fileData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to copy file data: %w", err)
 	}
 
+	// The declared content type is only a hint, the actual format is sniffed from the content
+	contentType, err = s.textExtractor.DetectContentType(contentType, tempFile, written)
+	if err != nil {
+		return nil, err
+	}
+
+	fileModel, err := file.NewFile(name, contentType, written)
+	if err != nil {
+		return nil, err
+	}
+
+	fileModel.ID = uuid.New().String()
+	fileModel.Uploader = uploader
+	fileModel.AssignmentID = assignmentID
+
 	fileHash, err := s.hasher.ComputeHashFromFile(ctx, tempFile.Name())
 	if err != nil {
 		return nil, fmt.Errorf("failed to compute file fileHash: %w", err)
@@ -70,10 +117,50 @@ func (s *FileService) UploadFile(ctx context.Context, name, contentType string,
 		return nil, fmt.Errorf("failed to set file fileHash: %w", err)
 	}
 
-	existingFile, err := s.fileRepository.FindByHash(ctx, fileHash)
-	if err == nil && existingFile != nil {
-		log.Printf("file with hash %s already exists", existingFile.Hash)
-		return existingFile, nil
+	contentBlob, err := s.blobService.FindByHash(ctx, fileHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if contentBlob != nil {
+		log.Printf("content with hash %s is already stored", fileHash)
+	} else {
+		contentBlob, err = s.storeBlob(ctx, fileModel, tempFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.blobService.AttachFile(ctx, fileModel, contentBlob)
+	if errors.Is(err, blob.ErrReleased) {
+		// The last file referencing the content was deleted after the lookup, the content is stored again
+		log.Printf("content with hash %s has been released, storing it again", fileHash)
+		contentBlob, err = s.storeBlob(ctx, fileModel, tempFile)
+		if err != nil {
+			return nil, err
+		}
+		err = s.blobService.AttachFile(ctx, fileModel, contentBlob)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.changeFeed.Publish(file.ChangeCreated, fileModel)
+
+	return fileModel, nil
+}
+
+// storeBlob uploads new content with its plain-text rendition and registers it as a blob
+func (s *FileService) storeBlob(ctx context.Context, fileModel *file.File, tempFile *os.File) (*blob.Blob, error) {
+	contentBlob, err := blob.NewBlob(fileModel.Hash, fileModel.ContentType, fileModel.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract text before uploading anything so that unreadable documents leave nothing behind
+	text, err := s.textExtractor.ExtractText(ctx, fileModel.ContentType, tempFile, fileModel.Size)
+	if err != nil {
+		return nil, err
 	}
 
 	_, err = tempFile.Seek(0, 0)
@@ -86,15 +173,19 @@ func (s *FileService) UploadFile(ctx context.Context, name, contentType string,
 		return nil, fmt.Errorf("failed to upload file to storage: %w", err)
 	}
 
-	fileModel.ID = fileInfo.ID
-	fileModel.Location = fileInfo.Location
-
-	err = s.fileRepository.Store(ctx, fileModel)
+	textInfo, err := s.fileStorage.UploadText(ctx, fileInfo.ID, text)
 	if err != nil {
-		return nil, fmt.Errorf("failed to store file metadata: %w", err)
+		if deleteErr := s.fileStorage.Delete(ctx, fileInfo.ID); deleteErr != nil {
+			log.Printf("failed to delete file %s: %v", fileInfo.ID, deleteErr)
+		}
+		return nil, fmt.Errorf("failed to upload text rendition to storage: %w", err)
 	}
 
-	return fileModel, nil
+	if err := contentBlob.SetStorage(fileInfo.ID, fileInfo.Location, textInfo.ID); err != nil {
+		return nil, fmt.Errorf("failed to set blob storage: %w", err)
+	}
+
+	return s.blobService.Register(ctx, contentBlob)
 }
 
 // GetFileByID retrieves a file by its ID
@@ -102,9 +193,13 @@ func (s *FileService) GetFileByID(ctx context.Context, id string) (*file.File, e
 	return s.fileRepository.FindByID(ctx, id)
 }
 
-// GetAllFiles retrieves all files
-func (s *FileService) GetAllFiles(ctx context.Context) ([]*file.File, error) {
-	return s.fileRepository.FindAll(ctx)
+// ListFiles retrieves a page of files matching the query
+func (s *FileService) ListFiles(ctx context.Context, query file.ListQuery) (*file.Page, error) {
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	return s.fileRepository.List(ctx, query)
 }
 
 // DownloadFile retrieves a file's content from storage
@@ -115,13 +210,122 @@ func (s *FileService) DownloadFile(ctx context.Context, id string) (io.ReadClose
 	}
 
 	if fileModel == nil {
-		return nil, nil, fmt.Errorf("file not found")
+		return nil, nil, file.ErrNotFound
 	}
 
-	fileReader, err := s.fileStorage.Download(ctx, fileModel.ID)
+	fileReader, err := s.fileStorage.Download(ctx, fileModel.BlobID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to download file from storage: %w", err)
 	}
 
 	return fileReader, fileModel, nil
 }
+
+// DownloadText retrieves the normalized plain-text rendition of a file from storage
+func (s *FileService) DownloadText(ctx context.Context, id string) (io.ReadCloser, *file.File, error) {
+	fileModel, err := s.fileRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get file metadata: %w", err)
+	}
+
+	if fileModel == nil {
+		return nil, nil, file.ErrNotFound
+	}
+
+	// Plain text files uploaded before renditions were introduced are served as is
+	textKey := fileModel.TextKey
+	if textKey == "" {
+		textKey = fileModel.BlobID
+	}
+
+	textReader, err := s.fileStorage.Download(ctx, textKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to download text from storage: %w", err)
+	}
+
+	return textReader, fileModel, nil
+}
+
+// DeleteFile hides a file at once and removes its analysis data and the reference to its content.
+// The content itself is removed from storage once no other file references it.
+// Returns nil file if it doesn't exist, ErrDeletionPending if the cleanup has to be retried later.
+func (s *FileService) DeleteFile(ctx context.Context, id string) (*file.File, error) {
+	fileModel, err := s.fileRepository.MarkDeleted(ctx, id, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark file as deleted: %w", err)
+	}
+
+	if fileModel == nil {
+		return nil, nil
+	}
+
+	s.changeFeed.Publish(file.ChangeDeleted, fileModel)
+
+	err = s.purgeFile(ctx, fileModel)
+	if err != nil {
+		log.Printf("failed to clean up deleted file %s: %v", fileModel.ID, err)
+		return fileModel, fmt.Errorf("%w: %v", ErrDeletionPending, err)
+	}
+
+	return fileModel, nil
+}
+
+// RetryPendingDeletions finishes the cleanup of files whose deletion previously failed
+func (s *FileService) RetryPendingDeletions(ctx context.Context) error {
+	// Recent deletions are skipped as they may still be cleaned up by the request that started them
+	files, err := s.fileRepository.FindPendingDeletions(ctx, time.Now().Add(-DeletionRetryInterval), deletionRetryBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get pending deletions: %w", err)
+	}
+
+	for _, fileModel := range files {
+		err = s.purgeFile(ctx, fileModel)
+		if err != nil {
+			log.Printf("failed to clean up deleted file %s: %v", fileModel.ID, err)
+			continue
+		}
+
+		log.Printf("cleaned up deleted file %s", fileModel.ID)
+	}
+
+	// Content of files deleted earlier may still be left in storage
+	return s.blobService.PurgeReleased(ctx)
+}
+
+// RunDeletionRetries retries pending deletions every DeletionRetryInterval until the context is done
+func (s *FileService) RunDeletionRetries(ctx context.Context) {
+	ticker := time.NewTicker(DeletionRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RetryPendingDeletions(ctx); err != nil {
+				log.Printf("failed to retry pending deletions: %v", err)
+			}
+		}
+	}
+}
+
+// purgeFile removes everything stored for a deleted file, the metadata row goes after the analysis data
+// so that a failure at any step leaves the file pending. Every step is idempotent and safe to repeat.
+func (s *FileService) purgeFile(ctx context.Context, fileModel *file.File) error {
+	err := s.fileAnalysisService.DeleteFileData(ctx, fileModel.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete analysis data: %w", err)
+	}
+
+	err = s.fileRepository.Delete(ctx, fileModel.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete file metadata: %w", err)
+	}
+
+	err = s.blobService.PurgeReleased(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete file content: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"filestoringservice/internal/interfaces/broker"
+	"filestoringservice/internal/interfaces/repository"
+)
+
+const (
+	// OutboxPollInterval is how often the relay looks for events to deliver
+	OutboxPollInterval = time.Second
+	// outboxBatchSize limits the amount of events claimed per poll
+	outboxBatchSize = 100
+)
+
+// OutboxRelay delivers the events written to the outbox with the changes they report.
+// Events are delivered at least once, a failed delivery is retried with backoff until it is accepted.
+type OutboxRelay struct {
+	outboxRepository repository.OutboxRepository
+	publisher        broker.Publisher
+}
+
+// NewOutboxRelay creates a new outbox relay
+func NewOutboxRelay(outboxRepository repository.OutboxRepository, publisher broker.Publisher) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepository: outboxRepository,
+		publisher:        publisher,
+	}
+}
+
+// Run delivers due events until the context is done
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(OutboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// A full batch means more events are waiting, they are delivered without waiting for the next tick
+		claimed, err := r.DeliverDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("failed to deliver outbox events: %v", err)
+		}
+		if claimed == outboxBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue publishes a batch of due events and returns the amount of events claimed
+func (r *OutboxRelay) DeliverDue(ctx context.Context) (int, error) {
+	events, err := r.outboxRepository.ClaimDue(ctx, time.Now(), outboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		err = r.publisher.Publish(ctx, event)
+		if err != nil {
+			if ctx.Err() != nil {
+				// The claim expires and the event is delivered after the restart
+				return len(events), ctx.Err()
+			}
+
+			log.Printf("failed to deliver event %s: %v", event.ID, err)
+			event.Fail(err, time.Now())
+			if err := r.outboxRepository.Update(ctx, event); err != nil {
+				log.Printf("failed to reschedule event %s: %v", event.ID, err)
+			}
+			continue
+		}
+
+		if err := r.outboxRepository.Delete(ctx, event.ID); err != nil {
+			// The event is delivered again once its claim expires, consumers ignore duplicates
+			log.Printf("failed to remove delivered event %s: %v", event.ID, err)
+		}
+	}
+
+	return len(events), nil
+}
@@ -0,0 +1,451 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"filestoringservice/internal/domain/blob"
+	"filestoringservice/internal/domain/file"
+	"filestoringservice/internal/domain/upload"
+	"filestoringservice/internal/infrastructure/storage/s3"
+	"filestoringservice/internal/interfaces/extractor"
+	"filestoringservice/internal/interfaces/hash"
+	"filestoringservice/internal/interfaces/repository"
+)
+
+// UploadService handles resumable chunked uploads backed by S3 multipart uploads
+type UploadService struct {
+	sessionRepository repository.UploadSessionRepository
+	blobService       *BlobService
+	fileStorage       *s3.FileStorage
+	hasher            hash.Hasher
+	textExtractor     extractor.TextExtractor
+	courseService     *CourseService
+	changeFeed        *ChangeFeed
+
+	mu      sync.Mutex
+	hashers map[string]*sessionHasher
+}
+
+// sessionHasher keeps the incremental hash of the chunks received in order.
+// It lives in memory only, so after a restart the hash is recomputed from S3 on completion.
+type sessionHasher struct {
+	mu       sync.Mutex
+	hasher   hash.StreamHasher
+	nextPart int
+	valid    bool
+}
+
+// NewUploadService creates a new upload service
+func NewUploadService(sessionRepository repository.UploadSessionRepository, blobService *BlobService, storage *s3.FileStorage, hasher hash.Hasher, textExtractor extractor.TextExtractor, courseService *CourseService, changeFeed *ChangeFeed) *UploadService {
+	return &UploadService{
+		sessionRepository: sessionRepository,
+		blobService:       blobService,
+		fileStorage:       storage,
+		hasher:            hasher,
+		textExtractor:     textExtractor,
+		courseService:     courseService,
+		changeFeed:        changeFeed,
+		hashers:           make(map[string]*sessionHasher),
+	}
+}
+
+// CreateSession validates the file metadata and starts a new upload session
+func (s *UploadService) CreateSession(ctx context.Context, name, contentType, uploader, assignmentID string, size int64) (*upload.Session, error) {
+	session, err := upload.NewSession(name, contentType, size)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.courseService.CheckAssignment(ctx, assignmentID); err != nil {
+		return nil, err
+	}
+
+	session.Uploader = uploader
+	session.AssignmentID = assignmentID
+
+	multipartUpload, err := s.fileStorage.CreateMultipartUpload(ctx, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start upload in storage: %w", err)
+	}
+
+	session.ID = uuid.New().String()
+	session.StorageKey = multipartUpload.Key
+	session.StorageUploadID = multipartUpload.UploadID
+
+	err = s.sessionRepository.Store(ctx, session)
+	if err != nil {
+		if abortErr := s.fileStorage.AbortMultipartUpload(ctx, multipartUpload); abortErr != nil {
+			log.Printf("failed to abort multipart upload %s: %v", multipartUpload.UploadID, abortErr)
+		}
+		return nil, fmt.Errorf("failed to store upload session: %w", err)
+	}
+
+	s.mu.Lock()
+	s.hashers[session.ID] = &sessionHasher{hasher: s.hasher.NewStreamHasher(), nextPart: 1, valid: true}
+	s.mu.Unlock()
+
+	return session, nil
+}
+
+// GetSession retrieves an upload session with the list of received chunks
+func (s *UploadService) GetSession(ctx context.Context, id string) (*upload.Session, error) {
+	return s.sessionRepository.FindByID(ctx, id)
+}
+
+// UploadChunk stores a numbered chunk as a multipart upload part and feeds it to the incremental hash
+func (s *UploadService) UploadChunk(ctx context.Context, id string, number int, data io.Reader) (*upload.Session, error) {
+	session, err := s.findOpenSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read one byte more than allowed to detect oversized chunks
+	chunk, err := io.ReadAll(io.LimitReader(data, session.ChunkSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chunk: %w", err)
+	}
+
+	if err := session.ValidateChunk(number, int64(len(chunk))); err != nil {
+		return nil, err
+	}
+
+	previous, resent := session.Part(number)
+
+	etag, err := s.fileStorage.UploadPart(ctx, storageUpload(session), number, bytes.NewReader(chunk))
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload chunk to storage: %w", err)
+	}
+
+	part := upload.Part{
+		Number:     number,
+		Size:       int64(len(chunk)),
+		ETag:       etag,
+		UploadedAt: time.Now(),
+	}
+
+	err = s.sessionRepository.StorePart(ctx, session.ID, part)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store chunk metadata: %w", err)
+	}
+
+	// The ETag of a part is the MD5 of its content, so a resent identical chunk keeps the hash valid
+	s.feedHasher(session.ID, number, chunk, resent && previous.ETag != etag)
+
+	session.AddPart(part)
+	return session, nil
+}
+
+// CompleteSession assembles the uploaded chunks into a file, extracts its text and stores its metadata.
+// Content that is already stored is shared with the new file record instead of being stored again.
+func (s *UploadService) CompleteSession(ctx context.Context, id string) (*file.File, error) {
+	session, err := s.findOpenSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(session.MissingChunks()) > 0 {
+		return nil, upload.ErrIncomplete
+	}
+
+	fileHash, hashed := s.takeHash(session)
+
+	// When the hash is already known duplicates can be detected without assembling the object.
+	// The parts are dropped only once the file references the stored content: if the content
+	// has been released meanwhile, the upload is completed and stored as new content instead.
+	if hashed {
+		contentBlob, err := s.blobService.FindByHash(ctx, fileHash)
+		if err != nil {
+			return nil, err
+		}
+		if contentBlob != nil {
+			log.Printf("content with hash %s is already stored", fileHash)
+			fileModel, err := s.attachFile(ctx, session, contentBlob)
+			if !errors.Is(err, blob.ErrReleased) {
+				if fileModel != nil {
+					if abortErr := s.fileStorage.AbortMultipartUpload(ctx, storageUpload(session)); abortErr != nil {
+						log.Printf("failed to abort multipart upload %s: %v", session.StorageUploadID, abortErr)
+					}
+				}
+				return fileModel, err
+			}
+			log.Printf("content with hash %s has been released, storing the upload again", fileHash)
+		}
+	}
+
+	parts := make([]s3.CompletedPart, len(session.Parts))
+	for i, part := range session.Parts {
+		parts[i] = s3.CompletedPart{Number: part.Number, ETag: part.ETag}
+	}
+
+	fileInfo, err := s.fileStorage.CompleteMultipartUpload(ctx, storageUpload(session), parts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete upload in storage: %w", err)
+	}
+
+	// The assembled object is read back once for hashing (if needed) and text extraction
+	tempFile, err := s.downloadToTempFile(ctx, fileInfo.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(tempFile *os.File) {
+		if err := tempFile.Close(); err != nil {
+			log.Printf("failed to close temp file: %v", err)
+		}
+		if err := os.Remove(tempFile.Name()); err != nil {
+			log.Printf("failed to remove temp file: %v", err)
+		}
+	}(tempFile)
+
+	if !hashed {
+		fileHash, err = s.hasher.ComputeHashFromFile(ctx, tempFile.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute file hash: %w", err)
+		}
+
+		contentBlob, err := s.blobService.FindByHash(ctx, fileHash)
+		if err != nil {
+			return nil, err
+		}
+		if contentBlob != nil {
+			log.Printf("content with hash %s is already stored", fileHash)
+			fileModel, err := s.attachFile(ctx, session, contentBlob)
+			if !errors.Is(err, blob.ErrReleased) {
+				s.deleteStoredFile(ctx, fileInfo.ID)
+				return fileModel, err
+			}
+			log.Printf("content with hash %s has been released, storing the upload again", fileHash)
+		}
+	}
+
+	contentBlob, text, err := s.extractDocument(ctx, session, fileHash, tempFile)
+	if err != nil {
+		// The parts are already assembled, so the session can't be resumed anymore
+		s.deleteStoredFile(ctx, fileInfo.ID)
+		if abortErr := session.Abort(); abortErr == nil {
+			if updateErr := s.sessionRepository.Update(ctx, session); updateErr != nil {
+				log.Printf("failed to update upload session %s: %v", session.ID, updateErr)
+			}
+		}
+		s.dropHasher(session.ID)
+		return nil, err
+	}
+
+	textInfo, err := s.fileStorage.UploadText(ctx, fileInfo.ID, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload text rendition to storage: %w", err)
+	}
+
+	if err := contentBlob.SetStorage(fileInfo.ID, fileInfo.Location, textInfo.ID); err != nil {
+		return nil, fmt.Errorf("failed to set blob storage: %w", err)
+	}
+
+	contentBlob, err = s.blobService.Register(ctx, contentBlob)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.attachFile(ctx, session, contentBlob)
+}
+
+// attachFile creates the file record of a finished session referencing the stored content.
+// The file is returned once it is stored, even if the session couldn't be closed.
+func (s *UploadService) attachFile(ctx context.Context, session *upload.Session, contentBlob *blob.Blob) (*file.File, error) {
+	fileModel, err := file.NewFile(session.FileName, contentBlob.ContentType, session.TotalSize)
+	if err != nil {
+		return nil, err
+	}
+
+	fileModel.ID = uuid.New().String()
+	fileModel.Uploader = session.Uploader
+	fileModel.AssignmentID = session.AssignmentID
+
+	err = s.blobService.AttachFile(ctx, fileModel, contentBlob)
+	if err != nil {
+		return nil, err
+	}
+
+	s.changeFeed.Publish(file.ChangeCreated, fileModel)
+
+	return fileModel, s.closeSession(ctx, session, fileModel.ID)
+}
+
+// AbortSession discards an unfinished upload session and its uploaded chunks
+func (s *UploadService) AbortSession(ctx context.Context, id string) error {
+	session, err := s.sessionRepository.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get upload session: %w", err)
+	}
+	if session == nil {
+		return upload.ErrSessionNotFound
+	}
+
+	if err := session.Abort(); err != nil {
+		return err
+	}
+
+	if err := s.fileStorage.AbortMultipartUpload(ctx, storageUpload(session)); err != nil {
+		return fmt.Errorf("failed to abort upload in storage: %w", err)
+	}
+
+	s.dropHasher(session.ID)
+
+	return s.sessionRepository.Update(ctx, session)
+}
+
+// findOpenSession retrieves a session that still accepts chunks
+func (s *UploadService) findOpenSession(ctx context.Context, id string) (*upload.Session, error) {
+	session, err := s.sessionRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upload session: %w", err)
+	}
+	if session == nil {
+		return nil, upload.ErrSessionNotFound
+	}
+
+	if err := session.CanAcceptChunks(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// closeSession marks the session as completed
+func (s *UploadService) closeSession(ctx context.Context, session *upload.Session, fileID string) error {
+	s.dropHasher(session.ID)
+
+	if err := session.Complete(fileID); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepository.Update(ctx, session); err != nil {
+		return fmt.Errorf("failed to update upload session: %w", err)
+	}
+
+	return nil
+}
+
+// feedHasher writes the chunk to the session hash if it is the next one in order
+func (s *UploadService) feedHasher(sessionID string, number int, chunk []byte, changed bool) {
+	s.mu.Lock()
+	h, ok := s.hashers[sessionID]
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch {
+	case !h.valid:
+	case number == h.nextPart:
+		_, _ = h.hasher.Write(chunk)
+		h.nextPart++
+	case number < h.nextPart && !changed:
+		// Identical chunk sent again, nothing to do
+	default:
+		// Out of order or modified chunk, the hash is recomputed on completion
+		h.valid = false
+	}
+}
+
+// takeHash returns the incremental hash if it covers every chunk of the session
+func (s *UploadService) takeHash(session *upload.Session) (string, bool) {
+	s.mu.Lock()
+	h, ok := s.hashers[session.ID]
+	s.mu.Unlock()
+	if !ok {
+		return "", false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.valid || h.nextPart != session.TotalChunks()+1 {
+		return "", false
+	}
+	return h.hasher.Sum(), true
+}
+
+// dropHasher forgets the incremental hash of a closed session
+func (s *UploadService) dropHasher(sessionID string) {
+	s.mu.Lock()
+	delete(s.hashers, sessionID)
+	s.mu.Unlock()
+}
+
+// downloadToTempFile copies a stored object into a temporary file
+func (s *UploadService) downloadToTempFile(ctx context.Context, fileKey string) (*os.File, error) {
+	reader, err := s.fileStorage.Download(ctx, fileKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			log.Printf("failed to close file reader: %v", err)
+		}
+	}()
+
+	tempFile, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	if _, err := io.Copy(tempFile, reader); err != nil {
+		_ = tempFile.Close()
+		_ = os.Remove(tempFile.Name())
+		return nil, fmt.Errorf("failed to copy file data: %w", err)
+	}
+
+	return tempFile, nil
+}
+
+// extractDocument detects the actual document format and extracts its plain text
+func (s *UploadService) extractDocument(ctx context.Context, session *upload.Session, fileHash string, data io.ReaderAt) (*blob.Blob, string, error) {
+	contentType, err := s.textExtractor.DetectContentType(session.ContentType, data, session.TotalSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Validates the detected format the same way as for regular uploads
+	if _, err := file.NewFile(session.FileName, contentType, session.TotalSize); err != nil {
+		return nil, "", err
+	}
+
+	contentBlob, err := blob.NewBlob(fileHash, file.NormalizeContentType(contentType), session.TotalSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	text, err := s.textExtractor.ExtractText(ctx, contentType, data, session.TotalSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return contentBlob, text, nil
+}
+
+// deleteStoredFile removes an assembled object that won't be referenced by any blob
+func (s *UploadService) deleteStoredFile(ctx context.Context, fileKey string) {
+	if err := s.fileStorage.Delete(ctx, fileKey); err != nil {
+		log.Printf("failed to delete file %s: %v", fileKey, err)
+	}
+}
+
+// storageUpload returns the S3 multipart upload backing the session
+func storageUpload(session *upload.Session) *s3.MultipartUpload {
+	return &s3.MultipartUpload{
+		Key:      session.StorageKey,
+		UploadID: session.StorageUploadID,
+	}
+}
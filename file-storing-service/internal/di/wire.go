@@ -4,23 +4,38 @@
 package di
 
 import (
+	brokerRealizations "filestoringservice/internal/infrastructure/broker"
+	extractorRealizations "filestoringservice/internal/infrastructure/extractor"
 	hashRealizations "filestoringservice/internal/infrastructure/hash"
+	brokerInterface "filestoringservice/internal/interfaces/broker"
+	extractorInterface "filestoringservice/internal/interfaces/extractor"
 	hashInterface "filestoringservice/internal/interfaces/hash"
 	"filestoringservice/internal/interfaces/repository"
 	"github.com/google/wire"
 
 	"filestoringservice/internal/application/service"
 	"filestoringservice/internal/infrastructure/config"
+	"filestoringservice/internal/infrastructure/fileanalysisservice"
 	"filestoringservice/internal/infrastructure/persistence/postgres"
 	"filestoringservice/internal/infrastructure/storage/s3"
 	"filestoringservice/internal/interfaces/api/handler"
 	"filestoringservice/internal/interfaces/api/router"
+	grpcServer "filestoringservice/internal/interfaces/grpc/server"
+	"google.golang.org/grpc"
 )
 
 // RepositorySet provides repository implementations
 var RepositorySet = wire.NewSet(
 	postgres.NewFileRepository,
 	wire.Bind(new(repository.FileRepository), new(*postgres.FileRepository)),
+	postgres.NewBlobRepository,
+	wire.Bind(new(repository.BlobRepository), new(*postgres.BlobRepository)),
+	postgres.NewUploadSessionRepository,
+	wire.Bind(new(repository.UploadSessionRepository), new(*postgres.UploadSessionRepository)),
+	postgres.NewCourseRepository,
+	wire.Bind(new(repository.CourseRepository), new(*postgres.CourseRepository)),
+	postgres.NewOutboxRepository,
+	wire.Bind(new(repository.OutboxRepository), new(*postgres.OutboxRepository)),
 )
 
 var HasherSet = wire.NewSet(
@@ -28,6 +43,16 @@ var HasherSet = wire.NewSet(
 	wire.Bind(new(hashInterface.Hasher), new(*hashRealizations.BLAKE3Hasher)),
 )
 
+var PublisherSet = wire.NewSet(
+	brokerRealizations.NewHTTPPublisher,
+	wire.Bind(new(brokerInterface.Publisher), new(*brokerRealizations.HTTPPublisher)),
+)
+
+var ExtractorSet = wire.NewSet(
+	extractorRealizations.NewRegistry,
+	wire.Bind(new(extractorInterface.TextExtractor), new(*extractorRealizations.Registry)),
+)
+
 // InitializeApplication wires up all the dependencies
 func InitializeApplication() (*Application, error) {
 	wire.Build(
@@ -37,6 +62,9 @@ func InitializeApplication() (*Application, error) {
 		// Hasher
 		HasherSet,
 
+		// Text extractors
+		ExtractorSet,
+
 		// Databases.
 		postgres.NewDB,
 
@@ -46,17 +74,34 @@ func InitializeApplication() (*Application, error) {
 		// Storages.
 		s3.NewFileStorage,
 
+		// External services.
+		fileanalysisservice.NewFileAnalysisService,
+
+		// Event publisher
+		PublisherSet,
+
 		// Services.
+		service.NewChangeFeed,
+		service.NewBlobService,
+		service.NewCourseService,
 		service.NewFileService,
+		service.NewUploadService,
+		service.NewOutboxRelay,
 
 		// Handlers.
 		handler.NewFileHandler,
+		handler.NewUploadHandler,
+		handler.NewCourseHandler,
 		handler.NewInfoHandler,
 		handler.NewDocsHandler,
 
 		// Routers.
 		router.NewRouter,
 
+		// gRPC API.
+		grpcServer.NewFileStorageServer,
+		grpcServer.NewServer,
+
 		// Application.
 		NewApplication,
 	)
@@ -66,14 +111,20 @@ func InitializeApplication() (*Application, error) {
 
 // Application is the main application container
 type Application struct {
-	Router *router.Router
-	Config *config.Config
+	Router      *router.Router
+	GRPCServer  *grpc.Server
+	Config      *config.Config
+	FileService *service.FileService
+	OutboxRelay *service.OutboxRelay
 }
 
 // NewApplication creates a new application
-func NewApplication(router *router.Router, config *config.Config) *Application {
+func NewApplication(router *router.Router, grpcServer *grpc.Server, config *config.Config, fileService *service.FileService, outboxRelay *service.OutboxRelay) *Application {
 	return &Application{
-		Router: router,
-		Config: config,
+		Router:      router,
+		GRPCServer:  grpcServer,
+		Config:      config,
+		FileService: fileService,
+		OutboxRelay: outboxRelay,
 	}
 }
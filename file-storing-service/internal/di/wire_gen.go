@@ -8,15 +8,22 @@ package di
 
 import (
 	"filestoringservice/internal/application/service"
+	"filestoringservice/internal/infrastructure/broker"
 	"filestoringservice/internal/infrastructure/config"
+	"filestoringservice/internal/infrastructure/extractor"
+	"filestoringservice/internal/infrastructure/fileanalysisservice"
 	"filestoringservice/internal/infrastructure/hash"
 	"filestoringservice/internal/infrastructure/persistence/postgres"
 	"filestoringservice/internal/infrastructure/storage/s3"
 	"filestoringservice/internal/interfaces/api/handler"
 	"filestoringservice/internal/interfaces/api/router"
+	broker2 "filestoringservice/internal/interfaces/broker"
+	extractor2 "filestoringservice/internal/interfaces/extractor"
+	"filestoringservice/internal/interfaces/grpc/server"
 	hash2 "filestoringservice/internal/interfaces/hash"
 	"filestoringservice/internal/interfaces/repository"
 	"github.com/google/wire"
+	"google.golang.org/grpc"
 )
 
 // Injectors from wire.go:
@@ -32,37 +39,63 @@ func InitializeApplication() (*Application, error) {
 		return nil, err
 	}
 	fileRepository := postgres.NewFileRepository(db)
+	blobRepository := postgres.NewBlobRepository(db)
 	fileStorage, err := s3.NewFileStorage(configConfig)
 	if err != nil {
 		return nil, err
 	}
+	blobService := service.NewBlobService(blobRepository, fileRepository, fileStorage)
 	blake3Hasher := hash.NewBLAKE3Hasher()
-	fileService := service.NewFileService(fileRepository, fileStorage, blake3Hasher)
+	registry := extractor.NewRegistry()
+	fileAnalysisService := fileanalysisservice.NewFileAnalysisService(configConfig)
+	courseRepository := postgres.NewCourseRepository(db)
+	courseService := service.NewCourseService(courseRepository, fileRepository)
+	changeFeed := service.NewChangeFeed()
+	fileService := service.NewFileService(fileRepository, blobService, fileStorage, blake3Hasher, registry, fileAnalysisService, courseService, changeFeed)
 	fileHandler := handler.NewFileHandler(fileService)
+	uploadSessionRepository := postgres.NewUploadSessionRepository(db)
+	uploadService := service.NewUploadService(uploadSessionRepository, blobService, fileStorage, blake3Hasher, registry, courseService, changeFeed)
+	uploadHandler := handler.NewUploadHandler(uploadService)
+	courseHandler := handler.NewCourseHandler(courseService)
 	infoHandler := handler.NewInfoHandler()
 	docsHandler := handler.NewDocsHandler()
-	routerRouter := router.NewRouter(fileHandler, infoHandler, docsHandler)
-	application := NewApplication(routerRouter, configConfig)
+	routerRouter := router.NewRouter(fileHandler, uploadHandler, courseHandler, infoHandler, docsHandler)
+	fileStorageServer := server.NewFileStorageServer(fileService, courseService, changeFeed)
+	grpcServer := server.NewServer(fileStorageServer)
+	outboxRepository := postgres.NewOutboxRepository(db)
+	httpPublisher := broker.NewHTTPPublisher(configConfig)
+	outboxRelay := service.NewOutboxRelay(outboxRepository, httpPublisher)
+	application := NewApplication(routerRouter, grpcServer, configConfig, fileService, outboxRelay)
 	return application, nil
 }
 
 // wire.go:
 
 // RepositorySet provides repository implementations
-var RepositorySet = wire.NewSet(postgres.NewFileRepository, wire.Bind(new(repository.FileRepository), new(*postgres.FileRepository)))
+var RepositorySet = wire.NewSet(postgres.NewFileRepository, wire.Bind(new(repository.FileRepository), new(*postgres.FileRepository)), postgres.NewBlobRepository, wire.Bind(new(repository.BlobRepository), new(*postgres.BlobRepository)), postgres.NewUploadSessionRepository, wire.Bind(new(repository.UploadSessionRepository), new(*postgres.UploadSessionRepository)), postgres.NewCourseRepository, wire.Bind(new(repository.CourseRepository), new(*postgres.CourseRepository)), postgres.NewOutboxRepository, wire.Bind(new(repository.OutboxRepository), new(*postgres.OutboxRepository)))
 
 var HasherSet = wire.NewSet(hash.NewBLAKE3Hasher, wire.Bind(new(hash2.Hasher), new(*hash.BLAKE3Hasher)))
 
+var PublisherSet = wire.NewSet(broker.NewHTTPPublisher, wire.Bind(new(broker2.Publisher), new(*broker.HTTPPublisher)))
+
+var ExtractorSet = wire.NewSet(extractor.NewRegistry, wire.Bind(new(extractor2.TextExtractor), new(*extractor.Registry)))
+
 // Application is the main application container
 type Application struct {
-	Router *router.Router
-	Config *config.Config
+	Router      *router.Router
+	GRPCServer  *grpc.Server
+	Config      *config.Config
+	FileService *service.FileService
+	OutboxRelay *service.OutboxRelay
 }
 
 // NewApplication creates a new application
-func NewApplication(router2 *router.Router, config2 *config.Config) *Application {
+func NewApplication(router2 *router.Router, grpcServer *grpc.Server, config2 *config.Config, fileService *service.FileService, outboxRelay *service.OutboxRelay) *Application {
 	return &Application{
-		Router: router2,
-		Config: config2,
+		Router:      router2,
+		GRPCServer:  grpcServer,
+		Config:      config2,
+		FileService: fileService,
+		OutboxRelay: outboxRelay,
 	}
 }
@@ -0,0 +1,62 @@
+package blob
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrReleased is returned when a file is attached to a blob that has lost all its references
+var ErrReleased = errors.New("content blob has been released")
+
+// Blob represents stored file content shared by every file with the same hash
+type Blob struct {
+	ID          string // Key of the original content in storage
+	Hash        string
+	Size        int64
+	ContentType string
+	Location    string
+	TextKey     string // Key of the normalized plain-text rendition in storage
+	RefCount    int
+	ReleasedAt  *time.Time // Set once the last file referencing the blob is deleted
+	UpdatedAt   time.Time
+	CreatedAt   time.Time
+}
+
+// NewBlob creates a new Blob domain entity, it has no references until a file is attached
+func NewBlob(hash, contentType string, size int64) (*Blob, error) {
+	if hash == "" {
+		return nil, errors.New("hash cannot be empty")
+	}
+	if size <= 0 {
+		return nil, errors.New("blob size must be greater than zero")
+	}
+
+	now := time.Now()
+	return &Blob{
+		Hash:        hash,
+		Size:        size,
+		ContentType: contentType,
+		UpdatedAt:   now,
+		CreatedAt:   now,
+	}, nil
+}
+
+// SetStorage sets the storage keys and location of the content and its text rendition
+func (b *Blob) SetStorage(id, location, textKey string) error {
+	if id == "" {
+		return errors.New("storage key cannot be empty")
+	}
+	if textKey == "" {
+		return errors.New("text key cannot be empty")
+	}
+	b.ID = id
+	b.Location = location
+	b.TextKey = textKey
+	b.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsReleased reports whether no file references the blob anymore
+func (b *Blob) IsReleased() bool {
+	return b.ReleasedAt != nil
+}
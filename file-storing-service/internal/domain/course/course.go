@@ -0,0 +1,111 @@
+package course
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCourseNotFound     = errors.New("course not found")
+	ErrAssignmentNotFound = errors.New("assignment not found")
+	ErrNotSubmitted       = errors.New("file is not submitted to an assignment")
+	ErrInvalidScope       = errors.New("invalid search scope")
+)
+
+const (
+	// MinYear and MaxYear bound the academic year of a course
+	MinYear = 1900
+	MaxYear = 9999
+)
+
+// Scope is the set of submissions a file is checked against
+type Scope string
+
+const (
+	ScopeAssignment Scope = "assignment"  // Работы того же задания
+	ScopeCourse     Scope = "course"      // Работы всех заданий курса
+	ScopeAll        Scope = "all"         // Все загруженные файлы
+	ScopePriorYears Scope = "prior_years" // Работы курса с тем же названием за прошлые годы
+)
+
+// ParseScope validates a scope received from a client, the empty scope means all files
+func ParseScope(value string) (Scope, error) {
+	switch scope := Scope(value); scope {
+	case "":
+		return ScopeAll, nil
+	case ScopeAssignment, ScopeCourse, ScopeAll, ScopePriorYears:
+		return scope, nil
+	default:
+		return "", fmt.Errorf("%w: scope must be one of: %s, %s, %s, %s", ErrInvalidScope, ScopeAssignment, ScopeCourse, ScopeAll, ScopePriorYears)
+	}
+}
+
+// Course represents a course taught in a given year, every year gets its own course
+type Course struct {
+	ID        string
+	Name      string
+	Year      int
+	CreatedAt time.Time
+}
+
+// NewCourse creates a new Course domain entity
+func NewCourse(name string, year int) (*Course, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("course name cannot be empty")
+	}
+	if year < MinYear || year > MaxYear {
+		return nil, fmt.Errorf("course year must be between %d and %d", MinYear, MaxYear)
+	}
+
+	return &Course{
+		Name:      name,
+		Year:      year,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Assignment represents a task of a course students submit files to
+type Assignment struct {
+	ID        string
+	CourseID  string
+	Name      string
+	CreatedAt time.Time
+}
+
+// NewAssignment creates a new Assignment domain entity
+func NewAssignment(courseID, name string) (*Assignment, error) {
+	if courseID == "" {
+		return nil, errors.New("course ID cannot be empty")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("assignment name cannot be empty")
+	}
+
+	return &Assignment{
+		CourseID:  courseID,
+		Name:      name,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Submission is a file uploaded by a student to an assignment
+type Submission struct {
+	FileID       string
+	FileName     string
+	AssignmentID string
+	Student      string
+	SubmittedAt  time.Time
+}
+
+// ScopeFiles is the resolved search scope of a submitted file
+type ScopeFiles struct {
+	FileID       string
+	Scope        Scope
+	AssignmentID string   // Empty if the file is not submitted to an assignment
+	CourseID     string   // Empty if the file is not submitted to an assignment
+	FileIDs      []string // Other files of the scope, nil for all files
+}
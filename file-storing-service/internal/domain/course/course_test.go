@@ -0,0 +1,79 @@
+package course
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewCourse(t *testing.T) {
+	tests := []struct {
+		name       string
+		courseName string
+		year       int
+		wantErr    bool
+	}{
+		{name: "Valid course", courseName: "Algorithms", year: 2024, wantErr: false},
+		{name: "Empty name", courseName: "  ", year: 2024, wantErr: true},
+		{name: "Year too early", courseName: "Algorithms", year: 24, wantErr: true},
+		{name: "Year too late", courseName: "Algorithms", year: 20240, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCourse(tt.courseName, tt.year)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCourse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (c.Name != tt.courseName || c.Year != tt.year) {
+				t.Errorf("NewCourse() = %+v", c)
+			}
+		})
+	}
+}
+
+func TestNewAssignment(t *testing.T) {
+	assignment, err := NewAssignment("course-1", " Homework 1 ")
+	if err != nil {
+		t.Fatalf("NewAssignment() error = %v", err)
+	}
+	if assignment.Name != "Homework 1" || assignment.CourseID != "course-1" {
+		t.Errorf("NewAssignment() = %+v", assignment)
+	}
+
+	if _, err := NewAssignment("", "Homework 1"); err == nil {
+		t.Error("NewAssignment() without course should fail")
+	}
+	if _, err := NewAssignment("course-1", ""); err == nil {
+		t.Error("NewAssignment() without name should fail")
+	}
+}
+
+func TestParseScope(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Scope
+		wantErr bool
+	}{
+		{value: "", want: ScopeAll},
+		{value: "all", want: ScopeAll},
+		{value: "assignment", want: ScopeAssignment},
+		{value: "course", want: ScopeCourse},
+		{value: "prior_years", want: ScopePriorYears},
+		{value: "everything", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			scope, err := ParseScope(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidScope) {
+					t.Errorf("ParseScope() error = %v, want ErrInvalidScope", err)
+				}
+				return
+			}
+			if err != nil || scope != tt.want {
+				t.Errorf("ParseScope() = %v, %v, want %v", scope, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package file
+
+import "time"
+
+// ChangeKind is what happened to a file
+type ChangeKind string
+
+const (
+	ChangeCreated ChangeKind = "created"
+	ChangeDeleted ChangeKind = "deleted"
+)
+
+// Change is an upload or a deletion of a file reported to the other services
+type Change struct {
+	Kind       ChangeKind
+	File       File
+	OccurredAt time.Time
+}
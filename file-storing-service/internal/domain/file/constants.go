@@ -3,5 +3,30 @@ package file
 const (
 	// MaxFileSize defines the maximum allowed file size in bytes (32MB)
 	MaxFileSize = 32 << 20 // 32 * 1024 * 1024 bytes
-	ContentType = "text/plain"
+
+	// Supported document content types
+	ContentTypePlainText = "text/plain"
+	ContentTypeHTML      = "text/html"
+	ContentTypePDF       = "application/pdf"
+	ContentTypeDOCX      = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	ContentTypeODT       = "application/vnd.oasis.opendocument.text"
 )
+
+// SupportedContentTypes lists the document formats a plain-text rendition can be extracted from
+var SupportedContentTypes = []string{
+	ContentTypePlainText,
+	ContentTypeHTML,
+	ContentTypePDF,
+	ContentTypeDOCX,
+	ContentTypeODT,
+}
+
+// IsSupportedContentType checks whether documents of the given content type can be stored
+func IsSupportedContentType(contentType string) bool {
+	for _, supported := range SupportedContentTypes {
+		if contentType == supported {
+			return true
+		}
+	}
+	return false
+}
@@ -2,20 +2,37 @@ package file
 
 import (
 	"errors"
+	"fmt"
+	"mime"
+	"strings"
 	"time"
+
+	"filestoringservice/internal/domain/blob"
+)
+
+var (
+	// ErrUnsupportedContentType is returned for documents no plain text can be extracted from
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+	// ErrNotFound is returned when a file doesn't exist or is deleted
+	ErrNotFound = errors.New("file not found")
 )
 
-// File represents a file entity in the domain
+// File represents a single upload in the domain, files with identical content share one blob
 type File struct {
-	ID          string
-	Name        string
-	Size        int64
-	ContentType string
-	Location    string
-	Hash        string
-	UploadedAt  time.Time
-	UpdatedAt   time.Time
-	CreatedAt   time.Time
+	ID           string
+	Name         string
+	Uploader     string
+	AssignmentID string // Assignment the file is submitted to, empty for files outside of courses
+	BlobID       string // Content shared with other uploads of the same bytes
+	Size         int64
+	ContentType  string
+	Location     string
+	Hash         string
+	TextKey      string // Key of the normalized plain-text rendition in storage
+	UploadedAt   time.Time
+	UpdatedAt    time.Time
+	CreatedAt    time.Time
+	DeletedAt    *time.Time // Set once deletion is requested, until cleanup is finished
 }
 
 // NewFile creates a new File domain entity
@@ -26,8 +43,9 @@ func NewFile(name, contentType string, size int64) (*File, error) {
 	if size <= 0 {
 		return nil, errors.New("file size must be greater than zero")
 	}
-	if contentType != ContentType {
-		return nil, errors.New("content type must be " + ContentType)
+	contentType = NormalizeContentType(contentType)
+	if !IsSupportedContentType(contentType) {
+		return nil, fmt.Errorf("%w: content type must be one of: %s", ErrUnsupportedContentType, strings.Join(SupportedContentTypes, ", "))
 	}
 
 	now := time.Now()
@@ -50,3 +68,29 @@ func (f *File) SetHash(hash string) error {
 	f.UpdatedAt = time.Now()
 	return nil
 }
+
+// SetBlob links the file to the stored content and copies its storage details
+func (f *File) SetBlob(b *blob.Blob) error {
+	if b.ID == "" {
+		return errors.New("blob must be stored before a file is attached")
+	}
+	if f.Hash != "" && f.Hash != b.Hash {
+		return fmt.Errorf("file hash %s doesn't match blob hash %s", f.Hash, b.Hash)
+	}
+	f.BlobID = b.ID
+	f.Hash = b.Hash
+	f.ContentType = b.ContentType
+	f.Location = b.Location
+	f.TextKey = b.TextKey
+	f.UpdatedAt = time.Now()
+	return nil
+}
+
+// NormalizeContentType strips parameters (e.g. charset) and lowercases the media type
+func NormalizeContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return mediaType
+}
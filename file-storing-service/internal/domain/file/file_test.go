@@ -0,0 +1,64 @@
+package file
+
+import (
+	"testing"
+
+	"filestoringservice/internal/domain/blob"
+)
+
+func TestFile_SetBlob(t *testing.T) {
+	contentBlob, err := blob.NewBlob("hash", ContentTypePDF, 10)
+	if err != nil {
+		t.Fatalf("NewBlob() error = %v", err)
+	}
+
+	first, err := NewFile("first.pdf", ContentTypePDF, 10)
+	if err != nil {
+		t.Fatalf("NewFile() error = %v", err)
+	}
+
+	if err := first.SetBlob(contentBlob); err == nil {
+		t.Error("SetBlob() expected error for a blob that is not stored")
+	}
+
+	if err := contentBlob.SetStorage("key", "http://s3/files/key", "key.txt"); err != nil {
+		t.Fatalf("SetStorage() error = %v", err)
+	}
+
+	first.ID = "first"
+	first.Uploader = "ivanov"
+	if err := first.SetBlob(contentBlob); err != nil {
+		t.Fatalf("SetBlob() error = %v", err)
+	}
+
+	second, err := NewFile("second.pdf", ContentTypePDF, 10)
+	if err != nil {
+		t.Fatalf("NewFile() error = %v", err)
+	}
+
+	second.ID = "second"
+	second.Uploader = "petrov"
+	if err := second.SetBlob(contentBlob); err != nil {
+		t.Fatalf("SetBlob() error = %v", err)
+	}
+
+	// Both uploads share the content but keep their own identity
+	if first.BlobID != second.BlobID || first.TextKey != "key.txt" || second.Location != "http://s3/files/key" {
+		t.Errorf("files must share storage details of the blob, got %+v and %+v", first, second)
+	}
+	if first.Name == second.Name || first.Uploader == second.Uploader {
+		t.Errorf("files must keep their own name and uploader, got %+v and %+v", first, second)
+	}
+
+	other, err := NewFile("other.pdf", ContentTypePDF, 10)
+	if err != nil {
+		t.Fatalf("NewFile() error = %v", err)
+	}
+
+	if err := other.SetHash("other"); err != nil {
+		t.Fatalf("SetHash() error = %v", err)
+	}
+	if err := other.SetBlob(contentBlob); err == nil {
+		t.Error("SetBlob() expected error for a blob with another hash")
+	}
+}
@@ -0,0 +1,165 @@
+package file
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultPageSize is the amount of files returned when no limit is requested
+	DefaultPageSize = 50
+	// MaxPageSize caps the amount of files returned by a single request
+	MaxPageSize = 500
+)
+
+// ErrInvalidQuery is returned for listing parameters that can't be applied
+var ErrInvalidQuery = errors.New("invalid file query")
+
+// SortField is a column files can be ordered by
+type SortField string
+
+const (
+	SortByUploadedAt SortField = "uploaded_at"
+	SortByName       SortField = "name"
+	SortBySize       SortField = "size"
+)
+
+// SortOrder is the direction of the ordering
+type SortOrder string
+
+const (
+	OrderAsc  SortOrder = "asc"
+	OrderDesc SortOrder = "desc"
+)
+
+// ListQuery describes a page of files with optional filters
+type ListQuery struct {
+	NameContains string
+	HashPrefix   string
+	MinSize      *int64
+	MaxSize      *int64
+	UploadedFrom *time.Time
+	UploadedTo   *time.Time
+	SortBy       SortField
+	Order        SortOrder
+	Limit        int
+	After        *Cursor // Last file of the previous page
+}
+
+// Page is a single page of listed files
+type Page struct {
+	Files      []*File
+	TotalCount int
+	NextCursor string // Empty on the last page
+}
+
+// Cursor points at the last file of a page, files are ordered by the sort field and then by ID
+type Cursor struct {
+	SortBy SortField `json:"s"`
+	Order  SortOrder `json:"o"`
+	Value  string    `json:"v"`
+	ID     string    `json:"id"`
+}
+
+// Normalize fills in defaults and validates the query
+func (q *ListQuery) Normalize() error {
+	if q.SortBy == "" {
+		q.SortBy = SortByUploadedAt
+	}
+	if q.Order == "" {
+		q.Order = OrderDesc
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultPageSize
+	}
+
+	switch q.SortBy {
+	case SortByUploadedAt, SortByName, SortBySize:
+	default:
+		return fmt.Errorf("%w: sort must be one of: %s, %s, %s", ErrInvalidQuery, SortByUploadedAt, SortByName, SortBySize)
+	}
+	if q.Order != OrderAsc && q.Order != OrderDesc {
+		return fmt.Errorf("%w: order must be %s or %s", ErrInvalidQuery, OrderAsc, OrderDesc)
+	}
+	if q.Limit < 1 || q.Limit > MaxPageSize {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, MaxPageSize)
+	}
+	if q.MinSize != nil && q.MaxSize != nil && *q.MinSize > *q.MaxSize {
+		return fmt.Errorf("%w: min size is greater than max size", ErrInvalidQuery)
+	}
+	if q.UploadedFrom != nil && q.UploadedTo != nil && q.UploadedFrom.After(*q.UploadedTo) {
+		return fmt.Errorf("%w: upload date range is empty", ErrInvalidQuery)
+	}
+	// A cursor is only meaningful for the ordering it was issued for
+	if q.After != nil && (q.After.SortBy != q.SortBy || q.After.Order != q.Order) {
+		return fmt.Errorf("%w: cursor was issued for another sort order", ErrInvalidQuery)
+	}
+	if q.After != nil {
+		if _, err := q.After.SortValue(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NewCursor creates a cursor pointing at the file
+func NewCursor(f *File, sortBy SortField, order SortOrder) *Cursor {
+	cursor := &Cursor{SortBy: sortBy, Order: order, ID: f.ID}
+
+	switch sortBy {
+	case SortByName:
+		cursor.Value = f.Name
+	case SortBySize:
+		cursor.Value = strconv.FormatInt(f.Size, 10)
+	default:
+		cursor.Value = f.UploadedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	return cursor
+}
+
+// ParseCursor decodes a cursor received from a client
+func ParseCursor(encoded string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+
+	return &cursor, nil
+}
+
+// Encode returns the opaque representation of the cursor handed to clients
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// SortValue returns the value of the sort field typed for comparison
+func (c *Cursor) SortValue() (any, error) {
+	switch c.SortBy {
+	case SortByName:
+		return c.Value, nil
+	case SortBySize:
+		size, err := strconv.ParseInt(c.Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+		}
+		return size, nil
+	default:
+		uploadedAt, err := time.Parse(time.RFC3339Nano, c.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+		}
+		return uploadedAt, nil
+	}
+}
@@ -0,0 +1,98 @@
+package file
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestListQuery_Normalize(t *testing.T) {
+	small, large := int64(10), int64(100)
+	from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		query ListQuery
+		valid bool
+	}{
+		{name: "Defaults", query: ListQuery{}, valid: true},
+		{name: "Sort by name ascending", query: ListQuery{SortBy: SortByName, Order: OrderAsc}, valid: true},
+		{name: "Size range", query: ListQuery{MinSize: &small, MaxSize: &large}, valid: true},
+		{name: "Unknown sort field", query: ListQuery{SortBy: "hash"}, valid: false},
+		{name: "Unknown order", query: ListQuery{Order: "random"}, valid: false},
+		{name: "Limit too large", query: ListQuery{Limit: MaxPageSize + 1}, valid: false},
+		{name: "Negative limit", query: ListQuery{Limit: -1}, valid: false},
+		{name: "Empty size range", query: ListQuery{MinSize: &large, MaxSize: &small}, valid: false},
+		{name: "Empty date range", query: ListQuery{UploadedFrom: &from, UploadedTo: &to}, valid: false},
+		{
+			name:  "Cursor of another order",
+			query: ListQuery{SortBy: SortBySize, After: &Cursor{SortBy: SortByName, Order: OrderDesc, Value: "a", ID: "1"}},
+			valid: false,
+		},
+		{
+			name:  "Malformed cursor value",
+			query: ListQuery{SortBy: SortBySize, After: &Cursor{SortBy: SortBySize, Order: OrderDesc, Value: "big", ID: "1"}},
+			valid: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.query.Normalize()
+			if tt.valid && err != nil {
+				t.Errorf("Normalize() unexpected error = %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidQuery) {
+				t.Errorf("Normalize() error = %v, want ErrInvalidQuery", err)
+			}
+		})
+	}
+
+	query := ListQuery{}
+	if err := query.Normalize(); err != nil {
+		t.Fatalf("Normalize() error = %v", err)
+	}
+	if query.SortBy != SortByUploadedAt || query.Order != OrderDesc || query.Limit != DefaultPageSize {
+		t.Errorf("Normalize() defaults = %+v", query)
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	uploadedAt := time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	f := &File{ID: "file-id", Name: "report.pdf", Size: 42, UploadedAt: uploadedAt}
+
+	tests := []struct {
+		sortBy SortField
+		want   any
+	}{
+		{sortBy: SortByUploadedAt, want: uploadedAt},
+		{sortBy: SortByName, want: "report.pdf"},
+		{sortBy: SortBySize, want: int64(42)},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.sortBy), func(t *testing.T) {
+			cursor, err := ParseCursor(NewCursor(f, tt.sortBy, OrderAsc).Encode())
+			if err != nil {
+				t.Fatalf("ParseCursor() error = %v", err)
+			}
+
+			if cursor.ID != f.ID || cursor.SortBy != tt.sortBy || cursor.Order != OrderAsc {
+				t.Errorf("ParseCursor() = %+v", cursor)
+			}
+
+			value, err := cursor.SortValue()
+			if err != nil {
+				t.Fatalf("SortValue() error = %v", err)
+			}
+			if value != tt.want {
+				t.Errorf("SortValue() = %v, want %v", value, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseCursor("not a cursor"); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("ParseCursor() error = %v, want ErrInvalidQuery", err)
+	}
+}
@@ -0,0 +1,91 @@
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"filestoringservice/internal/domain/file"
+)
+
+// TypeFileUploaded is the event of a new file, the analysis service indexes it
+const TypeFileUploaded = "file.uploaded"
+
+const (
+	// BaseBackoff is the delay before the first redelivery, it doubles with every further attempt
+	BaseBackoff = 5 * time.Second
+	// MaxBackoff caps the delay between deliveries, events are redelivered until they are accepted
+	MaxBackoff = 5 * time.Minute
+	// Lease is how long a relay may deliver a claimed event before another relay claims it again
+	Lease = time.Minute
+)
+
+// Event is a message stored in the same transaction as the change it reports and delivered by the relay afterwards.
+// Delivery is at least once, consumers deduplicate events by ID.
+type Event struct {
+	ID            string
+	Type          string
+	Payload       json.RawMessage
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time // The event is not delivered before this time
+	CreatedAt     time.Time
+}
+
+// FileUploaded is the payload of TypeFileUploaded
+type FileUploaded struct {
+	FileID       string    `json:"file_id"`
+	Name         string    `json:"name"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Hash         string    `json:"hash"`
+	Uploader     string    `json:"uploader,omitempty"`
+	AssignmentID string    `json:"assignment_id,omitempty"`
+	UploadedAt   time.Time `json:"uploaded_at"`
+}
+
+// NewFileUploaded creates the event of an uploaded file
+func NewFileUploaded(fileModel *file.File) (*Event, error) {
+	if fileModel.ID == "" {
+		return nil, errors.New("file ID cannot be empty")
+	}
+
+	payload, err := json.Marshal(FileUploaded{
+		FileID:       fileModel.ID,
+		Name:         fileModel.Name,
+		ContentType:  fileModel.ContentType,
+		Size:         fileModel.Size,
+		Hash:         fileModel.Hash,
+		Uploader:     fileModel.Uploader,
+		AssignmentID: fileModel.AssignmentID,
+		UploadedAt:   fileModel.UploadedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	now := time.Now()
+	return &Event{
+		Type:          TypeFileUploaded,
+		Payload:       payload,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// Fail records a failed delivery and schedules the next one with exponential backoff
+func (e *Event) Fail(err error, now time.Time) {
+	e.Attempts++
+	e.LastError = err.Error()
+	e.NextAttemptAt = now.Add(Backoff(e.Attempts))
+}
+
+// Backoff returns the delay after the given number of failed deliveries
+func Backoff(attempts int) time.Duration {
+	delay := BaseBackoff
+	for i := 1; i < attempts && delay < MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, MaxBackoff)
+}
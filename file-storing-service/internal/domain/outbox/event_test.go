@@ -0,0 +1,53 @@
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"filestoringservice/internal/domain/file"
+)
+
+func TestNewFileUploaded(t *testing.T) {
+	uploadedAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	event, err := NewFileUploaded(&file.File{ID: "file1", Name: "essay.txt", ContentType: "text/plain", Size: 42, Hash: "abc", AssignmentID: "hw1", UploadedAt: uploadedAt})
+	if err != nil {
+		t.Fatalf("NewFileUploaded() error = %v", err)
+	}
+
+	if event.Type != TypeFileUploaded || event.NextAttemptAt.IsZero() {
+		t.Errorf("Event = %+v, want a new due file.uploaded event", event)
+	}
+
+	var payload FileUploaded
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		t.Fatalf("Payload is not JSON: %v", err)
+	}
+	if payload.FileID != "file1" || payload.AssignmentID != "hw1" || payload.Size != 42 || !payload.UploadedAt.Equal(uploadedAt) {
+		t.Errorf("Payload = %+v", payload)
+	}
+
+	if _, err := NewFileUploaded(&file.File{}); err == nil {
+		t.Error("NewFileUploaded() without a file ID, want an error")
+	}
+}
+
+func TestEvent_Fail(t *testing.T) {
+	event := &Event{}
+	now := time.Now()
+
+	event.Fail(errors.New("connection refused"), now)
+	if event.Attempts != 1 || event.LastError != "connection refused" || event.NextAttemptAt != now.Add(BaseBackoff) {
+		t.Errorf("Event after the first failure = %+v", event)
+	}
+
+	event.Fail(errors.New("connection refused"), now)
+	if event.NextAttemptAt != now.Add(2*BaseBackoff) {
+		t.Errorf("Next attempt = %v, want the backoff doubled", event.NextAttemptAt.Sub(now))
+	}
+
+	if Backoff(100) != MaxBackoff {
+		t.Errorf("Backoff(100) = %v, want %v", Backoff(100), MaxBackoff)
+	}
+}
@@ -0,0 +1,10 @@
+package upload
+
+import "time"
+
+const (
+	// DefaultChunkSize defines the size of every chunk except the last one (5MB, S3 minimum part size)
+	DefaultChunkSize = 5 << 20 // 5 * 1024 * 1024 bytes
+	// SessionTTL defines how long an unfinished upload session can be resumed
+	SessionTTL = 24 * time.Hour
+)
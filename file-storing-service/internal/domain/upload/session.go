@@ -0,0 +1,185 @@
+package upload
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"filestoringservice/internal/domain/file"
+)
+
+var (
+	ErrSessionNotFound = errors.New("upload session not found")
+	ErrSessionClosed   = errors.New("upload session is already completed or aborted")
+	ErrSessionExpired  = errors.New("upload session has expired")
+	ErrInvalidChunk    = errors.New("invalid chunk")
+	ErrIncomplete      = errors.New("upload session has missing chunks")
+)
+
+// Status represents the state of an upload session
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusCompleted Status = "completed"
+	StatusAborted   Status = "aborted"
+)
+
+// Part represents a single chunk stored as an S3 multipart upload part
+type Part struct {
+	Number     int
+	Size       int64
+	ETag       string
+	UploadedAt time.Time
+}
+
+// Session represents a resumable chunked upload in the domain
+type Session struct {
+	ID              string
+	FileName        string
+	ContentType     string
+	Uploader        string
+	AssignmentID    string // Assignment the file is submitted to
+	TotalSize       int64
+	ChunkSize       int64
+	StorageKey      string
+	StorageUploadID string
+	Status          Status
+	FileID          string
+	Parts           []Part
+	ExpiresAt       time.Time
+	UpdatedAt       time.Time
+	CreatedAt       time.Time
+}
+
+// NewSession creates a new upload session domain entity
+func NewSession(name, contentType string, size int64) (*Session, error) {
+	// Reuse file validation so that sessions are never created for files that can't be stored
+	if _, err := file.NewFile(name, contentType, size); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &Session{
+		FileName:    name,
+		ContentType: contentType,
+		TotalSize:   size,
+		ChunkSize:   DefaultChunkSize,
+		Status:      StatusPending,
+		ExpiresAt:   now.Add(SessionTTL),
+		UpdatedAt:   now,
+		CreatedAt:   now,
+	}, nil
+}
+
+// TotalChunks returns the number of chunks the file is split into
+func (s *Session) TotalChunks() int {
+	return int((s.TotalSize + s.ChunkSize - 1) / s.ChunkSize)
+}
+
+// ExpectedChunkSize returns the size the chunk with the given number must have
+func (s *Session) ExpectedChunkSize(number int) int64 {
+	if number == s.TotalChunks() {
+		return s.TotalSize - int64(number-1)*s.ChunkSize
+	}
+	return s.ChunkSize
+}
+
+// CanAcceptChunks checks that the session is still open for uploads
+func (s *Session) CanAcceptChunks(now time.Time) error {
+	if s.Status != StatusPending {
+		return ErrSessionClosed
+	}
+	if now.After(s.ExpiresAt) {
+		return ErrSessionExpired
+	}
+	return nil
+}
+
+// ValidateChunk checks the chunk number and size against the session layout
+func (s *Session) ValidateChunk(number int, size int64) error {
+	if number < 1 || number > s.TotalChunks() {
+		return fmt.Errorf("%w: chunk number must be between 1 and %d", ErrInvalidChunk, s.TotalChunks())
+	}
+	if expected := s.ExpectedChunkSize(number); size != expected {
+		return fmt.Errorf("%w: chunk %d must be %d bytes, got %d", ErrInvalidChunk, number, expected, size)
+	}
+	return nil
+}
+
+// Part returns the uploaded part with the given number
+func (s *Session) Part(number int) (Part, bool) {
+	for _, part := range s.Parts {
+		if part.Number == number {
+			return part, true
+		}
+	}
+	return Part{}, false
+}
+
+// AddPart registers an uploaded part, replacing a previously uploaded part with the same number
+func (s *Session) AddPart(part Part) {
+	for i := range s.Parts {
+		if s.Parts[i].Number == part.Number {
+			s.Parts[i] = part
+			s.UpdatedAt = time.Now()
+			return
+		}
+	}
+
+	s.Parts = append(s.Parts, part)
+	sort.Slice(s.Parts, func(i, j int) bool {
+		return s.Parts[i].Number < s.Parts[j].Number
+	})
+	s.UpdatedAt = time.Now()
+}
+
+// ReceivedBytes returns the amount of bytes stored so far
+func (s *Session) ReceivedBytes() int64 {
+	var received int64
+	for _, part := range s.Parts {
+		received += part.Size
+	}
+	return received
+}
+
+// MissingChunks returns the numbers of chunks that have not been uploaded yet
+func (s *Session) MissingChunks() []int {
+	uploaded := make(map[int]bool, len(s.Parts))
+	for _, part := range s.Parts {
+		uploaded[part.Number] = true
+	}
+
+	missing := []int{}
+	for number := 1; number <= s.TotalChunks(); number++ {
+		if !uploaded[number] {
+			missing = append(missing, number)
+		}
+	}
+	return missing
+}
+
+// Complete marks the session as completed and links it to the stored file
+func (s *Session) Complete(fileID string) error {
+	if s.Status != StatusPending {
+		return ErrSessionClosed
+	}
+	if len(s.MissingChunks()) > 0 {
+		return ErrIncomplete
+	}
+	s.Status = StatusCompleted
+	s.FileID = fileID
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// Abort marks the session as aborted
+func (s *Session) Abort() error {
+	if s.Status != StatusPending {
+		return ErrSessionClosed
+	}
+	s.Status = StatusAborted
+	s.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,121 @@
+package upload
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSession_ChunkLayout(t *testing.T) {
+	session, err := NewSession("document.txt", "text/plain", 2*DefaultChunkSize+10)
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+
+	if session.TotalChunks() != 3 {
+		t.Errorf("TotalChunks() = %v, want 3", session.TotalChunks())
+	}
+
+	if session.ExpectedChunkSize(1) != DefaultChunkSize {
+		t.Errorf("ExpectedChunkSize(1) = %v, want %v", session.ExpectedChunkSize(1), DefaultChunkSize)
+	}
+
+	if session.ExpectedChunkSize(3) != 10 {
+		t.Errorf("ExpectedChunkSize(3) = %v, want 10", session.ExpectedChunkSize(3))
+	}
+}
+
+func TestSession_ValidateChunk(t *testing.T) {
+	session, err := NewSession("document.txt", "text/plain", DefaultChunkSize+10)
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		number int
+		size   int64
+		valid  bool
+	}{
+		{name: "Full chunk", number: 1, size: DefaultChunkSize, valid: true},
+		{name: "Last chunk", number: 2, size: 10, valid: true},
+		{name: "Zero number", number: 0, size: DefaultChunkSize, valid: false},
+		{name: "Number out of range", number: 3, size: 10, valid: false},
+		{name: "Short chunk", number: 1, size: 10, valid: false},
+		{name: "Oversized last chunk", number: 2, size: 11, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := session.ValidateChunk(tt.number, tt.size)
+			if tt.valid && err != nil {
+				t.Errorf("ValidateChunk() error = %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidChunk) {
+				t.Errorf("ValidateChunk() error = %v, want ErrInvalidChunk", err)
+			}
+		})
+	}
+}
+
+func TestSession_PartsAndCompletion(t *testing.T) {
+	session, err := NewSession("document.txt", "text/plain", DefaultChunkSize+10)
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+
+	session.AddPart(Part{Number: 2, Size: 10, ETag: "b"})
+
+	if err := session.Complete("file1"); !errors.Is(err, ErrIncomplete) {
+		t.Errorf("Complete() error = %v, want ErrIncomplete", err)
+	}
+
+	missing := session.MissingChunks()
+	if len(missing) != 1 || missing[0] != 1 {
+		t.Errorf("MissingChunks() = %v, want [1]", missing)
+	}
+
+	session.AddPart(Part{Number: 1, Size: DefaultChunkSize, ETag: "a"})
+	session.AddPart(Part{Number: 1, Size: DefaultChunkSize, ETag: "c"})
+
+	if len(session.Parts) != 2 || session.Parts[0].Number != 1 || session.Parts[0].ETag != "c" {
+		t.Errorf("Parts = %v, want replaced part 1 followed by part 2", session.Parts)
+	}
+
+	if session.ReceivedBytes() != session.TotalSize {
+		t.Errorf("ReceivedBytes() = %v, want %v", session.ReceivedBytes(), session.TotalSize)
+	}
+
+	if err := session.Complete("file1"); err != nil {
+		t.Errorf("Complete() error = %v", err)
+	}
+
+	if err := session.CanAcceptChunks(time.Now()); !errors.Is(err, ErrSessionClosed) {
+		t.Errorf("CanAcceptChunks() error = %v, want ErrSessionClosed", err)
+	}
+}
+
+func TestSession_Expiration(t *testing.T) {
+	session, err := NewSession("document.txt", "text/plain", 10)
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+
+	if err := session.CanAcceptChunks(time.Now()); err != nil {
+		t.Errorf("CanAcceptChunks() error = %v", err)
+	}
+
+	if err := session.CanAcceptChunks(session.ExpiresAt.Add(time.Second)); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("CanAcceptChunks() error = %v, want ErrSessionExpired", err)
+	}
+}
+
+func TestNewSession_Validation(t *testing.T) {
+	if _, err := NewSession("image.png", "image/png", 10); err == nil {
+		t.Error("NewSession() should reject unsupported content type")
+	}
+
+	if _, err := NewSession("document.txt", "text/plain", 0); err == nil {
+		t.Error("NewSession() should reject empty files")
+	}
+}
@@ -0,0 +1,70 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"filestoringservice/internal/domain/outbox"
+	"filestoringservice/internal/infrastructure/config"
+)
+
+// publishTimeout limits a single delivery so that a hanging consumer doesn't stall the relay
+const publishTimeout = 10 * time.Second
+
+// HTTPPublisher delivers events straight to the events endpoint of file-analysis-service,
+// it needs no message broker and is meant for local runs and small deployments
+type HTTPPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPPublisher(cfg *config.Config) *HTTPPublisher {
+	return &HTTPPublisher{
+		url:    cfg.FileAnalysisServiceBaseURL + "/events",
+		client: &http.Client{Timeout: publishTimeout},
+	}
+}
+
+// Publish posts the event, any 2xx response means the consumer has accepted it
+func (p *HTTPPublisher) Publish(ctx context.Context, event *outbox.Event) error {
+	body, err := json.Marshal(map[string]any{
+		"id":         event.ID,
+		"type":       event.Type,
+		"payload":    event.Payload,
+		"created_at": event.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver event: %w", err)
+	}
+
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("failed to close response body: %v", err)
+		}
+	}(res.Body)
+
+	if res.StatusCode/100 == 2 {
+		return nil
+	}
+
+	responseBody, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+	return fmt.Errorf("event consumer responded with status %d: %s", res.StatusCode, responseBody)
+}
@@ -11,6 +11,10 @@ import (
 type Config struct {
 	// Server config
 	ServerPort string
+	GRPCPort   string
+
+	// External apis
+	FileAnalysisServiceBaseURL string
 
 	// Database config
 	DBHost     string
@@ -40,6 +44,10 @@ func Load() (*Config, error) {
 	config := &Config{
 		// Server config
 		ServerPort: getEnv("SERVER_PORT", "8000"),
+		GRPCPort:   getEnv("GRPC_PORT", "9000"),
+
+		// External apis
+		FileAnalysisServiceBaseURL: getEnv("FILE_ANALYSIS_SERVICE_API_URL", "http://file-analysis-service:8001/analysis-api"),
 
 		// Database config
 		DBHost:     getEnv("DB_HOST", "localhost"),
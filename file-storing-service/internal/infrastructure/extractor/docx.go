@@ -0,0 +1,99 @@
+package extractor
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"filestoringservice/internal/domain/file"
+)
+
+// wordprocessingNamespace is the namespace of the main DOCX document part
+const wordprocessingNamespace = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+
+// DOCXExtractor implements the Extractor interface for Word (Office Open XML) documents
+type DOCXExtractor struct{}
+
+// NewDOCXExtractor creates a new DOCX extractor
+func NewDOCXExtractor() *DOCXExtractor {
+	return &DOCXExtractor{}
+}
+
+// ContentTypes returns the content types handled by the extractor
+func (e *DOCXExtractor) ContentTypes() []string {
+	return []string{file.ContentTypeDOCX}
+}
+
+// Extract returns the text of the document body, paragraphs are separated by blank lines
+func (e *DOCXExtractor) Extract(ctx context.Context, data io.ReaderAt, size int64) (string, error) {
+	decoder, closer, err := openZipXML(data, size, "word/document.xml")
+	if err != nil {
+		return "", err
+	}
+	defer closer.Close()
+
+	var result strings.Builder
+	inText := false
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return result.String(), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to parse DOCX document: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Space != wordprocessingNamespace {
+				continue
+			}
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				result.WriteString("\t")
+			case "br", "cr":
+				result.WriteString("\n")
+			}
+		case xml.EndElement:
+			if t.Name.Space != wordprocessingNamespace {
+				continue
+			}
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				result.WriteString("\n\n")
+			}
+		case xml.CharData:
+			if inText {
+				result.Write(t)
+			}
+		}
+	}
+}
+
+// openZipXML opens an XML entry of a zip based document
+func openZipXML(data io.ReaderAt, size int64, name string) (*xml.Decoder, io.Closer, error) {
+	archive, err := zip.NewReader(data, size)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open document archive: %w", err)
+	}
+
+	entry, err := archive.Open(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+
+	return xml.NewDecoder(entry), entry, nil
+}
@@ -0,0 +1,90 @@
+package extractor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"filestoringservice/internal/domain/file"
+)
+
+// skippedHTMLElements contain no document text
+var skippedHTMLElements = map[string]bool{
+	"head": true, "script": true, "style": true, "noscript": true, "template": true, "svg": true,
+}
+
+// blockHTMLElements start a new paragraph
+var blockHTMLElements = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "header": true, "footer": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "li": true, "table": true, "tr": true, "blockquote": true, "pre": true,
+}
+
+// HTMLExtractor implements the Extractor interface for HTML documents
+type HTMLExtractor struct{}
+
+// NewHTMLExtractor creates a new HTML extractor
+func NewHTMLExtractor() *HTMLExtractor {
+	return &HTMLExtractor{}
+}
+
+// ContentTypes returns the content types handled by the extractor
+func (e *HTMLExtractor) ContentTypes() []string {
+	return []string{file.ContentTypeHTML}
+}
+
+// Extract returns the visible text of an HTML document
+func (e *HTMLExtractor) Extract(ctx context.Context, data io.ReaderAt, size int64) (string, error) {
+	tokenizer := html.NewTokenizer(io.NewSectionReader(data, 0, size))
+
+	var result strings.Builder
+	skipDepth := 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if errors.Is(tokenizer.Err(), io.EOF) {
+				return result.String(), nil
+			}
+			return "", fmt.Errorf("failed to parse HTML: %w", tokenizer.Err())
+		case html.StartTagToken:
+			name, _ := tokenizer.TagName()
+			tag := string(name)
+			if skippedHTMLElements[tag] {
+				skipDepth++
+			}
+			if blockHTMLElements[tag] {
+				result.WriteString("\n\n")
+			}
+			if tag == "br" {
+				result.WriteString("\n")
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			tag := string(name)
+			if skippedHTMLElements[tag] && skipDepth > 0 {
+				skipDepth--
+			}
+			if blockHTMLElements[tag] {
+				result.WriteString("\n\n")
+			}
+		case html.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			if string(name) == "br" {
+				result.WriteString("\n")
+			}
+		case html.TextToken:
+			if skipDepth == 0 {
+				result.Write(tokenizer.Text())
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package extractor
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	horizontalSpaceRegex = regexp.MustCompile(`[ \t]+`)
+	blankLinesRegex      = regexp.MustCompile(`\n{3,}`)
+)
+
+// NormalizeText brings extracted text to a canonical form:
+// valid UTF-8, LF line endings, no control characters, single spaces and at most one blank line between paragraphs
+func NormalizeText(text string) string {
+	text = strings.ToValidUTF8(text, "")
+	text = strings.TrimPrefix(text, "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	text = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			return r
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, text)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(horizontalSpaceRegex.ReplaceAllString(line, " "))
+	}
+	text = strings.Join(lines, "\n")
+
+	text = blankLinesRegex.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
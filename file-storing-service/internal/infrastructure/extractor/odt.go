@@ -0,0 +1,93 @@
+package extractor
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"filestoringservice/internal/domain/file"
+)
+
+// openDocumentTextNamespace is the namespace of ODF text elements
+const openDocumentTextNamespace = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+
+// ODTExtractor implements the Extractor interface for OpenDocument text documents
+type ODTExtractor struct{}
+
+// NewODTExtractor creates a new ODT extractor
+func NewODTExtractor() *ODTExtractor {
+	return &ODTExtractor{}
+}
+
+// ContentTypes returns the content types handled by the extractor
+func (e *ODTExtractor) ContentTypes() []string {
+	return []string{file.ContentTypeODT}
+}
+
+// Extract returns the text of paragraphs and headings, separated by blank lines
+func (e *ODTExtractor) Extract(ctx context.Context, data io.ReaderAt, size int64) (string, error) {
+	decoder, closer, err := openZipXML(data, size, "content.xml")
+	if err != nil {
+		return "", err
+	}
+	defer closer.Close()
+
+	var result strings.Builder
+	paragraphDepth := 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return result.String(), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to parse ODT document: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Space != openDocumentTextNamespace {
+				continue
+			}
+			switch t.Name.Local {
+			case "p", "h":
+				paragraphDepth++
+			case "s":
+				// <text:s text:c="N"/> encodes N consecutive spaces
+				count := 1
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "c" {
+						if c, err := strconv.Atoi(attr.Value); err == nil && c > 0 {
+							count = c
+						}
+					}
+				}
+				result.WriteString(strings.Repeat(" ", count))
+			case "tab":
+				result.WriteString("\t")
+			case "line-break":
+				result.WriteString("\n")
+			}
+		case xml.EndElement:
+			if t.Name.Space != openDocumentTextNamespace {
+				continue
+			}
+			if t.Name.Local == "p" || t.Name.Local == "h" {
+				paragraphDepth--
+				result.WriteString("\n\n")
+			}
+		case xml.CharData:
+			if paragraphDepth > 0 {
+				result.Write(t)
+			}
+		}
+	}
+}
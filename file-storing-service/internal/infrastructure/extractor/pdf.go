@@ -0,0 +1,72 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+
+	"filestoringservice/internal/domain/file"
+)
+
+// PDFExtractor implements the Extractor interface for PDF documents
+type PDFExtractor struct{}
+
+// NewPDFExtractor creates a new PDF extractor
+func NewPDFExtractor() *PDFExtractor {
+	return &PDFExtractor{}
+}
+
+// ContentTypes returns the content types handled by the extractor
+func (e *PDFExtractor) ContentTypes() []string {
+	return []string{file.ContentTypePDF}
+}
+
+// Extract returns the text of all pages, pages are separated by blank lines
+func (e *PDFExtractor) Extract(ctx context.Context, data io.ReaderAt, size int64) (text string, err error) {
+	// The PDF parser panics on some malformed documents
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed PDF document: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(data, size)
+	if err != nil {
+		return "", fmt.Errorf("failed to open PDF: %w", err)
+	}
+
+	var result strings.Builder
+	fonts := make(map[string]*pdf.Font)
+
+	for i := 1; i <= reader.NumPage(); i++ {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+
+		// Cache fonts so that character maps are not parsed for every page
+		for _, name := range page.Fonts() {
+			if _, ok := fonts[name]; !ok {
+				font := page.Font(name)
+				fonts[name] = &font
+			}
+		}
+
+		pageText, err := page.GetPlainText(fonts)
+		if err != nil {
+			return "", fmt.Errorf("failed to extract text from page %d: %w", i, err)
+		}
+
+		result.WriteString(pageText)
+		result.WriteString("\n\n")
+	}
+
+	return result.String(), nil
+}
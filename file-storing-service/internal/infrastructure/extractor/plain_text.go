@@ -0,0 +1,36 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"filestoringservice/internal/domain/file"
+)
+
+// PlainTextExtractor implements the Extractor interface for plain text files
+type PlainTextExtractor struct{}
+
+// NewPlainTextExtractor creates a new plain text extractor
+func NewPlainTextExtractor() *PlainTextExtractor {
+	return &PlainTextExtractor{}
+}
+
+// ContentTypes returns the content types handled by the extractor
+func (e *PlainTextExtractor) ContentTypes() []string {
+	return []string{file.ContentTypePlainText}
+}
+
+// Extract returns the file content as is, normalization is done by the registry
+func (e *PlainTextExtractor) Extract(ctx context.Context, data io.ReaderAt, size int64) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	content, err := io.ReadAll(io.NewSectionReader(data, 0, size))
+	if err != nil {
+		return "", fmt.Errorf("failed to read text: %w", err)
+	}
+
+	return string(content), nil
+}
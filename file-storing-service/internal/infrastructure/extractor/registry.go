@@ -0,0 +1,132 @@
+package extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"filestoringservice/internal/domain/file"
+	extractorInterface "filestoringservice/internal/interfaces/extractor"
+)
+
+// sniffLength is the amount of bytes used to detect the document format
+const sniffLength = 512
+
+// Registry implements the TextExtractor interface by dispatching to extractors keyed by content type
+type Registry struct {
+	extractors map[string]extractorInterface.Extractor
+}
+
+// NewRegistry creates a registry with all built-in extractors
+func NewRegistry() *Registry {
+	registry := &Registry{
+		extractors: make(map[string]extractorInterface.Extractor),
+	}
+
+	registry.Register(NewPlainTextExtractor())
+	registry.Register(NewHTMLExtractor())
+	registry.Register(NewPDFExtractor())
+	registry.Register(NewDOCXExtractor())
+	registry.Register(NewODTExtractor())
+
+	return registry
+}
+
+// Register adds an extractor for all of its content types, replacing previously registered ones
+func (r *Registry) Register(extractor extractorInterface.Extractor) {
+	for _, contentType := range extractor.ContentTypes() {
+		r.extractors[contentType] = extractor
+	}
+}
+
+// DetectContentType determines the document format from its magic bytes, falling back to the declared type
+func (r *Registry) DetectContentType(declared string, data io.ReaderAt, size int64) (string, error) {
+	declared = file.NormalizeContentType(declared)
+
+	header := make([]byte, min(size, sniffLength))
+	n, err := data.ReadAt(header, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read file header: %w", err)
+	}
+	header = header[:n]
+
+	var detected string
+	switch {
+	case bytes.HasPrefix(header, []byte("%PDF-")):
+		detected = file.ContentTypePDF
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")):
+		detected = sniffZipDocument(data, size)
+	default:
+		sniffed := file.NormalizeContentType(http.DetectContentType(header))
+		switch {
+		case sniffed == file.ContentTypeHTML:
+			detected = file.ContentTypeHTML
+		case strings.HasPrefix(sniffed, "text/"):
+			// HTML fragments without a leading tag are sniffed as plain text
+			if declared == file.ContentTypeHTML {
+				detected = file.ContentTypeHTML
+			} else {
+				detected = file.ContentTypePlainText
+			}
+		}
+	}
+
+	if _, ok := r.extractors[detected]; !ok {
+		return "", fmt.Errorf("%w: %s", file.ErrUnsupportedContentType, declared)
+	}
+
+	return detected, nil
+}
+
+// ExtractText extracts and normalizes the plain text of a document
+func (r *Registry) ExtractText(ctx context.Context, contentType string, data io.ReaderAt, size int64) (string, error) {
+	extractor, ok := r.extractors[file.NormalizeContentType(contentType)]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", file.ErrUnsupportedContentType, contentType)
+	}
+
+	text, err := extractor.Extract(ctx, data, size)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract text: %w", err)
+	}
+
+	return NormalizeText(text), nil
+}
+
+// sniffZipDocument distinguishes zip based office documents
+func sniffZipDocument(data io.ReaderAt, size int64) string {
+	archive, err := zip.NewReader(data, size)
+	if err != nil {
+		return ""
+	}
+
+	for _, entry := range archive.File {
+		switch entry.Name {
+		case "word/document.xml":
+			return file.ContentTypeDOCX
+		case "mimetype":
+			mimetype, err := readZipEntry(entry, sniffLength)
+			if err == nil && strings.TrimSpace(string(mimetype)) == file.ContentTypeODT {
+				return file.ContentTypeODT
+			}
+		}
+	}
+
+	return ""
+}
+
+// readZipEntry reads at most limit bytes of a zip entry
+func readZipEntry(entry *zip.File, limit int64) ([]byte, error) {
+	reader, err := entry.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(io.LimitReader(reader, limit))
+}
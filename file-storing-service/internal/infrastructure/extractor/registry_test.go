@@ -0,0 +1,214 @@
+package extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"filestoringservice/internal/domain/file"
+)
+
+func buildZip(t *testing.T, entries map[string]string, order []string) []byte {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, name := range order {
+		entry, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry: %v", err)
+		}
+		if _, err := entry.Write([]byte(entries[name])); err != nil {
+			t.Fatalf("Failed to write zip entry: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func buildDOCX(t *testing.T) []byte {
+	document := `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:body>
+<w:p><w:r><w:t>Первый</w:t></w:r><w:r><w:t xml:space="preserve"> абзац</w:t></w:r></w:p>
+<w:p><w:r><w:t>Второй</w:t><w:tab/><w:t>абзац</w:t></w:r></w:p>
+</w:body>
+</w:document>`
+	return buildZip(t, map[string]string{
+		"[Content_Types].xml": `<Types/>`,
+		"word/document.xml":   document,
+	}, []string{"[Content_Types].xml", "word/document.xml"})
+}
+
+func buildODT(t *testing.T) []byte {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0">
+<office:automatic-styles><style>ignored</style></office:automatic-styles>
+<office:body><office:text>
+<text:h>Заголовок</text:h>
+<text:p>Первый<text:s text:c="3"/>абзац</text:p>
+<text:p>Второй<text:line-break/>абзац</text:p>
+</office:text></office:body>
+</office:document-content>`
+	return buildZip(t, map[string]string{
+		"mimetype":    file.ContentTypeODT,
+		"content.xml": content,
+	}, []string{"mimetype", "content.xml"})
+}
+
+// buildPDF assembles a single page PDF with correct cross-reference offsets
+func buildPDF(text string) []byte {
+	stream := fmt.Sprintf("BT /F1 12 Tf 72 712 Td (%s) Tj ET", text)
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}
+
+func TestRegistry_DetectContentType(t *testing.T) {
+	registry := NewRegistry()
+
+	tests := []struct {
+		name     string
+		declared string
+		data     []byte
+		expected string
+	}{
+		{name: "Plain text", declared: "text/plain", data: []byte("Привет мир"), expected: file.ContentTypePlainText},
+		{name: "Plain text with charset", declared: "text/plain; charset=utf-8", data: []byte("Привет"), expected: file.ContentTypePlainText},
+		{name: "HTML sniffed", declared: "application/octet-stream", data: []byte("<!DOCTYPE html><html><body>Привет</body></html>"), expected: file.ContentTypeHTML},
+		{name: "HTML fragment declared", declared: "text/html", data: []byte("Привет <b>мир</b>"), expected: file.ContentTypeHTML},
+		{name: "PDF sniffed", declared: "application/octet-stream", data: buildPDF("Hello"), expected: file.ContentTypePDF},
+		{name: "DOCX sniffed", declared: "application/zip", data: buildDOCX(t), expected: file.ContentTypeDOCX},
+		{name: "ODT sniffed", declared: "application/octet-stream", data: buildODT(t), expected: file.ContentTypeODT},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detected, err := registry.DetectContentType(tt.declared, bytes.NewReader(tt.data), int64(len(tt.data)))
+			if err != nil {
+				t.Fatalf("DetectContentType() error = %v", err)
+			}
+			if detected != tt.expected {
+				t.Errorf("DetectContentType() = %v, want %v", detected, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegistry_DetectContentType_Unsupported(t *testing.T) {
+	registry := NewRegistry()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "PNG image", data: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")},
+		{name: "Plain zip archive", data: buildZip(t, map[string]string{"a.txt": "a"}, []string{"a.txt"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := registry.DetectContentType("application/octet-stream", bytes.NewReader(tt.data), int64(len(tt.data)))
+			if !errors.Is(err, file.ErrUnsupportedContentType) {
+				t.Errorf("DetectContentType() error = %v, want ErrUnsupportedContentType", err)
+			}
+		})
+	}
+}
+
+func TestRegistry_ExtractText(t *testing.T) {
+	registry := NewRegistry()
+
+	tests := []struct {
+		name        string
+		contentType string
+		data        []byte
+		expected    string
+	}{
+		{
+			name:        "Plain text",
+			contentType: file.ContentTypePlainText,
+			data:        []byte("\ufeffПервая  строка\r\n\r\n\r\n\r\nВторая строка "),
+			expected:    "Первая строка\n\nВторая строка",
+		},
+		{
+			name:        "HTML",
+			contentType: file.ContentTypeHTML,
+			data:        []byte("<html><head><title>T</title><style>p{}</style></head><body><h1>Заголовок</h1><p>Привет&nbsp;<b>мир</b></p><script>alert(1)</script></body></html>"),
+			expected:    "Заголовок\n\nПривет мир",
+		},
+		{
+			name:        "DOCX",
+			contentType: file.ContentTypeDOCX,
+			data:        buildDOCX(t),
+			expected:    "Первый абзац\n\nВторой абзац",
+		},
+		{
+			name:        "ODT",
+			contentType: file.ContentTypeODT,
+			data:        buildODT(t),
+			expected:    "Заголовок\n\nПервый абзац\n\nВторой\nабзац",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := registry.ExtractText(context.Background(), tt.contentType, bytes.NewReader(tt.data), int64(len(tt.data)))
+			if err != nil {
+				t.Fatalf("ExtractText() error = %v", err)
+			}
+			if text != tt.expected {
+				t.Errorf("ExtractText() = %q, want %q", text, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegistry_ExtractText_PDF(t *testing.T) {
+	registry := NewRegistry()
+	data := buildPDF("Hello PDF world")
+
+	text, err := registry.ExtractText(context.Background(), file.ContentTypePDF, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ExtractText() error = %v", err)
+	}
+
+	if !strings.Contains(text, "Hello PDF world") {
+		t.Errorf("ExtractText() = %q, want it to contain %q", text, "Hello PDF world")
+	}
+}
+
+func TestRegistry_ExtractText_MalformedPDF(t *testing.T) {
+	registry := NewRegistry()
+	data := []byte("%PDF-1.4\nnot really a pdf")
+
+	_, err := registry.ExtractText(context.Background(), file.ContentTypePDF, bytes.NewReader(data), int64(len(data)))
+	if err == nil {
+		t.Error("ExtractText() should fail for malformed PDF")
+	}
+}
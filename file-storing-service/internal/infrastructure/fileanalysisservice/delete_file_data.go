@@ -0,0 +1,56 @@
+package fileanalysisservice
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"filestoringservice/internal/infrastructure/config"
+)
+
+// requestTimeout limits a single call so that a hanging analysis service doesn't block deletions
+const requestTimeout = 10 * time.Second
+
+type FileAnalysisService struct {
+	basePath string
+	client   *http.Client
+}
+
+func NewFileAnalysisService(cfg *config.Config) *FileAnalysisService {
+	return &FileAnalysisService{
+		basePath: cfg.FileAnalysisServiceBaseURL,
+		client:   &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// DeleteFileData asks the analysis service to remove everything it stores for the file.
+// Data that is already gone is not an error, so the call can be repeated.
+func (fileAnalysisService *FileAnalysisService) DeleteFileData(ctx context.Context, fileID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fileAnalysisService.basePath+"/analysis/"+url.PathEscape(fileID), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	res, err := fileAnalysisService.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call file analysis service: %w", err)
+	}
+
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("failed to close response body: %v", err)
+		}
+	}(res.Body)
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode/100 == 2 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+	return fmt.Errorf("file analysis service responded with status %d: %s", res.StatusCode, body)
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/zeebo/blake3"
 	"io"
 	"os"
+
+	hashInterface "filestoringservice/internal/interfaces/hash"
 )
 
 // BLAKE3Hasher implements the Hasher interface using BLAKE3 algorithm
@@ -47,6 +49,24 @@ func (h *BLAKE3Hasher) ComputeHashFromFile(ctx context.Context, filePath string)
 	return h.ComputeHash(ctx, file)
 }
 
+// NewStreamHasher creates a BLAKE3 hasher that accepts data in several writes
+func (h *BLAKE3Hasher) NewStreamHasher() hashInterface.StreamHasher {
+	return &blake3StreamHasher{hasher: blake3.New()}
+}
+
+// blake3StreamHasher implements the StreamHasher interface
+type blake3StreamHasher struct {
+	hasher *blake3.Hasher
+}
+
+func (s *blake3StreamHasher) Write(p []byte) (int, error) {
+	return s.hasher.Write(p)
+}
+
+func (s *blake3StreamHasher) Sum() string {
+	return hex.EncodeToString(s.hasher.Sum(nil))
+}
+
 // copyWithContext copies data with context cancellation support
 func (h *BLAKE3Hasher) copyWithContext(ctx context.Context, dst io.Writer, src io.Reader) (int64, error) {
 	buf := make([]byte, 32*1024)
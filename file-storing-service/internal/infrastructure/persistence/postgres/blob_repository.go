@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"filestoringservice/internal/domain/blob"
+)
+
+// blobColumns lists the columns read by every blob query
+const blobColumns = `id, hash, size, content_type, location, text_key, ref_count, released_at, updated_at, created_at`
+
+// BlobRepository implements the repository.BlobRepository interface with PostgreSQL
+type BlobRepository struct {
+	db *sql.DB
+}
+
+// NewBlobRepository creates a new PostgreSQL blob repository
+func NewBlobRepository(db *sql.DB) *BlobRepository {
+	return &BlobRepository{
+		db: db,
+	}
+}
+
+// Store saves a new blob to the database. It returns false without storing anything
+// if another live blob with the same hash has been stored in the meantime.
+func (r *BlobRepository) Store(ctx context.Context, blob *blob.Blob) (bool, error) {
+	query := `
+		INSERT INTO blobs (id, hash, size, content_type, location, text_key, ref_count, updated_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (hash) WHERE released_at IS NULL DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		blob.ID,
+		blob.Hash,
+		blob.Size,
+		blob.ContentType,
+		blob.Location,
+		blob.TextKey,
+		blob.RefCount,
+		blob.UpdatedAt,
+		blob.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to store blob: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to store blob: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// scanBlob reads a blob selected with blobColumns
+func scanBlob(row rowScanner) (*blob.Blob, error) {
+	var b blob.Blob
+	var updatedAt, createdAt time.Time
+	var releasedAt sql.NullTime
+
+	err := row.Scan(
+		&b.ID,
+		&b.Hash,
+		&b.Size,
+		&b.ContentType,
+		&b.Location,
+		&b.TextKey,
+		&b.RefCount,
+		&releasedAt,
+		&updatedAt,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	b.UpdatedAt = updatedAt
+	b.CreatedAt = createdAt
+	if releasedAt.Valid {
+		b.ReleasedAt = &releasedAt.Time
+	}
+
+	return &b, nil
+}
+
+// FindByHash retrieves the live blob with the given content hash
+func (r *BlobRepository) FindByHash(ctx context.Context, hash string) (*blob.Blob, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM blobs
+		WHERE hash = $1 AND released_at IS NULL
+	`, blobColumns)
+
+	b, err := scanBlob(r.db.QueryRowContext(ctx, query, hash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find blob: %w", err)
+	}
+
+	return b, nil
+}
+
+// FindReleased retrieves blobs no file references anymore whose content is still to be removed
+func (r *BlobRepository) FindReleased(ctx context.Context, limit int) ([]*blob.Blob, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM blobs
+		WHERE released_at IS NOT NULL
+		ORDER BY released_at
+		LIMIT $1
+	`, blobColumns)
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blobs: %w", err)
+	}
+	defer rows.Close()
+
+	var blobs []*blob.Blob
+	for rows.Next() {
+		b, err := scanBlob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan blob: %w", err)
+		}
+
+		blobs = append(blobs, b)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over blobs: %w", err)
+	}
+
+	return blobs, nil
+}
+
+// Release marks a blob as released if no file references it, a referenced blob is left as is
+func (r *BlobRepository) Release(ctx context.Context, id string, now time.Time) error {
+	query := `
+		UPDATE blobs
+		SET released_at = $2, updated_at = $2
+		WHERE id = $1 AND ref_count = 0 AND released_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, now)
+	if err != nil {
+		return fmt.Errorf("failed to release blob: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a released blob from the database
+func (r *BlobRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM blobs WHERE id = $1 AND released_at IS NOT NULL`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,268 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"filestoringservice/internal/domain/course"
+)
+
+// CourseRepository implements the repository.CourseRepository interface with PostgreSQL
+type CourseRepository struct {
+	db *sql.DB
+}
+
+// NewCourseRepository creates a new PostgreSQL course repository
+func NewCourseRepository(db *sql.DB) *CourseRepository {
+	return &CourseRepository{
+		db: db,
+	}
+}
+
+// StoreCourse saves a new course
+func (r *CourseRepository) StoreCourse(ctx context.Context, c *course.Course) error {
+	query := `
+		INSERT INTO courses (id, name, year, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, c.ID, c.Name, c.Year, c.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store course: %w", err)
+	}
+
+	return nil
+}
+
+// FindCourseByID retrieves a course, nil if it doesn't exist
+func (r *CourseRepository) FindCourseByID(ctx context.Context, id string) (*course.Course, error) {
+	query := `
+		SELECT id, name, year, created_at
+		FROM courses
+		WHERE id = $1
+	`
+
+	c, err := scanCourse(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find course: %w", err)
+	}
+
+	return c, nil
+}
+
+// ListCourses retrieves all courses, the latest years first
+func (r *CourseRepository) ListCourses(ctx context.Context) ([]*course.Course, error) {
+	query := `
+		SELECT id, name, year, created_at
+		FROM courses
+		ORDER BY year DESC, name, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query courses: %w", err)
+	}
+	defer rows.Close()
+
+	courses := []*course.Course{}
+	for rows.Next() {
+		c, err := scanCourse(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan course: %w", err)
+		}
+		courses = append(courses, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating courses: %w", err)
+	}
+
+	return courses, nil
+}
+
+// scanCourse reads a course row
+func scanCourse(row rowScanner) (*course.Course, error) {
+	var c course.Course
+	err := row.Scan(&c.ID, &c.Name, &c.Year, &c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// StoreAssignment saves a new assignment
+func (r *CourseRepository) StoreAssignment(ctx context.Context, assignment *course.Assignment) error {
+	query := `
+		INSERT INTO assignments (id, course_id, name, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, assignment.ID, assignment.CourseID, assignment.Name, assignment.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store assignment: %w", err)
+	}
+
+	return nil
+}
+
+// FindAssignmentByID retrieves an assignment, nil if it doesn't exist
+func (r *CourseRepository) FindAssignmentByID(ctx context.Context, id string) (*course.Assignment, error) {
+	query := `
+		SELECT id, course_id, name, created_at
+		FROM assignments
+		WHERE id = $1
+	`
+
+	assignment, err := scanAssignment(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find assignment: %w", err)
+	}
+
+	return assignment, nil
+}
+
+// ListAssignments retrieves the assignments of a course in the order they were created
+func (r *CourseRepository) ListAssignments(ctx context.Context, courseID string) ([]*course.Assignment, error) {
+	query := `
+		SELECT id, course_id, name, created_at
+		FROM assignments
+		WHERE course_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := []*course.Assignment{}
+	for rows.Next() {
+		assignment, err := scanAssignment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan assignment: %w", err)
+		}
+		assignments = append(assignments, assignment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating assignments: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// scanAssignment reads an assignment row
+func scanAssignment(row rowScanner) (*course.Assignment, error) {
+	var assignment course.Assignment
+	err := row.Scan(&assignment.ID, &assignment.CourseID, &assignment.Name, &assignment.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &assignment, nil
+}
+
+// ListSubmissions retrieves the files submitted to an assignment, the earliest first
+func (r *CourseRepository) ListSubmissions(ctx context.Context, assignmentID string) ([]*course.Submission, error) {
+	query := `
+		SELECT id, name, assignment_id, uploader, uploaded_at
+		FROM files
+		WHERE assignment_id = $1 AND deleted_at IS NULL
+		ORDER BY uploaded_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, assignmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query submissions: %w", err)
+	}
+	defer rows.Close()
+
+	submissions := []*course.Submission{}
+	for rows.Next() {
+		var submission course.Submission
+		err := rows.Scan(&submission.FileID, &submission.FileName, &submission.AssignmentID, &submission.Student, &submission.SubmittedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan submission: %w", err)
+		}
+		submissions = append(submissions, &submission)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating submissions: %w", err)
+	}
+
+	return submissions, nil
+}
+
+// FindAssignmentFileIDs retrieves the files submitted to the assignment
+func (r *CourseRepository) FindAssignmentFileIDs(ctx context.Context, assignmentID string) ([]string, error) {
+	query := `
+		SELECT id
+		FROM files
+		WHERE assignment_id = $1 AND deleted_at IS NULL
+		ORDER BY id
+	`
+
+	return r.findFileIDs(ctx, query, assignmentID)
+}
+
+// FindCourseFileIDs retrieves the files submitted to any assignment of the course
+func (r *CourseRepository) FindCourseFileIDs(ctx context.Context, courseID string) ([]string, error) {
+	query := `
+		SELECT f.id
+		FROM files f
+		JOIN assignments a ON a.id = f.assignment_id
+		WHERE a.course_id = $1 AND f.deleted_at IS NULL
+		ORDER BY f.id
+	`
+
+	return r.findFileIDs(ctx, query, courseID)
+}
+
+// FindPriorYearsFileIDs retrieves the files submitted to the courses with the given name taught before the year
+func (r *CourseRepository) FindPriorYearsFileIDs(ctx context.Context, courseName string, year int) ([]string, error) {
+	query := `
+		SELECT f.id
+		FROM files f
+		JOIN assignments a ON a.id = f.assignment_id
+		JOIN courses c ON c.id = a.course_id
+		WHERE c.name = $1 AND c.year < $2 AND f.deleted_at IS NULL
+		ORDER BY f.id
+	`
+
+	return r.findFileIDs(ctx, query, courseName, year)
+}
+
+// findFileIDs runs a query selecting file IDs
+func (r *CourseRepository) findFileIDs(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query submitted files: %w", err)
+	}
+	defer rows.Close()
+
+	fileIDs := []string{}
+	for rows.Next() {
+		var fileID string
+		if err := rows.Scan(&fileID); err != nil {
+			return nil, fmt.Errorf("failed to scan file ID: %w", err)
+		}
+		fileIDs = append(fileIDs, fileID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating submitted files: %w", err)
+	}
+
+	return fileIDs, nil
+}
@@ -34,7 +34,8 @@ func NewDB(cfg *config.Config) (*sql.DB, error) {
 
 // createTables creates the necessary tables if they don't exist
 func createTables(db *sql.DB) error {
-	query := `
+	queries := []string{
+		`
 		CREATE TABLE IF NOT EXISTS files (
 			id VARCHAR(255) PRIMARY KEY,
 			name VARCHAR(255) NOT NULL,
@@ -46,11 +47,113 @@ func createTables(db *sql.DB) error {
 			updated_at TIMESTAMP NOT NULL,
 			created_at TIMESTAMP NOT NULL
 		)
-	`
+		`,
+		// Files uploaded before text renditions were introduced have no text key
+		`ALTER TABLE files ADD COLUMN IF NOT EXISTS text_key VARCHAR(255) NOT NULL DEFAULT ''`,
+		// Deleted files are hidden at once and removed by the cleanup afterwards
+		`ALTER TABLE files ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL`,
+		// Content is stored once per hash and shared by every upload of the same bytes
+		`
+		CREATE TABLE IF NOT EXISTS blobs (
+			id VARCHAR(255) PRIMARY KEY,
+			hash VARCHAR(255) NOT NULL,
+			size BIGINT NOT NULL,
+			content_type VARCHAR(255) NOT NULL,
+			location TEXT NOT NULL,
+			text_key VARCHAR(255) NOT NULL DEFAULT '',
+			ref_count INTEGER NOT NULL DEFAULT 0,
+			released_at TIMESTAMP NULL,
+			updated_at TIMESTAMP NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)
+		`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS blobs_live_hash_idx ON blobs (hash) WHERE released_at IS NULL`,
+		`ALTER TABLE files ADD COLUMN IF NOT EXISTS blob_id VARCHAR(255) NOT NULL DEFAULT ''`,
+		`ALTER TABLE files ADD COLUMN IF NOT EXISTS uploader VARCHAR(255) NOT NULL DEFAULT ''`,
+		// Files stored before blobs were introduced own their content, their ID is the storage key
+		`
+		INSERT INTO blobs (id, hash, size, content_type, location, text_key, ref_count, updated_at, created_at)
+		SELECT id, hash, size, content_type, location, text_key, 1, updated_at, created_at
+		FROM files
+		WHERE blob_id = ''
+		ON CONFLICT DO NOTHING
+		`,
+		`UPDATE files SET blob_id = id WHERE blob_id = ''`,
+		`CREATE INDEX IF NOT EXISTS files_blob_id_idx ON files (blob_id)`,
+		// Keyset pagination indexes for every sort order and the hash prefix filter
+		`CREATE INDEX IF NOT EXISTS files_uploaded_at_id_idx ON files (uploaded_at, id) WHERE deleted_at IS NULL`,
+		`CREATE INDEX IF NOT EXISTS files_name_id_idx ON files (name, id) WHERE deleted_at IS NULL`,
+		`CREATE INDEX IF NOT EXISTS files_size_id_idx ON files (size, id) WHERE deleted_at IS NULL`,
+		`CREATE INDEX IF NOT EXISTS files_hash_prefix_idx ON files (hash varchar_pattern_ops) WHERE deleted_at IS NULL`,
+		`
+		CREATE TABLE IF NOT EXISTS upload_sessions (
+			id VARCHAR(255) PRIMARY KEY,
+			file_name VARCHAR(255) NOT NULL,
+			content_type VARCHAR(255) NOT NULL,
+			total_size BIGINT NOT NULL,
+			chunk_size BIGINT NOT NULL,
+			storage_key VARCHAR(255) NOT NULL,
+			storage_upload_id TEXT NOT NULL,
+			status VARCHAR(32) NOT NULL,
+			file_id VARCHAR(255) NOT NULL DEFAULT '',
+			expires_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)
+		`,
+		`ALTER TABLE upload_sessions ADD COLUMN IF NOT EXISTS uploader VARCHAR(255) NOT NULL DEFAULT ''`,
+		`
+		CREATE TABLE IF NOT EXISTS upload_parts (
+			session_id VARCHAR(255) NOT NULL REFERENCES upload_sessions(id) ON DELETE CASCADE,
+			part_number INTEGER NOT NULL,
+			size BIGINT NOT NULL,
+			etag VARCHAR(255) NOT NULL,
+			uploaded_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (session_id, part_number)
+		)
+		`,
+		// Courses are kept per year, submissions are files attached to an assignment of a course
+		`
+		CREATE TABLE IF NOT EXISTS courses (
+			id VARCHAR(255) PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			year INTEGER NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS assignments (
+			id VARCHAR(255) PRIMARY KEY,
+			course_id VARCHAR(255) NOT NULL REFERENCES courses(id),
+			name VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)
+		`,
+		`ALTER TABLE files ADD COLUMN IF NOT EXISTS assignment_id VARCHAR(255) NOT NULL DEFAULT ''`,
+		`ALTER TABLE upload_sessions ADD COLUMN IF NOT EXISTS assignment_id VARCHAR(255) NOT NULL DEFAULT ''`,
+		`CREATE INDEX IF NOT EXISTS files_assignment_id_idx ON files (assignment_id) WHERE deleted_at IS NULL`,
+		`CREATE INDEX IF NOT EXISTS assignments_course_id_idx ON assignments (course_id)`,
+		`CREATE INDEX IF NOT EXISTS courses_name_year_idx ON courses (name, year)`,
+		// Events are written with the change they report and removed once delivered
+		`
+		CREATE TABLE IF NOT EXISTS outbox_events (
+			id VARCHAR(255) PRIMARY KEY,
+			type VARCHAR(255) NOT NULL,
+			payload JSONB NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			next_attempt_at TIMESTAMP NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)
+		`,
+		`CREATE INDEX IF NOT EXISTS outbox_events_next_attempt_at_idx ON outbox_events (next_attempt_at)`,
+	}
 
-	_, err := db.Exec(query)
-	if err != nil {
-		return fmt.Errorf("failed to create files table: %w", err)
+	for _, query := range queries {
+		_, err := db.Exec(query)
+		if err != nil {
+			return fmt.Errorf("failed to create tables: %w", err)
+		}
 	}
 
 	return nil
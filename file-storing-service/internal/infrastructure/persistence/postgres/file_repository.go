@@ -5,13 +5,19 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	_ "github.com/lib/pq"
 
+	"filestoringservice/internal/domain/blob"
 	"filestoringservice/internal/domain/file"
+	"filestoringservice/internal/domain/outbox"
 )
 
+// fileColumns lists the columns read by every file query
+const fileColumns = `id, name, uploader, assignment_id, blob_id, hash, size, content_type, location, text_key, uploaded_at, updated_at, created_at, deleted_at`
+
 // FileRepository implements the repository.FileRepository interface with PostgreSQL
 type FileRepository struct {
 	db *sql.DB
@@ -24,22 +30,53 @@ func NewFileRepository(db *sql.DB) *FileRepository {
 	}
 }
 
-// Store saves a file to the database
-func (r *FileRepository) Store(ctx context.Context, file *file.File) error {
+// Store saves a file to the database and adds a reference to its blob in the same transaction.
+// The events are added to the outbox in that transaction too, so they are sent only for stored files.
+func (r *FileRepository) Store(ctx context.Context, file *file.File, events ...*outbox.Event) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// A released blob is about to be removed from storage and can't be referenced again
+	result, err := tx.ExecContext(ctx, `
+		UPDATE blobs
+		SET ref_count = ref_count + 1, updated_at = $2
+		WHERE id = $1 AND released_at IS NULL
+	`, file.BlobID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to reference blob: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to reference blob: %w", err)
+	}
+	if affected == 0 {
+		return blob.ErrReleased
+	}
+
 	query := `
-		INSERT INTO files (id, name, hash, size, content_type, location, uploaded_at, updated_at, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO files (id, name, uploader, assignment_id, blob_id, hash, size, content_type, location, text_key, uploaded_at, updated_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`
 
-	_, err := r.db.ExecContext(
+	_, err = tx.ExecContext(
 		ctx,
 		query,
 		file.ID,
 		file.Name,
+		file.Uploader,
+		file.AssignmentID,
+		file.BlobID,
 		file.Hash,
 		file.Size,
 		file.ContentType,
 		file.Location,
+		file.TextKey,
 		file.UploadedAt,
 		file.UpdatedAt,
 		file.CreatedAt,
@@ -48,94 +85,257 @@ func (r *FileRepository) Store(ctx context.Context, file *file.File) error {
 		return fmt.Errorf("failed to store file: %w", err)
 	}
 
+	if err = insertEvents(ctx, tx, events); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit file: %w", err)
+	}
+
 	return nil
 }
 
-// findBy implements universal find logic.
-func (r *FileRepository) findBy(ctx context.Context, key string, value any) (*file.File, error) {
-	query := fmt.Sprintf(`
-		SELECT id, name, hash, size, content_type, location, uploaded_at, updated_at, created_at
-		FROM files
-		WHERE %s = $1
-	`, key)
-
-	row := r.db.QueryRowContext(ctx, query, value)
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
 
+// scanFile reads a file selected with fileColumns
+func scanFile(row rowScanner) (*file.File, error) {
 	var f file.File
 	var uploadedAt, updatedAt, createdAt time.Time
+	var deletedAt sql.NullTime
 
 	err := row.Scan(
 		&f.ID,
 		&f.Name,
+		&f.Uploader,
+		&f.AssignmentID,
+		&f.BlobID,
 		&f.Hash,
 		&f.Size,
 		&f.ContentType,
 		&f.Location,
+		&f.TextKey,
 		&uploadedAt,
 		&updatedAt,
 		&createdAt,
+		&deletedAt,
 	)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to find file: %w", err)
+		return nil, err
 	}
 
 	f.UploadedAt = uploadedAt
 	f.UpdatedAt = updatedAt
 	f.CreatedAt = createdAt
+	if deletedAt.Valid {
+		f.DeletedAt = &deletedAt.Time
+	}
 
 	return &f, nil
 }
 
+// findBy implements universal find logic, files pending deletion are not returned.
+func (r *FileRepository) findBy(ctx context.Context, key string, value any) (*file.File, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM files
+		WHERE %s = $1 AND deleted_at IS NULL
+	`, fileColumns, key)
+
+	f, err := scanFile(r.db.QueryRowContext(ctx, query, value))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find file: %w", err)
+	}
+
+	return f, nil
+}
+
 func (r *FileRepository) FindByID(ctx context.Context, id string) (*file.File, error) {
 	return r.findBy(ctx, "id", id)
 }
 
-func (r *FileRepository) FindByHash(ctx context.Context, hash string) (*file.File, error) {
-	return r.findBy(ctx, "hash", hash)
+// List retrieves a page of files matching the query together with the total amount of matching files.
+// Pages are read with keyset pagination, so the cost of a page doesn't depend on its position.
+func (r *FileRepository) List(ctx context.Context, query file.ListQuery) (*file.Page, error) {
+	where, args := listFilters(query)
+
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM files WHERE %s`, strings.Join(where, " AND "))
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count files: %w", err)
+	}
+
+	column := string(query.SortBy)
+	direction, comparison := "ASC", ">"
+	if query.Order == file.OrderDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if query.After != nil {
+		value, err := query.After.SortValue()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value, query.After.ID)
+		where = append(where, fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, comparison, len(args)-1, len(args)))
+	}
+
+	// One extra row tells whether there is a next page
+	args = append(args, query.Limit+1)
+	pageQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM files
+		WHERE %s
+		ORDER BY %s %s, id %s
+		LIMIT $%d
+	`, fileColumns, strings.Join(where, " AND "), column, direction, direction, len(args))
+
+	files, err := r.findMany(ctx, pageQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &file.Page{Files: files, TotalCount: total}
+	if len(files) > query.Limit {
+		page.Files = files[:query.Limit]
+		page.NextCursor = file.NewCursor(page.Files[query.Limit-1], query.SortBy, query.Order).Encode()
+	}
+
+	return page, nil
 }
 
-// FindAll retrieves all files from the database
-func (r *FileRepository) FindAll(ctx context.Context) ([]*file.File, error) {
-	query := `
-		SELECT id, name, hash, size, content_type, location, uploaded_at, updated_at, created_at
+// listFilters builds the WHERE conditions of a file listing
+func listFilters(query file.ListQuery) ([]string, []any) {
+	where := []string{"deleted_at IS NULL"}
+	var args []any
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		where = append(where, fmt.Sprintf(condition, len(args)))
+	}
+
+	if query.NameContains != "" {
+		add(`name ILIKE '%%' || $%d || '%%' ESCAPE '\'`, escapeLike(query.NameContains))
+	}
+	if query.HashPrefix != "" {
+		add(`hash LIKE $%d || '%%' ESCAPE '\'`, escapeLike(query.HashPrefix))
+	}
+	if query.MinSize != nil {
+		add("size >= $%d", *query.MinSize)
+	}
+	if query.MaxSize != nil {
+		add("size <= $%d", *query.MaxSize)
+	}
+	if query.UploadedFrom != nil {
+		add("uploaded_at >= $%d", *query.UploadedFrom)
+	}
+	if query.UploadedTo != nil {
+		add("uploaded_at <= $%d", *query.UploadedTo)
+	}
+
+	return where, args
+}
+
+// escapeLike escapes LIKE wildcards so that the value is matched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// MarkDeleted hides a file and schedules its cleanup, marking an already deleted file again is a no-op.
+// The file is returned even if it was marked before, nil is returned if it doesn't exist.
+func (r *FileRepository) MarkDeleted(ctx context.Context, id string, deletedAt time.Time) (*file.File, error) {
+	query := fmt.Sprintf(`
+		UPDATE files
+		SET deleted_at = COALESCE(deleted_at, $2)
+		WHERE id = $1
+		RETURNING %s
+	`, fileColumns)
+
+	f, err := scanFile(r.db.QueryRowContext(ctx, query, id, deletedAt))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to mark file as deleted: %w", err)
+	}
+
+	return f, nil
+}
+
+// FindPendingDeletions retrieves files marked as deleted before the given time whose cleanup hasn't finished
+func (r *FileRepository) FindPendingDeletions(ctx context.Context, before time.Time, limit int) ([]*file.File, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
 		FROM files
-		ORDER BY uploaded_at DESC
-	`
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+		ORDER BY deleted_at
+		LIMIT $2
+	`, fileColumns)
 
-	rows, err := r.db.QueryContext(ctx, query)
+	return r.findMany(ctx, query, before, limit)
+}
+
+// Delete removes file metadata from the database and drops the reference to its blob in the same transaction.
+// The blob is released once no file references it. Deleting a missing file is a no-op.
+func (r *FileRepository) Delete(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var blobID string
+	err = tx.QueryRowContext(ctx, `DELETE FROM files WHERE id = $1 RETURNING blob_id`, id).Scan(&blobID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query all files: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE blobs
+		SET ref_count = ref_count - 1,
+			released_at = CASE WHEN ref_count <= 1 THEN $2 ELSE released_at END,
+			updated_at = $2
+		WHERE id = $1
+	`, blobID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to release blob: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit file deletion: %w", err)
+	}
+
+	return nil
+}
+
+// findMany runs a query selecting fileColumns and collects the result
+func (r *FileRepository) findMany(ctx context.Context, query string, args ...any) ([]*file.File, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query files: %w", err)
 	}
 	defer rows.Close()
 
 	var files []*file.File
 	for rows.Next() {
-		var f file.File
-		var uploadedAt, updatedAt, createdAt time.Time
-
-		err := rows.Scan(
-			&f.ID,
-			&f.Name,
-			&f.Hash,
-			&f.Size,
-			&f.ContentType,
-			&f.Location,
-			&uploadedAt,
-			&updatedAt,
-			&createdAt,
-		)
+		f, err := scanFile(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan file: %w", err)
 		}
 
-		f.UploadedAt = uploadedAt
-		f.UpdatedAt = updatedAt
-		f.CreatedAt = createdAt
-
-		files = append(files, &f)
+		files = append(files, f)
 	}
 
 	if err = rows.Err(); err != nil {
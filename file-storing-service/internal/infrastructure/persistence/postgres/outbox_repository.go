@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"filestoringservice/internal/domain/outbox"
+)
+
+// outboxColumns lists the columns read by every outbox query
+const outboxColumns = `id, type, payload, attempts, last_error, next_attempt_at, created_at`
+
+// OutboxRepository implements the repository.OutboxRepository interface with PostgreSQL
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository creates a new PostgreSQL outbox repository
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{
+		db: db,
+	}
+}
+
+// insertEvents adds the events to the outbox within the transaction of the change they report
+func insertEvents(ctx context.Context, tx *sql.Tx, events []*outbox.Event) error {
+	for _, event := range events {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO outbox_events (id, type, payload, attempts, last_error, next_attempt_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, event.ID, event.Type, []byte(event.Payload), event.Attempts, event.LastError, event.NextAttemptAt, event.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to store event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ClaimDue returns the due events in the order they were created and postpones them by the lease,
+// so concurrent relays never deliver the same event at once
+func (r *OutboxRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*outbox.Event, error) {
+	query := fmt.Sprintf(`
+		UPDATE outbox_events
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id
+			FROM outbox_events
+			WHERE next_attempt_at <= $1
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s
+	`, outboxColumns)
+
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(outbox.Lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*outbox.Event
+	for rows.Next() {
+		var event outbox.Event
+		var payload []byte
+
+		err := rows.Scan(&event.ID, &event.Type, &payload, &event.Attempts, &event.LastError, &event.NextAttemptAt, &event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+
+		event.Payload = payload
+		events = append(events, &event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over events: %w", err)
+	}
+
+	return events, nil
+}
+
+// Update saves the delivery attempts of an event
+func (r *OutboxRepository) Update(ctx context.Context, event *outbox.Event) error {
+	query := `UPDATE outbox_events SET attempts = $2, last_error = $3, next_attempt_at = $4 WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, event.ID, event.Attempts, event.LastError, event.NextAttemptAt)
+	if err != nil {
+		return fmt.Errorf("failed to update event: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a delivered event
+func (r *OutboxRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM outbox_events WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+
+	return nil
+}
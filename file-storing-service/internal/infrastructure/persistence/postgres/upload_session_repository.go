@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"filestoringservice/internal/domain/upload"
+)
+
+// UploadSessionRepository implements the repository.UploadSessionRepository interface with PostgreSQL
+type UploadSessionRepository struct {
+	db *sql.DB
+}
+
+// NewUploadSessionRepository creates a new PostgreSQL upload session repository
+func NewUploadSessionRepository(db *sql.DB) *UploadSessionRepository {
+	return &UploadSessionRepository{
+		db: db,
+	}
+}
+
+// Store saves a new upload session to the database
+func (r *UploadSessionRepository) Store(ctx context.Context, session *upload.Session) error {
+	query := `
+		INSERT INTO upload_sessions (id, file_name, content_type, uploader, assignment_id, total_size, chunk_size, storage_key, storage_upload_id, status, file_id, expires_at, updated_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		session.ID,
+		session.FileName,
+		session.ContentType,
+		session.Uploader,
+		session.AssignmentID,
+		session.TotalSize,
+		session.ChunkSize,
+		session.StorageKey,
+		session.StorageUploadID,
+		session.Status,
+		session.FileID,
+		session.ExpiresAt,
+		session.UpdatedAt,
+		session.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store upload session: %w", err)
+	}
+
+	return nil
+}
+
+// Update saves the mutable state of an upload session
+func (r *UploadSessionRepository) Update(ctx context.Context, session *upload.Session) error {
+	query := `
+		UPDATE upload_sessions
+		SET status = $2, file_id = $3, updated_at = $4
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, session.ID, session.Status, session.FileID, session.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update upload session: %w", err)
+	}
+
+	return nil
+}
+
+// StorePart saves an uploaded part, replacing a previous upload of the same part
+func (r *UploadSessionRepository) StorePart(ctx context.Context, sessionID string, part upload.Part) error {
+	query := `
+		INSERT INTO upload_parts (session_id, part_number, size, etag, uploaded_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (session_id, part_number) DO UPDATE SET
+			size = EXCLUDED.size,
+			etag = EXCLUDED.etag,
+			uploaded_at = EXCLUDED.uploaded_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query, sessionID, part.Number, part.Size, part.ETag, part.UploadedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store upload part: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves an upload session together with its uploaded parts
+func (r *UploadSessionRepository) FindByID(ctx context.Context, id string) (*upload.Session, error) {
+	query := `
+		SELECT id, file_name, content_type, uploader, assignment_id, total_size, chunk_size, storage_key, storage_upload_id, status, file_id, expires_at, updated_at, created_at
+		FROM upload_sessions
+		WHERE id = $1
+	`
+
+	var s upload.Session
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&s.ID,
+		&s.FileName,
+		&s.ContentType,
+		&s.Uploader,
+		&s.AssignmentID,
+		&s.TotalSize,
+		&s.ChunkSize,
+		&s.StorageKey,
+		&s.StorageUploadID,
+		&s.Status,
+		&s.FileID,
+		&s.ExpiresAt,
+		&s.UpdatedAt,
+		&s.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find upload session: %w", err)
+	}
+
+	parts, err := r.findParts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.Parts = parts
+
+	return &s, nil
+}
+
+// findParts retrieves uploaded parts of a session ordered by part number
+func (r *UploadSessionRepository) findParts(ctx context.Context, sessionID string) ([]upload.Part, error) {
+	query := `
+		SELECT part_number, size, etag, uploaded_at
+		FROM upload_parts
+		WHERE session_id = $1
+		ORDER BY part_number
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query upload parts: %w", err)
+	}
+	defer rows.Close()
+
+	parts := []upload.Part{}
+	for rows.Next() {
+		var part upload.Part
+		if err := rows.Scan(&part.Number, &part.Size, &part.ETag, &part.UploadedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan upload part: %w", err)
+		}
+		parts = append(parts, part)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over upload parts: %w", err)
+	}
+
+	return parts, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -70,6 +71,27 @@ func (s *FileStorage) Upload(_ context.Context, fileData *os.File) (*UploadedFil
 	}, nil
 }
 
+// UploadText uploads the plain-text rendition of a file and returns the uploaded text information.
+func (s *FileStorage) UploadText(ctx context.Context, fileKey string, text string) (*UploadedFileInfo, error) {
+	textKey := fileKey + ".txt"
+
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(textKey),
+		Body:        strings.NewReader(text),
+		ContentType: aws.String("text/plain; charset=utf-8"),
+		ACL:         aws.String(s3.ObjectCannedACLPrivate),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload text to S3: %w", err)
+	}
+
+	return &UploadedFileInfo{
+		ID:       textKey,
+		Location: fmt.Sprintf("%s/%s/%s", s.endpoint, s.bucket, textKey),
+	}, nil
+}
+
 // Download downloads a file from S3 and returns a reader for the file content.
 func (s *FileStorage) Download(ctx context.Context, fileKey string) (io.ReadCloser, error) {
 	result, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
@@ -82,3 +104,106 @@ func (s *FileStorage) Download(ctx context.Context, fileKey string) (io.ReadClos
 
 	return result.Body, nil
 }
+
+// MultipartUpload represents a started S3 multipart upload.
+type MultipartUpload struct {
+	Key      string
+	UploadID string
+}
+
+// CompletedPart represents an uploaded part of a multipart upload.
+type CompletedPart struct {
+	Number int
+	ETag   string
+}
+
+// CreateMultipartUpload starts a new S3 multipart upload under a fresh key.
+func (s *FileStorage) CreateMultipartUpload(ctx context.Context, contentType string) (*MultipartUpload, error) {
+	fileKey := uuid.New().String()
+
+	result, err := s.client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(fileKey),
+		ContentType: aws.String(contentType),
+		ACL:         aws.String(s3.ObjectCannedACLPrivate),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+
+	return &MultipartUpload{
+		Key:      fileKey,
+		UploadID: aws.StringValue(result.UploadId),
+	}, nil
+}
+
+// UploadPart uploads a single part of a multipart upload and returns its ETag.
+func (s *FileStorage) UploadPart(ctx context.Context, upload *MultipartUpload, partNumber int, data io.ReadSeeker) (string, error) {
+	result, err := s.client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(s.bucket),
+		Key:        aws.String(upload.Key),
+		UploadId:   aws.String(upload.UploadID),
+		PartNumber: aws.Int64(int64(partNumber)),
+		Body:       data,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+	}
+
+	return aws.StringValue(result.ETag), nil
+}
+
+// CompleteMultipartUpload assembles the uploaded parts into a single object.
+func (s *FileStorage) CompleteMultipartUpload(ctx context.Context, upload *MultipartUpload, parts []CompletedPart) (*UploadedFileInfo, error) {
+	completedParts := make([]*s3.CompletedPart, len(parts))
+	for i, part := range parts {
+		completedParts[i] = &s3.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int64(int64(part.Number)),
+		}
+	}
+
+	_, err := s.client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(upload.Key),
+		UploadId: aws.String(upload.UploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{
+			Parts: completedParts,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	return &UploadedFileInfo{
+		ID:       upload.Key,
+		Location: fmt.Sprintf("%s/%s/%s", s.endpoint, s.bucket, upload.Key),
+	}, nil
+}
+
+// AbortMultipartUpload discards a multipart upload and all of its uploaded parts.
+func (s *FileStorage) AbortMultipartUpload(ctx context.Context, upload *MultipartUpload) error {
+	_, err := s.client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(upload.Key),
+		UploadId: aws.String(upload.UploadID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to abort multipart upload: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a file from S3.
+func (s *FileStorage) Delete(ctx context.Context, fileKey string) error {
+	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(fileKey),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete file from S3: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,364 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"filestoringservice/internal/application/service"
+	"filestoringservice/internal/domain/course"
+)
+
+// CourseHandler handles HTTP requests related to courses, assignments and submissions
+type CourseHandler struct {
+	courseService *service.CourseService
+}
+
+// CreateCourseRequest represents the request body for creating a course
+type CreateCourseRequest struct {
+	Name string `json:"name" example:"Algorithms"`
+	Year int    `json:"year" example:"2024"`
+}
+
+// CourseResponse represents a course
+type CourseResponse struct {
+	ID        string `json:"id" example:"12345678-1234-1234-1234-123456789012"`
+	Name      string `json:"name" example:"Algorithms"`
+	Year      int    `json:"year" example:"2024"`
+	CreatedAt string `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// CreateAssignmentRequest represents the request body for creating an assignment
+type CreateAssignmentRequest struct {
+	Name string `json:"name" example:"Homework 1"`
+}
+
+// AssignmentResponse represents an assignment
+type AssignmentResponse struct {
+	ID        string `json:"id" example:"12345678-1234-1234-1234-123456789012"`
+	CourseID  string `json:"course_id" example:"12345678-1234-1234-1234-123456789012"`
+	Name      string `json:"name" example:"Homework 1"`
+	CreatedAt string `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// SubmissionResponse represents a file submitted to an assignment
+type SubmissionResponse struct {
+	FileID       string `json:"file_id" example:"12345678-1234-1234-1234-123456789012"`
+	FileName     string `json:"file_name" example:"document.pdf"`
+	AssignmentID string `json:"assignment_id" example:"12345678-1234-1234-1234-123456789012"`
+	Student      string `json:"student" example:"ivanov"`
+	SubmittedAt  string `json:"submitted_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// ScopeResponse represents the files a submitted file is checked against
+type ScopeResponse struct {
+	FileID       string   `json:"file_id" example:"12345678-1234-1234-1234-123456789012"`
+	Scope        string   `json:"scope" example:"assignment"`
+	AssignmentID string   `json:"assignment_id"`
+	CourseID     string   `json:"course_id"`
+	FileIDs      []string `json:"file_ids"`
+}
+
+func NewCourseHandler(courseService *service.CourseService) *CourseHandler {
+	return &CourseHandler{
+		courseService: courseService,
+	}
+}
+
+// CreateCourse handles requests to create a course
+// @Summary Create a course
+// @Description Create a course taught in the given year, every year of the same course is a separate course with the same name
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Param request body CreateCourseRequest true "Course"
+// @Success 201 {object} CourseResponse "Course created"
+// @Failure 400 {object} ErrorResponse "Bad request"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /courses [post]
+func (h *CourseHandler) CreateCourse(w http.ResponseWriter, r *http.Request) {
+	var request CreateCourseRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, "Failed to decode request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c, err := h.courseService.CreateCourse(r.Context(), request.Name, request.Year)
+	if err != nil {
+		http.Error(w, "Failed to create course: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	err = json.NewEncoder(w).Encode(courseResponse(c))
+	if err != nil {
+		return
+	}
+}
+
+// ListCourses handles requests to list courses
+// @Summary List courses
+// @Description Get all courses, the latest years first
+// @Tags courses
+// @Produce json
+// @Success 200 {array} CourseResponse "Courses"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /courses [get]
+func (h *CourseHandler) ListCourses(w http.ResponseWriter, r *http.Request) {
+	courses, err := h.courseService.ListCourses(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to get courses: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	responses := make([]map[string]any, 0, len(courses))
+	for _, c := range courses {
+		responses = append(responses, courseResponse(c))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(responses)
+	if err != nil {
+		return
+	}
+}
+
+// GetCourse handles requests to retrieve a course
+// @Summary Get a course by ID
+// @Description Get course information by its ID
+// @Tags courses
+// @Produce json
+// @Param id path string true "Course ID"
+// @Success 200 {object} CourseResponse "Course"
+// @Failure 404 {object} ErrorResponse "Course not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /courses/{id} [get]
+func (h *CourseHandler) GetCourse(w http.ResponseWriter, r *http.Request) {
+	c, err := h.courseService.GetCourse(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Failed to get course: "+err.Error(), courseErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(courseResponse(c))
+	if err != nil {
+		return
+	}
+}
+
+// CreateAssignment handles requests to create an assignment of a course
+// @Summary Create an assignment
+// @Description Create an assignment of the course, files are submitted to it by passing assignment_id on upload
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Param id path string true "Course ID"
+// @Param request body CreateAssignmentRequest true "Assignment"
+// @Success 201 {object} AssignmentResponse "Assignment created"
+// @Failure 400 {object} ErrorResponse "Bad request"
+// @Failure 404 {object} ErrorResponse "Course not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /courses/{id}/assignments [post]
+func (h *CourseHandler) CreateAssignment(w http.ResponseWriter, r *http.Request) {
+	var request CreateAssignmentRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, "Failed to decode request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	assignment, err := h.courseService.CreateAssignment(r.Context(), r.PathValue("id"), request.Name)
+	if err != nil {
+		status := courseErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, "Failed to create assignment: "+err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	err = json.NewEncoder(w).Encode(assignmentResponse(assignment))
+	if err != nil {
+		return
+	}
+}
+
+// ListAssignments handles requests to list the assignments of a course
+// @Summary List course assignments
+// @Description Get the assignments of the course in the order they were created
+// @Tags courses
+// @Produce json
+// @Param id path string true "Course ID"
+// @Success 200 {array} AssignmentResponse "Assignments"
+// @Failure 404 {object} ErrorResponse "Course not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /courses/{id}/assignments [get]
+func (h *CourseHandler) ListAssignments(w http.ResponseWriter, r *http.Request) {
+	assignments, err := h.courseService.ListAssignments(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Failed to get assignments: "+err.Error(), courseErrorStatus(err))
+		return
+	}
+
+	responses := make([]map[string]any, 0, len(assignments))
+	for _, assignment := range assignments {
+		responses = append(responses, assignmentResponse(assignment))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(responses)
+	if err != nil {
+		return
+	}
+}
+
+// GetAssignment handles requests to retrieve an assignment
+// @Summary Get an assignment by ID
+// @Description Get assignment information by its ID
+// @Tags courses
+// @Produce json
+// @Param id path string true "Assignment ID"
+// @Success 200 {object} AssignmentResponse "Assignment"
+// @Failure 404 {object} ErrorResponse "Assignment not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /assignments/{id} [get]
+func (h *CourseHandler) GetAssignment(w http.ResponseWriter, r *http.Request) {
+	assignment, err := h.courseService.GetAssignment(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Failed to get assignment: "+err.Error(), courseErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(assignmentResponse(assignment))
+	if err != nil {
+		return
+	}
+}
+
+// ListSubmissions handles requests to list the files submitted to an assignment
+// @Summary List assignment submissions
+// @Description Get the files submitted to the assignment, the earliest first
+// @Tags courses
+// @Produce json
+// @Param id path string true "Assignment ID"
+// @Success 200 {array} SubmissionResponse "Submissions"
+// @Failure 404 {object} ErrorResponse "Assignment not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /assignments/{id}/submissions [get]
+func (h *CourseHandler) ListSubmissions(w http.ResponseWriter, r *http.Request) {
+	submissions, err := h.courseService.ListSubmissions(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Failed to get submissions: "+err.Error(), courseErrorStatus(err))
+		return
+	}
+
+	responses := make([]map[string]any, 0, len(submissions))
+	for _, submission := range submissions {
+		responses = append(responses, map[string]any{
+			"file_id":       submission.FileID,
+			"file_name":     submission.FileName,
+			"assignment_id": submission.AssignmentID,
+			"student":       submission.Student,
+			"submitted_at":  submission.SubmittedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(responses)
+	if err != nil {
+		return
+	}
+}
+
+// GetScope handles requests to resolve the search scope of a file
+// @Summary Get the search scope of a file
+// @Description List the files a file is checked against for plagiarism: submissions to the same assignment, to the same course,
+// @Description to the courses of the same name in earlier years, or all files (file_ids is null then). The file itself is never listed.
+// @Tags courses
+// @Produce json
+// @Param id path string true "File ID"
+// @Param scope query string false "Scope: assignment, course, prior_years or all (default)"
+// @Success 200 {object} ScopeResponse "Search scope"
+// @Failure 400 {object} ErrorResponse "Invalid scope"
+// @Failure 404 {object} ErrorResponse "File not found"
+// @Failure 409 {object} ErrorResponse "File is not submitted to an assignment"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /files/{id}/scope [get]
+func (h *CourseHandler) GetScope(w http.ResponseWriter, r *http.Request) {
+	scope, err := course.ParseScope(r.URL.Query().Get("scope"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	scopeFiles, err := h.courseService.ResolveScope(r.Context(), r.PathValue("id"), scope)
+	if err != nil {
+		http.Error(w, "Failed to resolve scope: "+err.Error(), courseErrorStatus(err))
+		return
+	}
+
+	if scopeFiles == nil {
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(map[string]any{
+		"file_id":       scopeFiles.FileID,
+		"scope":         scopeFiles.Scope,
+		"assignment_id": scopeFiles.AssignmentID,
+		"course_id":     scopeFiles.CourseID,
+		"file_ids":      scopeFiles.FileIDs,
+	})
+	if err != nil {
+		return
+	}
+}
+
+// courseErrorStatus maps course domain errors to HTTP status codes
+func courseErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, course.ErrCourseNotFound), errors.Is(err, course.ErrAssignmentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, course.ErrInvalidScope):
+		return http.StatusBadRequest
+	case errors.Is(err, course.ErrNotSubmitted):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// courseResponse converts a course to its response format
+func courseResponse(c *course.Course) map[string]any {
+	return map[string]any{
+		"id":         c.ID,
+		"name":       c.Name,
+		"year":       c.Year,
+		"created_at": c.CreatedAt,
+	}
+}
+
+// assignmentResponse converts an assignment to its response format
+func assignmentResponse(assignment *course.Assignment) map[string]any {
+	return map[string]any{
+		"id":         assignment.ID,
+		"course_id":  assignment.CourseID,
+		"name":       assignment.Name,
+		"created_at": assignment.CreatedAt,
+	}
+}
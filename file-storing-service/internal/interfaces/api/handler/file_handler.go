@@ -2,12 +2,17 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"filestoringservice/internal/application/service"
+	"filestoringservice/internal/domain/course"
 	"filestoringservice/internal/domain/file"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // FileHandler handles HTTP requests related to files
@@ -17,13 +22,22 @@ type FileHandler struct {
 
 // FileResponse represents the response structure for file operations
 type FileResponse struct {
-	ID          string `json:"id" example:"12345678-1234-1234-1234-123456789012"`
-	Name        string `json:"name" example:"document.pdf"`
-	Hash        string `json:"hash"`
-	Size        int64  `json:"size" example:"1048576"`
-	ContentType string `json:"content_type" example:"application/pdf"`
-	Location    string `json:"location" example:"files/12345678-1234-1234-1234-123456789012"`
-	UploadedAt  string `json:"uploaded_at" example:"2023-01-01T12:00:00Z"`
+	ID           string `json:"id" example:"12345678-1234-1234-1234-123456789012"`
+	Name         string `json:"name" example:"document.pdf"`
+	Uploader     string `json:"uploader" example:"ivanov"`
+	AssignmentID string `json:"assignment_id"`
+	Hash         string `json:"hash"`
+	Size         int64  `json:"size" example:"1048576"`
+	ContentType  string `json:"content_type" example:"application/pdf"`
+	Location     string `json:"location" example:"files/12345678-1234-1234-1234-123456789012"`
+	UploadedAt   string `json:"uploaded_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// FileListResponse represents a page of files
+type FileListResponse struct {
+	Files      []FileResponse `json:"files"`
+	TotalCount int            `json:"total_count" example:"1"`
+	NextCursor string         `json:"next_cursor" example:""`
 }
 
 // ErrorResponse represents an error response
@@ -40,13 +54,16 @@ func NewFileHandler(fileService *service.FileService) *FileHandler {
 
 // UploadFile handles file upload requests
 // @Summary Upload a file
-// @Description Upload a new file to the server
+// @Description Upload a new file to the server (plain text, HTML, PDF, DOCX or ODT)
 // @Tags files
 // @Accept multipart/form-data
 // @Produce json
 // @Param file formData file true "File to upload"
+// @Param uploader formData string false "Who submits the file"
+// @Param assignment_id formData string false "Assignment the file is submitted to"
 // @Success 201 {object} FileResponse "File uploaded successfully"
 // @Failure 400 {object} ErrorResponse "Bad request"
+// @Failure 404 {object} ErrorResponse "Assignment not found"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /files [post]
 func (h *FileHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
@@ -75,10 +92,16 @@ func (h *FileHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
 	filename := header.Filename
 	contentType := header.Header.Get("Content-Type")
 	size := header.Size
+	uploader := r.FormValue("uploader")
+	assignmentID := r.FormValue("assignment_id")
 
 	// Upload formFile
-	fileModel, err := h.fileService.UploadFile(r.Context(), filename, contentType, size, formFile)
+	fileModel, err := h.fileService.UploadFile(r.Context(), filename, contentType, uploader, assignmentID, size, formFile)
 	if err != nil {
+		if errors.Is(err, course.ErrAssignmentNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
 		http.Error(w, "Failed to upload formFile: "+err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -89,13 +112,15 @@ func (h *FileHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
 
 	// Response structure
 	response := map[string]any{
-		"id":           fileModel.ID,
-		"name":         fileModel.Name,
-		"hash":         fileModel.Hash,
-		"size":         fileModel.Size,
-		"content_type": fileModel.ContentType,
-		"location":     fileModel.Location,
-		"uploaded_at":  fileModel.UploadedAt,
+		"id":            fileModel.ID,
+		"name":          fileModel.Name,
+		"uploader":      fileModel.Uploader,
+		"assignment_id": fileModel.AssignmentID,
+		"hash":          fileModel.Hash,
+		"size":          fileModel.Size,
+		"content_type":  fileModel.ContentType,
+		"location":      fileModel.Location,
+		"uploaded_at":   fileModel.UploadedAt,
 	}
 
 	err = json.NewEncoder(w).Encode(response)
@@ -138,12 +163,14 @@ func (h *FileHandler) GetFile(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	response := map[string]any{
-		"id":           fileModel.ID,
-		"name":         fileModel.Name,
-		"size":         fileModel.Size,
-		"content_type": fileModel.ContentType,
-		"location":     fileModel.Location,
-		"uploaded_at":  fileModel.UploadedAt,
+		"id":            fileModel.ID,
+		"name":          fileModel.Name,
+		"uploader":      fileModel.Uploader,
+		"assignment_id": fileModel.AssignmentID,
+		"size":          fileModel.Size,
+		"content_type":  fileModel.ContentType,
+		"location":      fileModel.Location,
+		"uploaded_at":   fileModel.UploadedAt,
 	}
 
 	err = json.NewEncoder(w).Encode(response)
@@ -152,47 +179,144 @@ func (h *FileHandler) GetFile(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// GetAllFiles handles requests to retrieve all files
-// @Summary Get all files
-// @Description Get information for all uploaded files
+// ListFiles handles requests to retrieve a page of files
+// @Summary List files
+// @Description Get a page of uploaded files with optional filters, pass next_cursor as cursor to get the next page
 // @Tags files
 // @Accept json
 // @Produce json
-// @Success 200 {array} FileResponse "List of all files"
+// @Param name query string false "Name substring (case-insensitive)"
+// @Param hash_prefix query string false "Hash prefix"
+// @Param min_size query int false "Minimal size in bytes"
+// @Param max_size query int false "Maximal size in bytes"
+// @Param uploaded_from query string false "Uploaded at or after (RFC 3339)"
+// @Param uploaded_to query string false "Uploaded at or before (RFC 3339)"
+// @Param sort query string false "Sort field: uploaded_at (default), name or size"
+// @Param order query string false "Sort order: desc (default) or asc"
+// @Param limit query int false "Page size, 50 by default, 500 at most"
+// @Param cursor query string false "Cursor of the next page"
+// @Success 200 {object} FileListResponse "Page of files"
+// @Failure 400 {object} ErrorResponse "Invalid query"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /files [get]
-func (h *FileHandler) GetAllFiles(w http.ResponseWriter, r *http.Request) {
-	// Get all files
-	files, err := h.fileService.GetAllFiles(r.Context())
+func (h *FileHandler) ListFiles(w http.ResponseWriter, r *http.Request) {
+	query, err := parseListQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.fileService.ListFiles(r.Context(), query)
 	if err != nil {
+		if errors.Is(err, file.ErrInvalidQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, "Failed to get files: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	// Convert to response format
-	var responses []map[string]any
-	for _, fileModel := range files {
+	responses := make([]map[string]any, 0, len(page.Files))
+	for _, fileModel := range page.Files {
 		response := map[string]any{
-			"id":           fileModel.ID,
-			"name":         fileModel.Name,
-			"hash":         fileModel.Hash,
-			"size":         fileModel.Size,
-			"content_type": fileModel.ContentType,
-			"location":     fileModel.Location,
-			"uploaded_at":  fileModel.UploadedAt,
+			"id":            fileModel.ID,
+			"name":          fileModel.Name,
+			"uploader":      fileModel.Uploader,
+			"assignment_id": fileModel.AssignmentID,
+			"hash":          fileModel.Hash,
+			"size":          fileModel.Size,
+			"content_type":  fileModel.ContentType,
+			"location":      fileModel.Location,
+			"uploaded_at":   fileModel.UploadedAt,
 		}
 		responses = append(responses, response)
 	}
 
 	// Return files as JSON
 	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(responses)
+	err = json.NewEncoder(w).Encode(map[string]any{
+		"files":       responses,
+		"total_count": page.TotalCount,
+		"next_cursor": page.NextCursor,
+	})
 	if err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
+// parseListQuery reads listing parameters from the query string
+func parseListQuery(r *http.Request) (file.ListQuery, error) {
+	values := r.URL.Query()
+	query := file.ListQuery{
+		NameContains: values.Get("name"),
+		HashPrefix:   strings.ToLower(values.Get("hash_prefix")),
+		SortBy:       file.SortField(values.Get("sort")),
+		Order:        file.SortOrder(values.Get("order")),
+	}
+
+	var err error
+	if query.MinSize, err = parseIntParam(values.Get("min_size"), "min_size"); err != nil {
+		return query, err
+	}
+	if query.MaxSize, err = parseIntParam(values.Get("max_size"), "max_size"); err != nil {
+		return query, err
+	}
+	if query.UploadedFrom, err = parseTimeParam(values.Get("uploaded_from"), "uploaded_from"); err != nil {
+		return query, err
+	}
+	if query.UploadedTo, err = parseTimeParam(values.Get("uploaded_to"), "uploaded_to"); err != nil {
+		return query, err
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return query, fmt.Errorf("limit must be an integer")
+		}
+	}
+
+	if cursor := values.Get("cursor"); cursor != "" {
+		query.After, err = file.ParseCursor(cursor)
+		if err != nil {
+			return query, err
+		}
+	}
+
+	return query, nil
+}
+
+// parseIntParam parses an optional integer query parameter
+func parseIntParam(value, name string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an integer", name)
+	}
+
+	return &parsed, nil
+}
+
+// parseTimeParam parses an optional RFC 3339 query parameter
+func parseTimeParam(value, name string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC 3339 date", name)
+	}
+
+	// Upload dates are stored in UTC without a time zone
+	parsed = parsed.UTC()
+	return &parsed, nil
+}
+
 // DownloadFile handles file download requests
 // @Summary Download a file by ID
 // @Description Download the actual file content by its ID
@@ -215,7 +339,7 @@ func (h *FileHandler) DownloadFile(w http.ResponseWriter, r *http.Request) {
 	// Download file content and get metadata
 	fileReader, fileModel, err := h.fileService.DownloadFile(r.Context(), id)
 	if err != nil {
-		if err.Error() == "file not found" {
+		if errors.Is(err, file.ErrNotFound) {
 			http.Error(w, "File not found", http.StatusNotFound)
 			return
 		}
@@ -242,3 +366,78 @@ func (h *FileHandler) DownloadFile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// DownloadText handles plain-text rendition download requests
+// @Summary Download the plain text of a file by ID
+// @Description Download the normalized plain text extracted from the file (PDF, DOCX, ODT, HTML or plain text)
+// @Tags files
+// @Produce plain
+// @Param id path string true "File ID"
+// @Success 200 {string} string "File text"
+// @Failure 400 {object} ErrorResponse "Bad request"
+// @Failure 404 {object} ErrorResponse "File not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /files/{id}/text [get]
+func (h *FileHandler) DownloadText(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if id == "" {
+		http.Error(w, "File ID is required", http.StatusBadRequest)
+		return
+	}
+
+	textReader, _, err := h.fileService.DownloadText(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, file.ErrNotFound) {
+			http.Error(w, "File not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to download text: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	defer func() {
+		if closeErr := textReader.Close(); closeErr != nil {
+			fmt.Printf("Failed to close text reader: %v\n", closeErr)
+		}
+	}()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	_, err = io.Copy(w, textReader)
+	if err != nil {
+		http.Error(w, "Failed to stream text content", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteFile handles file deletion requests
+// @Summary Delete a file
+// @Description Delete a file together with its text rendition and analysis data, repeating the request is safe
+// @Tags files
+// @Param id path string true "File ID"
+// @Success 204 "File deleted"
+// @Success 202 "File deleted, cleanup failed and will be retried"
+// @Failure 404 {object} ErrorResponse "File not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /files/{id} [delete]
+func (h *FileHandler) DeleteFile(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	fileModel, err := h.fileService.DeleteFile(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrDeletionPending) {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		http.Error(w, "Failed to delete file: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if fileModel == nil {
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
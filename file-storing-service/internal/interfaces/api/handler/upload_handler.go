@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"filestoringservice/internal/application/service"
+	"filestoringservice/internal/domain/course"
+	"filestoringservice/internal/domain/file"
+	"filestoringservice/internal/domain/upload"
+)
+
+// UploadHandler handles HTTP requests related to resumable uploads
+type UploadHandler struct {
+	uploadService *service.UploadService
+}
+
+// CreateUploadSessionRequest represents the request body for starting a resumable upload
+type CreateUploadSessionRequest struct {
+	Name         string `json:"name" example:"document.pdf"`
+	ContentType  string `json:"content_type" example:"application/pdf"`
+	Uploader     string `json:"uploader" example:"ivanov"`
+	AssignmentID string `json:"assignment_id"`
+	Size         int64  `json:"size" example:"1048576"`
+}
+
+// UploadSessionResponse represents the state of a resumable upload
+type UploadSessionResponse struct {
+	ID             string `json:"id" example:"12345678-1234-1234-1234-123456789012"`
+	Name           string `json:"name" example:"document.pdf"`
+	ContentType    string `json:"content_type" example:"application/pdf"`
+	Uploader       string `json:"uploader" example:"ivanov"`
+	AssignmentID   string `json:"assignment_id"`
+	Size           int64  `json:"size" example:"1048576"`
+	ChunkSize      int64  `json:"chunk_size" example:"5242880"`
+	TotalChunks    int    `json:"total_chunks" example:"1"`
+	UploadedChunks []int  `json:"uploaded_chunks"`
+	MissingChunks  []int  `json:"missing_chunks"`
+	ReceivedBytes  int64  `json:"received_bytes" example:"0"`
+	Status         string `json:"status" example:"pending"`
+	FileID         string `json:"file_id,omitempty"`
+	ExpiresAt      string `json:"expires_at" example:"2023-01-02T12:00:00Z"`
+}
+
+func NewUploadHandler(uploadService *service.UploadService) *UploadHandler {
+	return &UploadHandler{
+		uploadService: uploadService,
+	}
+}
+
+// CreateUploadSession handles requests to start a resumable upload
+// @Summary Start a resumable upload
+// @Description Create an upload session, the file is then sent as numbered chunks of chunk_size bytes
+// @Tags uploads
+// @Accept json
+// @Produce json
+// @Param request body CreateUploadSessionRequest true "File metadata"
+// @Success 201 {object} UploadSessionResponse "Upload session created"
+// @Failure 400 {object} ErrorResponse "Bad request"
+// @Failure 404 {object} ErrorResponse "Assignment not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /uploads [post]
+func (h *UploadHandler) CreateUploadSession(w http.ResponseWriter, r *http.Request) {
+	var request CreateUploadSessionRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, "Failed to decode request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.uploadService.CreateSession(r.Context(), request.Name, request.ContentType, request.Uploader, request.AssignmentID, request.Size)
+	if err != nil {
+		if errors.Is(err, course.ErrAssignmentNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to create upload session: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	err = json.NewEncoder(w).Encode(uploadSessionResponse(session))
+	if err != nil {
+		return
+	}
+}
+
+// GetUploadSession handles requests to retrieve the state of a resumable upload
+// @Summary Get a resumable upload
+// @Description Get the upload session state, used to find out which chunks are missing after a dropped connection
+// @Tags uploads
+// @Produce json
+// @Param id path string true "Upload session ID"
+// @Success 200 {object} UploadSessionResponse "Upload session"
+// @Failure 404 {object} ErrorResponse "Upload session not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /uploads/{id} [get]
+func (h *UploadHandler) GetUploadSession(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	session, err := h.uploadService.GetSession(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Failed to get upload session: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if session == nil {
+		http.Error(w, "Upload session not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(uploadSessionResponse(session))
+	if err != nil {
+		return
+	}
+}
+
+// UploadChunk handles requests to upload a single chunk
+// @Summary Upload a chunk
+// @Description Upload a numbered chunk (starting from 1) of a resumable upload, a chunk can be sent again to replace it
+// @Tags uploads
+// @Accept application/octet-stream
+// @Produce json
+// @Param id path string true "Upload session ID"
+// @Param number path int true "Chunk number"
+// @Param chunk body string true "Chunk content"
+// @Success 200 {object} UploadSessionResponse "Chunk uploaded"
+// @Failure 400 {object} ErrorResponse "Invalid chunk"
+// @Failure 404 {object} ErrorResponse "Upload session not found"
+// @Failure 409 {object} ErrorResponse "Upload session is closed"
+// @Failure 410 {object} ErrorResponse "Upload session has expired"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /uploads/{id}/chunks/{number} [put]
+func (h *UploadHandler) UploadChunk(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		http.Error(w, "Chunk number must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.uploadService.UploadChunk(r.Context(), id, number, r.Body)
+	if err != nil {
+		http.Error(w, "Failed to upload chunk: "+err.Error(), uploadErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(uploadSessionResponse(session))
+	if err != nil {
+		return
+	}
+}
+
+// CompleteUploadSession handles requests to finalize a resumable upload
+// @Summary Complete a resumable upload
+// @Description Assemble the uploaded chunks into a file
+// @Tags uploads
+// @Produce json
+// @Param id path string true "Upload session ID"
+// @Success 201 {object} FileResponse "File uploaded successfully"
+// @Failure 404 {object} ErrorResponse "Upload session not found"
+// @Failure 409 {object} ErrorResponse "Upload session is closed or has missing chunks"
+// @Failure 410 {object} ErrorResponse "Upload session has expired"
+// @Failure 415 {object} ErrorResponse "Unsupported document format"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /uploads/{id}/complete [post]
+func (h *UploadHandler) CompleteUploadSession(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	fileModel, err := h.uploadService.CompleteSession(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Failed to complete upload: "+err.Error(), uploadErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := map[string]any{
+		"id":            fileModel.ID,
+		"name":          fileModel.Name,
+		"uploader":      fileModel.Uploader,
+		"assignment_id": fileModel.AssignmentID,
+		"hash":          fileModel.Hash,
+		"size":          fileModel.Size,
+		"content_type":  fileModel.ContentType,
+		"location":      fileModel.Location,
+		"uploaded_at":   fileModel.UploadedAt,
+	}
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		return
+	}
+}
+
+// AbortUploadSession handles requests to cancel a resumable upload
+// @Summary Abort a resumable upload
+// @Description Discard the upload session and all uploaded chunks
+// @Tags uploads
+// @Param id path string true "Upload session ID"
+// @Success 204 "Upload aborted"
+// @Failure 404 {object} ErrorResponse "Upload session not found"
+// @Failure 409 {object} ErrorResponse "Upload session is closed"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /uploads/{id} [delete]
+func (h *UploadHandler) AbortUploadSession(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	err := h.uploadService.AbortSession(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Failed to abort upload: "+err.Error(), uploadErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// uploadErrorStatus maps upload domain errors to HTTP status codes
+func uploadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, upload.ErrSessionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, upload.ErrInvalidChunk):
+		return http.StatusBadRequest
+	case errors.Is(err, file.ErrUnsupportedContentType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, upload.ErrSessionClosed), errors.Is(err, upload.ErrIncomplete):
+		return http.StatusConflict
+	case errors.Is(err, upload.ErrSessionExpired):
+		return http.StatusGone
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// uploadSessionResponse converts an upload session to its response format
+func uploadSessionResponse(session *upload.Session) map[string]any {
+	uploadedChunks := make([]int, len(session.Parts))
+	for i, part := range session.Parts {
+		uploadedChunks[i] = part.Number
+	}
+
+	return map[string]any{
+		"id":              session.ID,
+		"name":            session.FileName,
+		"content_type":    session.ContentType,
+		"uploader":        session.Uploader,
+		"assignment_id":   session.AssignmentID,
+		"size":            session.TotalSize,
+		"chunk_size":      session.ChunkSize,
+		"total_chunks":    session.TotalChunks(),
+		"uploaded_chunks": uploadedChunks,
+		"missing_chunks":  session.MissingChunks(),
+		"received_bytes":  session.ReceivedBytes(),
+		"status":          session.Status,
+		"file_id":         session.FileID,
+		"expires_at":      session.ExpiresAt,
+	}
+}
@@ -10,17 +10,21 @@ import (
 
 // Router handles HTTP routing
 type Router struct {
-	fileHandler *handler.FileHandler
-	infoHandler *handler.InfoHandler
-	docsHandler *handler.DocsHandler
+	fileHandler   *handler.FileHandler
+	uploadHandler *handler.UploadHandler
+	courseHandler *handler.CourseHandler
+	infoHandler   *handler.InfoHandler
+	docsHandler   *handler.DocsHandler
 }
 
 // NewRouter creates a new router
-func NewRouter(fileHandler *handler.FileHandler, infoHandler *handler.InfoHandler, docsHandler *handler.DocsHandler) *Router {
+func NewRouter(fileHandler *handler.FileHandler, uploadHandler *handler.UploadHandler, courseHandler *handler.CourseHandler, infoHandler *handler.InfoHandler, docsHandler *handler.DocsHandler) *Router {
 	return &Router{
-		fileHandler: fileHandler,
-		infoHandler: infoHandler,
-		docsHandler: docsHandler,
+		fileHandler:   fileHandler,
+		uploadHandler: uploadHandler,
+		courseHandler: courseHandler,
+		infoHandler:   infoHandler,
+		docsHandler:   docsHandler,
 	}
 }
 
@@ -33,9 +37,28 @@ func (r *Router) SetupRoutes() http.Handler {
 
 	// File routes
 	mux.HandleFunc("POST /store-api/files", r.fileHandler.UploadFile)
-	mux.HandleFunc("GET /store-api/files", r.fileHandler.GetAllFiles)
+	mux.HandleFunc("GET /store-api/files", r.fileHandler.ListFiles)
 	mux.HandleFunc("GET /store-api/files/{id}", r.fileHandler.GetFile)
+	mux.HandleFunc("DELETE /store-api/files/{id}", r.fileHandler.DeleteFile)
 	mux.HandleFunc("GET /store-api/files/{id}/download", r.fileHandler.DownloadFile)
+	mux.HandleFunc("GET /store-api/files/{id}/text", r.fileHandler.DownloadText)
+
+	// Resumable upload routes
+	mux.HandleFunc("POST /store-api/uploads", r.uploadHandler.CreateUploadSession)
+	mux.HandleFunc("GET /store-api/uploads/{id}", r.uploadHandler.GetUploadSession)
+	mux.HandleFunc("PUT /store-api/uploads/{id}/chunks/{number}", r.uploadHandler.UploadChunk)
+	mux.HandleFunc("POST /store-api/uploads/{id}/complete", r.uploadHandler.CompleteUploadSession)
+	mux.HandleFunc("DELETE /store-api/uploads/{id}", r.uploadHandler.AbortUploadSession)
+
+	// Course routes
+	mux.HandleFunc("POST /store-api/courses", r.courseHandler.CreateCourse)
+	mux.HandleFunc("GET /store-api/courses", r.courseHandler.ListCourses)
+	mux.HandleFunc("GET /store-api/courses/{id}", r.courseHandler.GetCourse)
+	mux.HandleFunc("POST /store-api/courses/{id}/assignments", r.courseHandler.CreateAssignment)
+	mux.HandleFunc("GET /store-api/courses/{id}/assignments", r.courseHandler.ListAssignments)
+	mux.HandleFunc("GET /store-api/assignments/{id}", r.courseHandler.GetAssignment)
+	mux.HandleFunc("GET /store-api/assignments/{id}/submissions", r.courseHandler.ListSubmissions)
+	mux.HandleFunc("GET /store-api/files/{id}/scope", r.courseHandler.GetScope)
 
 	// Swagger docs
 	mux.HandleFunc("GET /store-api/docs/", r.docsHandler.Docs)
@@ -0,0 +1,13 @@
+package broker
+
+import (
+	"context"
+
+	"filestoringservice/internal/domain/outbox"
+)
+
+// Publisher defines the interface for delivering outbox events to the other services.
+// A nil error means the event was accepted and is removed from the outbox.
+type Publisher interface {
+	Publish(ctx context.Context, event *outbox.Event) error
+}
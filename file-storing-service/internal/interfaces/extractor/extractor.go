@@ -0,0 +1,18 @@
+package extractor
+
+import (
+	"context"
+	"io"
+)
+
+// Extractor defines the interface for extracting plain text from documents of specific formats
+type Extractor interface {
+	ContentTypes() []string
+	Extract(ctx context.Context, data io.ReaderAt, size int64) (string, error)
+}
+
+// TextExtractor defines the interface for detecting document formats and producing plain-text renditions
+type TextExtractor interface {
+	DetectContentType(declared string, data io.ReaderAt, size int64) (string, error)
+	ExtractText(ctx context.Context, contentType string, data io.ReaderAt, size int64) (string, error)
+}
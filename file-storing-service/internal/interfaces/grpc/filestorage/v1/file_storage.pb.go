@@ -0,0 +1,753 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v5.29.3
+// source: filestorage/v1/file_storage.proto
+
+package filestoragev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Rendition is the form of the content to download
+type Rendition int32
+
+const (
+	// The original content
+	Rendition_RENDITION_UNSPECIFIED Rendition = 0
+	Rendition_RENDITION_ORIGINAL    Rendition = 1
+	// The normalized plain text extracted from the document
+	Rendition_RENDITION_TEXT Rendition = 2
+)
+
+// Enum value maps for Rendition.
+var (
+	Rendition_name = map[int32]string{
+		0: "RENDITION_UNSPECIFIED",
+		1: "RENDITION_ORIGINAL",
+		2: "RENDITION_TEXT",
+	}
+	Rendition_value = map[string]int32{
+		"RENDITION_UNSPECIFIED": 0,
+		"RENDITION_ORIGINAL":    1,
+		"RENDITION_TEXT":        2,
+	}
+)
+
+func (x Rendition) Enum() *Rendition {
+	p := new(Rendition)
+	*p = x
+	return p
+}
+
+func (x Rendition) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Rendition) Descriptor() protoreflect.EnumDescriptor {
+	return file_filestorage_v1_file_storage_proto_enumTypes[0].Descriptor()
+}
+
+func (Rendition) Type() protoreflect.EnumType {
+	return &file_filestorage_v1_file_storage_proto_enumTypes[0]
+}
+
+func (x Rendition) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Rendition.Descriptor instead.
+func (Rendition) EnumDescriptor() ([]byte, []int) {
+	return file_filestorage_v1_file_storage_proto_rawDescGZIP(), []int{0}
+}
+
+type FileChange_Kind int32
+
+const (
+	FileChange_KIND_UNSPECIFIED FileChange_Kind = 0
+	FileChange_KIND_CREATED     FileChange_Kind = 1
+	FileChange_KIND_DELETED     FileChange_Kind = 2
+)
+
+// Enum value maps for FileChange_Kind.
+var (
+	FileChange_Kind_name = map[int32]string{
+		0: "KIND_UNSPECIFIED",
+		1: "KIND_CREATED",
+		2: "KIND_DELETED",
+	}
+	FileChange_Kind_value = map[string]int32{
+		"KIND_UNSPECIFIED": 0,
+		"KIND_CREATED":     1,
+		"KIND_DELETED":     2,
+	}
+)
+
+func (x FileChange_Kind) Enum() *FileChange_Kind {
+	p := new(FileChange_Kind)
+	*p = x
+	return p
+}
+
+func (x FileChange_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FileChange_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_filestorage_v1_file_storage_proto_enumTypes[1].Descriptor()
+}
+
+func (FileChange_Kind) Type() protoreflect.EnumType {
+	return &file_filestorage_v1_file_storage_proto_enumTypes[1]
+}
+
+func (x FileChange_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FileChange_Kind.Descriptor instead.
+func (FileChange_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_filestorage_v1_file_storage_proto_rawDescGZIP(), []int{7, 0}
+}
+
+type FileMetadata struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ContentType string                 `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Size        int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	Hash        string                 `protobuf:"bytes,5,opt,name=hash,proto3" json:"hash,omitempty"`
+	Uploader    string                 `protobuf:"bytes,6,opt,name=uploader,proto3" json:"uploader,omitempty"`
+	// Empty for files outside of courses
+	AssignmentId  string                 `protobuf:"bytes,7,opt,name=assignment_id,json=assignmentId,proto3" json:"assignment_id,omitempty"`
+	UploadedAt    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=uploaded_at,json=uploadedAt,proto3" json:"uploaded_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileMetadata) Reset() {
+	*x = FileMetadata{}
+	mi := &file_filestorage_v1_file_storage_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileMetadata) ProtoMessage() {}
+
+func (x *FileMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_filestorage_v1_file_storage_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileMetadata.ProtoReflect.Descriptor instead.
+func (*FileMetadata) Descriptor() ([]byte, []int) {
+	return file_filestorage_v1_file_storage_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FileMetadata) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileMetadata) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetUploader() string {
+	if x != nil {
+		return x.Uploader
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetAssignmentId() string {
+	if x != nil {
+		return x.AssignmentId
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetUploadedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UploadedAt
+	}
+	return nil
+}
+
+type GetMetadataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetadataRequest) Reset() {
+	*x = GetMetadataRequest{}
+	mi := &file_filestorage_v1_file_storage_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetadataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetadataRequest) ProtoMessage() {}
+
+func (x *GetMetadataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filestorage_v1_file_storage_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetadataRequest.ProtoReflect.Descriptor instead.
+func (*GetMetadataRequest) Descriptor() ([]byte, []int) {
+	return file_filestorage_v1_file_storage_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetMetadataRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DownloadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Rendition     Rendition              `protobuf:"varint,2,opt,name=rendition,proto3,enum=filestorage.v1.Rendition" json:"rendition,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadRequest) Reset() {
+	*x = DownloadRequest{}
+	mi := &file_filestorage_v1_file_storage_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadRequest) ProtoMessage() {}
+
+func (x *DownloadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filestorage_v1_file_storage_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadRequest.ProtoReflect.Descriptor instead.
+func (*DownloadRequest) Descriptor() ([]byte, []int) {
+	return file_filestorage_v1_file_storage_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DownloadRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DownloadRequest) GetRendition() Rendition {
+	if x != nil {
+		return x.Rendition
+	}
+	return Rendition_RENDITION_UNSPECIFIED
+}
+
+type DownloadResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*DownloadResponse_Metadata
+	//	*DownloadResponse_Chunk
+	Payload       isDownloadResponse_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadResponse) Reset() {
+	*x = DownloadResponse{}
+	mi := &file_filestorage_v1_file_storage_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadResponse) ProtoMessage() {}
+
+func (x *DownloadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filestorage_v1_file_storage_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadResponse.ProtoReflect.Descriptor instead.
+func (*DownloadResponse) Descriptor() ([]byte, []int) {
+	return file_filestorage_v1_file_storage_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DownloadResponse) GetPayload() isDownloadResponse_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *DownloadResponse) GetMetadata() *FileMetadata {
+	if x != nil {
+		if x, ok := x.Payload.(*DownloadResponse_Metadata); ok {
+			return x.Metadata
+		}
+	}
+	return nil
+}
+
+func (x *DownloadResponse) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*DownloadResponse_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isDownloadResponse_Payload interface {
+	isDownloadResponse_Payload()
+}
+
+type DownloadResponse_Metadata struct {
+	Metadata *FileMetadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"`
+}
+
+type DownloadResponse_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*DownloadResponse_Metadata) isDownloadResponse_Payload() {}
+
+func (*DownloadResponse_Chunk) isDownloadResponse_Payload() {}
+
+type GetScopeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// assignment, course, all or prior_years, empty means all
+	Scope         string `protobuf:"bytes,2,opt,name=scope,proto3" json:"scope,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScopeRequest) Reset() {
+	*x = GetScopeRequest{}
+	mi := &file_filestorage_v1_file_storage_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScopeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScopeRequest) ProtoMessage() {}
+
+func (x *GetScopeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filestorage_v1_file_storage_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScopeRequest.ProtoReflect.Descriptor instead.
+func (*GetScopeRequest) Descriptor() ([]byte, []int) {
+	return file_filestorage_v1_file_storage_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetScopeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetScopeRequest) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+type Scope struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty if the file is not submitted to an assignment
+	AssignmentId string `protobuf:"bytes,1,opt,name=assignment_id,json=assignmentId,proto3" json:"assignment_id,omitempty"`
+	CourseId     string `protobuf:"bytes,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	// Other files of the scope, empty if all_files is set
+	FileIds       []string `protobuf:"bytes,3,rep,name=file_ids,json=fileIds,proto3" json:"file_ids,omitempty"`
+	AllFiles      bool     `protobuf:"varint,4,opt,name=all_files,json=allFiles,proto3" json:"all_files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Scope) Reset() {
+	*x = Scope{}
+	mi := &file_filestorage_v1_file_storage_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Scope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Scope) ProtoMessage() {}
+
+func (x *Scope) ProtoReflect() protoreflect.Message {
+	mi := &file_filestorage_v1_file_storage_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Scope.ProtoReflect.Descriptor instead.
+func (*Scope) Descriptor() ([]byte, []int) {
+	return file_filestorage_v1_file_storage_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Scope) GetAssignmentId() string {
+	if x != nil {
+		return x.AssignmentId
+	}
+	return ""
+}
+
+func (x *Scope) GetCourseId() string {
+	if x != nil {
+		return x.CourseId
+	}
+	return ""
+}
+
+func (x *Scope) GetFileIds() []string {
+	if x != nil {
+		return x.FileIds
+	}
+	return nil
+}
+
+func (x *Scope) GetAllFiles() bool {
+	if x != nil {
+		return x.AllFiles
+	}
+	return false
+}
+
+type WatchChangesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchChangesRequest) Reset() {
+	*x = WatchChangesRequest{}
+	mi := &file_filestorage_v1_file_storage_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchChangesRequest) ProtoMessage() {}
+
+func (x *WatchChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filestorage_v1_file_storage_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchChangesRequest.ProtoReflect.Descriptor instead.
+func (*WatchChangesRequest) Descriptor() ([]byte, []int) {
+	return file_filestorage_v1_file_storage_proto_rawDescGZIP(), []int{6}
+}
+
+type FileChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          FileChange_Kind        `protobuf:"varint,1,opt,name=kind,proto3,enum=filestorage.v1.FileChange_Kind" json:"kind,omitempty"`
+	File          *FileMetadata          `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileChange) Reset() {
+	*x = FileChange{}
+	mi := &file_filestorage_v1_file_storage_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileChange) ProtoMessage() {}
+
+func (x *FileChange) ProtoReflect() protoreflect.Message {
+	mi := &file_filestorage_v1_file_storage_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileChange.ProtoReflect.Descriptor instead.
+func (*FileChange) Descriptor() ([]byte, []int) {
+	return file_filestorage_v1_file_storage_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FileChange) GetKind() FileChange_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return FileChange_KIND_UNSPECIFIED
+}
+
+func (x *FileChange) GetFile() *FileMetadata {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+func (x *FileChange) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+var File_filestorage_v1_file_storage_proto protoreflect.FileDescriptor
+
+const file_filestorage_v1_file_storage_proto_rawDesc = "" +
+	"\n" +
+	"!filestorage/v1/file_storage.proto\x12\x0efilestorage.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xfb\x01\n" +
+	"\fFileMetadata\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x12\n" +
+	"\x04hash\x18\x05 \x01(\tR\x04hash\x12\x1a\n" +
+	"\buploader\x18\x06 \x01(\tR\buploader\x12#\n" +
+	"\rassignment_id\x18\a \x01(\tR\fassignmentId\x12;\n" +
+	"\vuploaded_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"uploadedAt\"$\n" +
+	"\x12GetMetadataRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"Z\n" +
+	"\x0fDownloadRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x127\n" +
+	"\trendition\x18\x02 \x01(\x0e2\x19.filestorage.v1.RenditionR\trendition\"q\n" +
+	"\x10DownloadResponse\x12:\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x1c.filestorage.v1.FileMetadataH\x00R\bmetadata\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\t\n" +
+	"\apayload\"7\n" +
+	"\x0fGetScopeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05scope\x18\x02 \x01(\tR\x05scope\"\x81\x01\n" +
+	"\x05Scope\x12#\n" +
+	"\rassignment_id\x18\x01 \x01(\tR\fassignmentId\x12\x1b\n" +
+	"\tcourse_id\x18\x02 \x01(\tR\bcourseId\x12\x19\n" +
+	"\bfile_ids\x18\x03 \x03(\tR\afileIds\x12\x1b\n" +
+	"\tall_files\x18\x04 \x01(\bR\ballFiles\"\x15\n" +
+	"\x13WatchChangesRequest\"\xf2\x01\n" +
+	"\n" +
+	"FileChange\x123\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x1f.filestorage.v1.FileChange.KindR\x04kind\x120\n" +
+	"\x04file\x18\x02 \x01(\v2\x1c.filestorage.v1.FileMetadataR\x04file\x12;\n" +
+	"\voccurred_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\"@\n" +
+	"\x04Kind\x12\x14\n" +
+	"\x10KIND_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fKIND_CREATED\x10\x01\x12\x10\n" +
+	"\fKIND_DELETED\x10\x02*R\n" +
+	"\tRendition\x12\x19\n" +
+	"\x15RENDITION_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12RENDITION_ORIGINAL\x10\x01\x12\x12\n" +
+	"\x0eRENDITION_TEXT\x10\x022\xc6\x02\n" +
+	"\vFileStorage\x12O\n" +
+	"\vGetMetadata\x12\".filestorage.v1.GetMetadataRequest\x1a\x1c.filestorage.v1.FileMetadata\x12O\n" +
+	"\bDownload\x12\x1f.filestorage.v1.DownloadRequest\x1a .filestorage.v1.DownloadResponse0\x01\x12B\n" +
+	"\bGetScope\x12\x1f.filestorage.v1.GetScopeRequest\x1a\x15.filestorage.v1.Scope\x12Q\n" +
+	"\fWatchChanges\x12#.filestorage.v1.WatchChangesRequest\x1a\x1a.filestorage.v1.FileChange0\x01b\x06proto3"
+
+var (
+	file_filestorage_v1_file_storage_proto_rawDescOnce sync.Once
+	file_filestorage_v1_file_storage_proto_rawDescData []byte
+)
+
+func file_filestorage_v1_file_storage_proto_rawDescGZIP() []byte {
+	file_filestorage_v1_file_storage_proto_rawDescOnce.Do(func() {
+		file_filestorage_v1_file_storage_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_filestorage_v1_file_storage_proto_rawDesc), len(file_filestorage_v1_file_storage_proto_rawDesc)))
+	})
+	return file_filestorage_v1_file_storage_proto_rawDescData
+}
+
+var file_filestorage_v1_file_storage_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_filestorage_v1_file_storage_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_filestorage_v1_file_storage_proto_goTypes = []any{
+	(Rendition)(0),                // 0: filestorage.v1.Rendition
+	(FileChange_Kind)(0),          // 1: filestorage.v1.FileChange.Kind
+	(*FileMetadata)(nil),          // 2: filestorage.v1.FileMetadata
+	(*GetMetadataRequest)(nil),    // 3: filestorage.v1.GetMetadataRequest
+	(*DownloadRequest)(nil),       // 4: filestorage.v1.DownloadRequest
+	(*DownloadResponse)(nil),      // 5: filestorage.v1.DownloadResponse
+	(*GetScopeRequest)(nil),       // 6: filestorage.v1.GetScopeRequest
+	(*Scope)(nil),                 // 7: filestorage.v1.Scope
+	(*WatchChangesRequest)(nil),   // 8: filestorage.v1.WatchChangesRequest
+	(*FileChange)(nil),            // 9: filestorage.v1.FileChange
+	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
+}
+var file_filestorage_v1_file_storage_proto_depIdxs = []int32{
+	10, // 0: filestorage.v1.FileMetadata.uploaded_at:type_name -> google.protobuf.Timestamp
+	0,  // 1: filestorage.v1.DownloadRequest.rendition:type_name -> filestorage.v1.Rendition
+	2,  // 2: filestorage.v1.DownloadResponse.metadata:type_name -> filestorage.v1.FileMetadata
+	1,  // 3: filestorage.v1.FileChange.kind:type_name -> filestorage.v1.FileChange.Kind
+	2,  // 4: filestorage.v1.FileChange.file:type_name -> filestorage.v1.FileMetadata
+	10, // 5: filestorage.v1.FileChange.occurred_at:type_name -> google.protobuf.Timestamp
+	3,  // 6: filestorage.v1.FileStorage.GetMetadata:input_type -> filestorage.v1.GetMetadataRequest
+	4,  // 7: filestorage.v1.FileStorage.Download:input_type -> filestorage.v1.DownloadRequest
+	6,  // 8: filestorage.v1.FileStorage.GetScope:input_type -> filestorage.v1.GetScopeRequest
+	8,  // 9: filestorage.v1.FileStorage.WatchChanges:input_type -> filestorage.v1.WatchChangesRequest
+	2,  // 10: filestorage.v1.FileStorage.GetMetadata:output_type -> filestorage.v1.FileMetadata
+	5,  // 11: filestorage.v1.FileStorage.Download:output_type -> filestorage.v1.DownloadResponse
+	7,  // 12: filestorage.v1.FileStorage.GetScope:output_type -> filestorage.v1.Scope
+	9,  // 13: filestorage.v1.FileStorage.WatchChanges:output_type -> filestorage.v1.FileChange
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_filestorage_v1_file_storage_proto_init() }
+func file_filestorage_v1_file_storage_proto_init() {
+	if File_filestorage_v1_file_storage_proto != nil {
+		return
+	}
+	file_filestorage_v1_file_storage_proto_msgTypes[3].OneofWrappers = []any{
+		(*DownloadResponse_Metadata)(nil),
+		(*DownloadResponse_Chunk)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_filestorage_v1_file_storage_proto_rawDesc), len(file_filestorage_v1_file_storage_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_filestorage_v1_file_storage_proto_goTypes,
+		DependencyIndexes: file_filestorage_v1_file_storage_proto_depIdxs,
+		EnumInfos:         file_filestorage_v1_file_storage_proto_enumTypes,
+		MessageInfos:      file_filestorage_v1_file_storage_proto_msgTypes,
+	}.Build()
+	File_filestorage_v1_file_storage_proto = out.File
+	file_filestorage_v1_file_storage_proto_goTypes = nil
+	file_filestorage_v1_file_storage_proto_depIdxs = nil
+}
@@ -0,0 +1,262 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: filestorage/v1/file_storage.proto
+
+package filestoragev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FileStorage_GetMetadata_FullMethodName  = "/filestorage.v1.FileStorage/GetMetadata"
+	FileStorage_Download_FullMethodName     = "/filestorage.v1.FileStorage/Download"
+	FileStorage_GetScope_FullMethodName     = "/filestorage.v1.FileStorage/GetScope"
+	FileStorage_WatchChanges_FullMethodName = "/filestorage.v1.FileStorage/WatchChanges"
+)
+
+// FileStorageClient is the client API for FileStorage service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FileStorage is the internal API of file-storing-service used by the other services.
+// The REST API stays for external clients.
+type FileStorageClient interface {
+	// GetMetadata returns the metadata of a file, NOT_FOUND if it doesn't exist
+	GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*FileMetadata, error)
+	// Download streams the content of a file. The first message carries the metadata,
+	// the following ones the content in chunks.
+	Download(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DownloadResponse], error)
+	// GetScope lists the files a submitted file is checked against. FAILED_PRECONDITION is returned
+	// for scopes narrower than all files if the file is not submitted to an assignment.
+	GetScope(ctx context.Context, in *GetScopeRequest, opts ...grpc.CallOption) (*Scope, error)
+	// WatchChanges streams the files uploaded and deleted after the call until the client cancels it.
+	// A client that doesn't keep up is disconnected with RESOURCE_EXHAUSTED.
+	WatchChanges(ctx context.Context, in *WatchChangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChange], error)
+}
+
+type fileStorageClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFileStorageClient(cc grpc.ClientConnInterface) FileStorageClient {
+	return &fileStorageClient{cc}
+}
+
+func (c *fileStorageClient) GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*FileMetadata, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileMetadata)
+	err := c.cc.Invoke(ctx, FileStorage_GetMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileStorageClient) Download(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DownloadResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FileStorage_ServiceDesc.Streams[0], FileStorage_Download_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadRequest, DownloadResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileStorage_DownloadClient = grpc.ServerStreamingClient[DownloadResponse]
+
+func (c *fileStorageClient) GetScope(ctx context.Context, in *GetScopeRequest, opts ...grpc.CallOption) (*Scope, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Scope)
+	err := c.cc.Invoke(ctx, FileStorage_GetScope_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileStorageClient) WatchChanges(ctx context.Context, in *WatchChangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileChange], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FileStorage_ServiceDesc.Streams[1], FileStorage_WatchChanges_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchChangesRequest, FileChange]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileStorage_WatchChangesClient = grpc.ServerStreamingClient[FileChange]
+
+// FileStorageServer is the server API for FileStorage service.
+// All implementations must embed UnimplementedFileStorageServer
+// for forward compatibility.
+//
+// FileStorage is the internal API of file-storing-service used by the other services.
+// The REST API stays for external clients.
+type FileStorageServer interface {
+	// GetMetadata returns the metadata of a file, NOT_FOUND if it doesn't exist
+	GetMetadata(context.Context, *GetMetadataRequest) (*FileMetadata, error)
+	// Download streams the content of a file. The first message carries the metadata,
+	// the following ones the content in chunks.
+	Download(*DownloadRequest, grpc.ServerStreamingServer[DownloadResponse]) error
+	// GetScope lists the files a submitted file is checked against. FAILED_PRECONDITION is returned
+	// for scopes narrower than all files if the file is not submitted to an assignment.
+	GetScope(context.Context, *GetScopeRequest) (*Scope, error)
+	// WatchChanges streams the files uploaded and deleted after the call until the client cancels it.
+	// A client that doesn't keep up is disconnected with RESOURCE_EXHAUSTED.
+	WatchChanges(*WatchChangesRequest, grpc.ServerStreamingServer[FileChange]) error
+	mustEmbedUnimplementedFileStorageServer()
+}
+
+// UnimplementedFileStorageServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFileStorageServer struct{}
+
+func (UnimplementedFileStorageServer) GetMetadata(context.Context, *GetMetadataRequest) (*FileMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetadata not implemented")
+}
+func (UnimplementedFileStorageServer) Download(*DownloadRequest, grpc.ServerStreamingServer[DownloadResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Download not implemented")
+}
+func (UnimplementedFileStorageServer) GetScope(context.Context, *GetScopeRequest) (*Scope, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScope not implemented")
+}
+func (UnimplementedFileStorageServer) WatchChanges(*WatchChangesRequest, grpc.ServerStreamingServer[FileChange]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchChanges not implemented")
+}
+func (UnimplementedFileStorageServer) mustEmbedUnimplementedFileStorageServer() {}
+func (UnimplementedFileStorageServer) testEmbeddedByValue()                     {}
+
+// UnsafeFileStorageServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FileStorageServer will
+// result in compilation errors.
+type UnsafeFileStorageServer interface {
+	mustEmbedUnimplementedFileStorageServer()
+}
+
+func RegisterFileStorageServer(s grpc.ServiceRegistrar, srv FileStorageServer) {
+	// If the following call pancis, it indicates UnimplementedFileStorageServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FileStorage_ServiceDesc, srv)
+}
+
+func _FileStorage_GetMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileStorageServer).GetMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileStorage_GetMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileStorageServer).GetMetadata(ctx, req.(*GetMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileStorage_Download_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileStorageServer).Download(m, &grpc.GenericServerStream[DownloadRequest, DownloadResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileStorage_DownloadServer = grpc.ServerStreamingServer[DownloadResponse]
+
+func _FileStorage_GetScope_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScopeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileStorageServer).GetScope(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileStorage_GetScope_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileStorageServer).GetScope(ctx, req.(*GetScopeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileStorage_WatchChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileStorageServer).WatchChanges(m, &grpc.GenericServerStream[WatchChangesRequest, FileChange]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileStorage_WatchChangesServer = grpc.ServerStreamingServer[FileChange]
+
+// FileStorage_ServiceDesc is the grpc.ServiceDesc for FileStorage service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FileStorage_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "filestorage.v1.FileStorage",
+	HandlerType: (*FileStorageServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMetadata",
+			Handler:    _FileStorage_GetMetadata_Handler,
+		},
+		{
+			MethodName: "GetScope",
+			Handler:    _FileStorage_GetScope_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Download",
+			Handler:       _FileStorage_Download_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchChanges",
+			Handler:       _FileStorage_WatchChanges_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "filestorage/v1/file_storage.proto",
+}
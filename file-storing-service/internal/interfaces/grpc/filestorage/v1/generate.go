@@ -0,0 +1,4 @@
+// Package filestoragev1 is the generated gRPC API of file-storing-service, see api/proto/filestorage/v1
+package filestoragev1
+
+//go:generate protoc -I ../../../../../api/proto --go_out=../.. --go_opt=paths=source_relative,Mfilestorage/v1/file_storage.proto=filestoringservice/internal/interfaces/grpc/filestorage/v1;filestoragev1 --go-grpc_out=../.. --go-grpc_opt=paths=source_relative,Mfilestorage/v1/file_storage.proto=filestoringservice/internal/interfaces/grpc/filestorage/v1;filestoragev1 filestorage/v1/file_storage.proto
@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"filestoringservice/internal/application/service"
+	"filestoringservice/internal/domain/course"
+	"filestoringservice/internal/domain/file"
+	pb "filestoringservice/internal/interfaces/grpc/filestorage/v1"
+)
+
+// downloadChunkSize is the size of the content chunks streamed by Download
+const downloadChunkSize = 64 << 10
+
+// FileStorageServer implements the gRPC API used by the other services
+type FileStorageServer struct {
+	pb.UnimplementedFileStorageServer
+
+	fileService   *service.FileService
+	courseService *service.CourseService
+	changeFeed    *service.ChangeFeed
+}
+
+func NewFileStorageServer(fileService *service.FileService, courseService *service.CourseService, changeFeed *service.ChangeFeed) *FileStorageServer {
+	return &FileStorageServer{
+		fileService:   fileService,
+		courseService: courseService,
+		changeFeed:    changeFeed,
+	}
+}
+
+// NewServer creates the gRPC server with the API registered
+func NewServer(fileStorageServer *FileStorageServer) *grpc.Server {
+	server := grpc.NewServer()
+	pb.RegisterFileStorageServer(server, fileStorageServer)
+
+	return server
+}
+
+func (s *FileStorageServer) GetMetadata(ctx context.Context, req *pb.GetMetadataRequest) (*pb.FileMetadata, error) {
+	fileModel, err := s.fileService.GetFileByID(ctx, req.GetId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if fileModel == nil {
+		return nil, status.Errorf(codes.NotFound, "file %s not found", req.GetId())
+	}
+
+	return toMetadata(fileModel), nil
+}
+
+func (s *FileStorageServer) Download(req *pb.DownloadRequest, stream grpc.ServerStreamingServer[pb.DownloadResponse]) error {
+	ctx := stream.Context()
+
+	var reader io.ReadCloser
+	var fileModel *file.File
+	var err error
+	switch req.GetRendition() {
+	case pb.Rendition_RENDITION_UNSPECIFIED, pb.Rendition_RENDITION_ORIGINAL:
+		reader, fileModel, err = s.fileService.DownloadFile(ctx, req.GetId())
+	case pb.Rendition_RENDITION_TEXT:
+		reader, fileModel, err = s.fileService.DownloadText(ctx, req.GetId())
+	default:
+		return status.Errorf(codes.InvalidArgument, "unknown rendition %v", req.GetRendition())
+	}
+	if err != nil {
+		return statusError(err)
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			log.Printf("failed to close file %s: %v", req.GetId(), err)
+		}
+	}()
+
+	err = stream.Send(&pb.DownloadResponse{Payload: &pb.DownloadResponse_Metadata{Metadata: toMetadata(fileModel)}})
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, downloadChunkSize)
+	for {
+		n, err := io.ReadFull(reader, buf)
+		if n > 0 {
+			sendErr := stream.Send(&pb.DownloadResponse{Payload: &pb.DownloadResponse_Chunk{Chunk: buf[:n]}})
+			if sendErr != nil {
+				return sendErr
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read file: %v", err)
+		}
+	}
+}
+
+func (s *FileStorageServer) GetScope(ctx context.Context, req *pb.GetScopeRequest) (*pb.Scope, error) {
+	scope, err := course.ParseScope(req.GetScope())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	scopeFiles, err := s.courseService.ResolveScope(ctx, req.GetId(), scope)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if scopeFiles == nil {
+		return nil, status.Errorf(codes.NotFound, "file %s not found", req.GetId())
+	}
+
+	return &pb.Scope{
+		AssignmentId: scopeFiles.AssignmentID,
+		CourseId:     scopeFiles.CourseID,
+		FileIds:      scopeFiles.FileIDs,
+		AllFiles:     scopeFiles.FileIDs == nil,
+	}, nil
+}
+
+func (s *FileStorageServer) WatchChanges(_ *pb.WatchChangesRequest, stream grpc.ServerStreamingServer[pb.FileChange]) error {
+	changes, unsubscribe := s.changeFeed.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case change, ok := <-changes:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "client fell behind the changes")
+			}
+
+			err := stream.Send(&pb.FileChange{
+				Kind:       toChangeKind(change.Kind),
+				File:       toMetadata(&change.File),
+				OccurredAt: timestamppb.New(change.OccurredAt),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func toMetadata(fileModel *file.File) *pb.FileMetadata {
+	return &pb.FileMetadata{
+		Id:           fileModel.ID,
+		Name:         fileModel.Name,
+		ContentType:  fileModel.ContentType,
+		Size:         fileModel.Size,
+		Hash:         fileModel.Hash,
+		Uploader:     fileModel.Uploader,
+		AssignmentId: fileModel.AssignmentID,
+		UploadedAt:   timestamppb.New(fileModel.UploadedAt),
+	}
+}
+
+func toChangeKind(kind file.ChangeKind) pb.FileChange_Kind {
+	switch kind {
+	case file.ChangeCreated:
+		return pb.FileChange_KIND_CREATED
+	case file.ChangeDeleted:
+		return pb.FileChange_KIND_DELETED
+	default:
+		return pb.FileChange_KIND_UNSPECIFIED
+	}
+}
+
+// statusError maps domain errors to gRPC status codes
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, file.ErrNotFound), errors.Is(err, course.ErrCourseNotFound), errors.Is(err, course.ErrAssignmentNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, course.ErrInvalidScope):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, course.ErrNotSubmitted):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
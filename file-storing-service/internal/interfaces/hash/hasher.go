@@ -9,4 +9,11 @@ import (
 type Hasher interface {
 	ComputeHash(ctx context.Context, data io.Reader) (string, error)
 	ComputeHashFromFile(ctx context.Context, filePath string) (string, error)
+	NewStreamHasher() StreamHasher
+}
+
+// StreamHasher computes a hash incrementally as data is written to it
+type StreamHasher interface {
+	io.Writer
+	Sum() string
 }
@@ -2,14 +2,18 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"filestoringservice/internal/domain/file"
+	"filestoringservice/internal/domain/outbox"
 )
 
 // FileRepository defines the interface for file persistence operations
 type FileRepository interface {
-	Store(ctx context.Context, file *file.File) error
+	Store(ctx context.Context, file *file.File, events ...*outbox.Event) error
 	FindByID(ctx context.Context, id string) (*file.File, error)
-	FindByHash(ctx context.Context, hash string) (*file.File, error)
-	FindAll(ctx context.Context) ([]*file.File, error)
+	List(ctx context.Context, query file.ListQuery) (*file.Page, error)
+	MarkDeleted(ctx context.Context, id string, deletedAt time.Time) (*file.File, error)
+	FindPendingDeletions(ctx context.Context, before time.Time, limit int) ([]*file.File, error)
+	Delete(ctx context.Context, id string) error
 }
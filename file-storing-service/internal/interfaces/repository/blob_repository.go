@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"filestoringservice/internal/domain/blob"
+)
+
+// BlobRepository defines the interface for content blob persistence operations.
+// References are counted by FileRepository when files are stored and deleted.
+type BlobRepository interface {
+	Store(ctx context.Context, blob *blob.Blob) (bool, error)
+	FindByHash(ctx context.Context, hash string) (*blob.Blob, error)
+	FindReleased(ctx context.Context, limit int) ([]*blob.Blob, error)
+	Release(ctx context.Context, id string, now time.Time) error
+	Delete(ctx context.Context, id string) error
+}
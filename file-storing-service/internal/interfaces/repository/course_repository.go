@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"filestoringservice/internal/domain/course"
+)
+
+// CourseRepository defines the interface for course, assignment and submission persistence operations.
+// Submissions are files with an assignment, they are stored by FileRepository.
+type CourseRepository interface {
+	StoreCourse(ctx context.Context, c *course.Course) error
+	FindCourseByID(ctx context.Context, id string) (*course.Course, error)
+	ListCourses(ctx context.Context) ([]*course.Course, error)
+	StoreAssignment(ctx context.Context, assignment *course.Assignment) error
+	FindAssignmentByID(ctx context.Context, id string) (*course.Assignment, error)
+	ListAssignments(ctx context.Context, courseID string) ([]*course.Assignment, error)
+	ListSubmissions(ctx context.Context, assignmentID string) ([]*course.Submission, error)
+	// Files submitted to the assignment, to the course or to the courses of the same name in earlier years.
+	// Deleted files are skipped.
+	FindAssignmentFileIDs(ctx context.Context, assignmentID string) ([]string, error)
+	FindCourseFileIDs(ctx context.Context, courseID string) ([]string, error)
+	FindPriorYearsFileIDs(ctx context.Context, courseName string, year int) ([]string, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"filestoringservice/internal/domain/outbox"
+)
+
+// OutboxRepository defines the interface for the events waiting to be delivered.
+// Events are added by other repositories in the transaction of the change they report.
+type OutboxRepository interface {
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*outbox.Event, error)
+	Update(ctx context.Context, event *outbox.Event) error
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"filestoringservice/internal/domain/upload"
+)
+
+// UploadSessionRepository defines the interface for upload session persistence operations
+type UploadSessionRepository interface {
+	Store(ctx context.Context, session *upload.Session) error
+	Update(ctx context.Context, session *upload.Session) error
+	StorePart(ctx context.Context, sessionID string, part upload.Part) error
+	FindByID(ctx context.Context, id string) (*upload.Session, error)
+}